@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	aplOmitUUIDs bool
+	aplWrite     bool
+	aplInfile    string
+)
+
+var aplCmd = &cobra.Command{
+	Use:   "apl",
+	Short: "convert APL rotations between the text and json formats",
+	Long:  "convert APL rotations between the text format (.apl) and protojson (.apl.json)",
+}
+
+var aplParseCmd = &cobra.Command{
+	Use:   "parse [file.apl]",
+	Short: "parse a text APL and print it as json",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rotation, err := readAPLText(args[0])
+		if err != nil {
+			return err
+		}
+		output, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(rotation)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rotation: %w", err)
+		}
+		return writeAPLOutput(string(output)+"\n", "")
+	},
+}
+
+var aplPrintCmd = &cobra.Command{
+	Use:   "print [file.apl.json]",
+	Short: "print a json APL in the text format",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", args[0], err)
+		}
+		rotation := &proto.APLRotation{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, rotation); err != nil {
+			return fmt.Errorf("failed to parse %q: %w", args[0], err)
+		}
+		return writeAPLOutput(core.FormatAPLText(rotation, core.APLTextOptions{OmitUUIDs: aplOmitUUIDs}), "")
+	},
+}
+
+var aplFmtCmd = &cobra.Command{
+	Use:   "fmt [file.apl]...",
+	Short: "reformat text APLs",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, file := range args {
+			rotation, err := readAPLText(file)
+			if err != nil {
+				return err
+			}
+			output := core.FormatAPLText(rotation, core.APLTextOptions{OmitUUIDs: aplOmitUUIDs})
+			if err := writeAPLOutput(output, core.Ternary(aplWrite, file, "")); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var aplLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "print APL warnings for each player in a raid sim request",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(aplInfile)
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", aplInfile, err)
+		}
+		input := &proto.RaidSimRequest{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, input); err != nil {
+			return fmt.Errorf("failed to parse %q: %w", aplInfile, err)
+		}
+
+		result := core.ComputeStats(&proto.ComputeStatsRequest{Raid: input.Raid, Encounter: input.Encounter})
+		if result.ErrorResult != "" {
+			return fmt.Errorf("failed to compute stats: %s", result.ErrorResult)
+		}
+
+		for partyIdx, party := range result.RaidStats.Parties {
+			for playerIdx, playerStats := range party.Players {
+				if playerStats.RotationStats == nil {
+					continue
+				}
+				player := input.Raid.Parties[partyIdx].Players[playerIdx]
+				printAPLLint(player.Name, player.Rotation, playerStats.RotationStats)
+			}
+		}
+		return nil
+	},
+}
+
+func printAPLLint(name string, rotation *proto.APLRotation, stats *proto.APLStats) {
+	printValidations := func(location string, validations []*proto.APLValidation) {
+		for _, validation := range validations {
+			fmt.Printf("%s: %s: %s: %s\n", name, location, validation.LogLevel, validation.Validation)
+		}
+	}
+
+	for i, actionStats := range stats.PrepullActions {
+		printValidations(fmt.Sprintf("prepull #%d", i+1), actionStats.Validations)
+	}
+	for i, actionStats := range stats.PriorityList {
+		printValidations(fmt.Sprintf("action #%d", i+1), actionStats.Validations)
+	}
+	for _, uuidValidations := range stats.UuidValidations {
+		location := fmt.Sprintf("value %q", uuidValidations.Uuid.GetValue())
+		for i, item := range rotation.PriorityList {
+			if aplContainsUUID(item.ProtoReflect(), uuidValidations.Uuid.GetValue()) {
+				location = fmt.Sprintf("action #%d, %s", i+1, location)
+				break
+			}
+		}
+		printValidations(location, uuidValidations.Validations)
+	}
+}
+
+func aplContainsUUID(msg protoreflect.Message, uuid string) bool {
+	if value, ok := msg.Interface().(*proto.APLValue); ok && value.Uuid != nil && value.Uuid.Value == uuid {
+		return true
+	}
+
+	found := false
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		if fd.IsList() {
+			for i := 0; i < v.List().Len() && !found; i++ {
+				found = aplContainsUUID(v.List().Get(i).Message(), uuid)
+			}
+		} else {
+			found = aplContainsUUID(v.Message(), uuid)
+		}
+		return !found
+	})
+	return found
+}
+
+func init() {
+	aplPrintCmd.Flags().BoolVar(&aplOmitUUIDs, "omit-uuids", false, "drop value uuids from the output")
+	aplFmtCmd.Flags().BoolVar(&aplOmitUUIDs, "omit-uuids", false, "drop value uuids from the output")
+	aplFmtCmd.Flags().BoolVarP(&aplWrite, "write", "w", false, "write the result back to the source file instead of stdout")
+
+	aplLintCmd.Flags().StringVar(&aplInfile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
+
+	aplCmd.AddCommand(aplParseCmd)
+	aplCmd.AddCommand(aplPrintCmd)
+	aplCmd.AddCommand(aplFmtCmd)
+	aplCmd.AddCommand(aplLintCmd)
+}
+
+func readAPLText(file string) (*proto.APLRotation, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", file, err)
+	}
+	rotation, err := core.ParseAPLText(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return rotation, nil
+}
+
+// Writes to the given file, or stdout if empty.
+func writeAPLOutput(output string, file string) error {
+	if file == "" {
+		fmt.Print(output)
+		return nil
+	}
+	if err := os.WriteFile(file, []byte(output), 0666); err != nil {
+		return fmt.Errorf("failed to write %q: %w", file, err)
+	}
+	return nil
+}
@@ -19,6 +19,9 @@ var (
 	replacefile string
 	outfile     string
 	verbose     bool
+
+	checkpointFile string
+	resume         bool
 )
 
 var bulkCmd = &cobra.Command{
@@ -33,6 +36,8 @@ func init() {
 	bulkCmd.Flags().StringVar(&replacefile, "replacefile", "", "location of replacement items file. Writes a CSV result of the items replaced instead of JSON")
 	bulkCmd.Flags().StringVar(&outfile, "output", "", "location of output file, defaults to stdout")
 	bulkCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	bulkCmd.Flags().StringVar(&checkpointFile, "checkpoint", "", "location of a checkpoint file, which finished combos are saved to as the bulk sim runs")
+	bulkCmd.Flags().BoolVar(&resume, "resume", false, "skip combos already saved in the checkpoint file, e.g. after an interrupted run")
 	bulkCmd.MarkFlagRequired("infile")
 	bulkCmd.MarkFlagRequired("replacefile")
 }
@@ -96,8 +101,23 @@ func BulkSim(input *proto.RaidSimRequest, replaceFile string, verbose bool) stri
 			FastMode:           replaceInput.FastMode,
 		},
 	}
+
+	var checkpoint *core.BulkSimCheckpoint
+	if checkpointFile != "" {
+		checkpoint, err = core.OpenBulkSimCheckpoint(checkpointFile, bsr, resume)
+		if err != nil {
+			log.Fatalf("failed to open checkpoint file: %s", err)
+		}
+		defer checkpoint.Close()
+		if verbose && checkpoint.Len() > 0 {
+			fmt.Printf("Resuming from checkpoint with %d finished combos.\n", checkpoint.Len())
+		}
+	} else if resume {
+		log.Fatalf("--resume requires --checkpoint")
+	}
+
 	progress := make(chan *proto.ProgressMetrics, 100)
-	core.RunBulkSimAsync(bsr, progress, "cmd-bulk-sim")
+	core.RunBulkSimWithCheckpointAsync(bsr, progress, "cmd-bulk-sim", checkpoint)
 
 	startTime := time.Now()
 
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	// Raid sims are split into this many chunks per worker, so faster workers can take more of them.
+	chunksPerWorker = 4
+	// A chunk is given up on after failing on this many workers.
+	maxChunkAttempts = 3
+	// A worker is dropped from the cluster after failing this many times in a row.
+	maxWorkerFailures = 3
+	// How long a worker sits out after a failure before it is sent another chunk.
+	workerFailureBackoff = 5 * time.Second
+)
+
+var errNoWorkers = errors.New("no workers left in the cluster")
+
+type simWorker struct {
+	address  string
+	failures int // In a row, reset by a success.
+}
+
+// simCluster runs raid sims on `wowsimcli worker` processes. Sims are split by iterations into
+// chunks which workers take as they become free, and the chunk results are combined the same way
+// as the results of threads within one process. A chunk which fails on a worker, e.g. because the
+// machine went away or it stalled for longer than the chunk timeout, is retried on another one.
+type simCluster struct {
+	client       *http.Client
+	chunkTimeout time.Duration
+
+	mu        sync.Mutex
+	free      chan *simWorker
+	live      int
+	noWorkers chan struct{} // Closed once every worker has been dropped.
+}
+
+func newSimCluster(addresses []string, chunkTimeout time.Duration) *simCluster {
+	c := &simCluster{
+		client:       &http.Client{},
+		chunkTimeout: chunkTimeout,
+		free:         make(chan *simWorker, len(addresses)),
+		live:         len(addresses),
+		noWorkers:    make(chan struct{}),
+	}
+	for _, address := range addresses {
+		if !strings.Contains(address, "://") {
+			address = "http://" + address
+		}
+		c.free <- &simWorker{address: strings.TrimSuffix(address, "/")}
+	}
+	return c
+}
+
+// Number of sims which keep every worker busy.
+func (c *simCluster) concurrency() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return max(c.live, 1)
+}
+
+// Runs a job like runJob, but with its sims run on the cluster.
+func (c *simCluster) runJob(request *proto.JobRequest, reporter chan *proto.ProgressMetrics, jobId string) {
+	switch r := request.Request.(type) {
+	case *proto.JobRequest_RaidSim:
+		core.RunRaidSimWithSimFuncAsync(r.RaidSim, reporter, jobId, c.runSplitSim)
+	case *proto.JobRequest_BulkSim:
+		// Bulk combos are small, so each one goes to a single worker and the workers sim different
+		// combos in parallel instead.
+		core.RunBulkSimWithOptionsAsync(r.BulkSim, reporter, jobId, core.BulkSimOptions{
+			SimFunc:     c.runWholeSim,
+			Concurrency: c.concurrency() * chunksPerWorker,
+		})
+	case *proto.JobRequest_StatWeights:
+		core.StatWeightsWithSimFuncAsync(r.StatWeights, reporter, jobId, c.runSplitSim)
+	}
+}
+
+// Splits the sim across all workers. Matches core.RaidSimFunc.
+func (c *simCluster) runSplitSim(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
+	return c.runSim(request, progress, signals, int32(c.concurrency()*chunksPerWorker))
+}
+
+// Sims the whole request on one worker. Matches core.RaidSimFunc.
+func (c *simCluster) runWholeSim(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
+	return c.runSim(request, progress, signals, 1)
+}
+
+func (c *simCluster) runSim(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals, numChunks int32) *proto.RaidSimResult {
+	result := c.runChunks(request, progress, signals, numChunks)
+	if progress != nil {
+		progress <- &proto.ProgressMetrics{
+			TotalIterations:     request.SimOptions.Iterations,
+			CompletedIterations: result.IterationsDone,
+			FinalRaidResult:     result,
+		}
+		close(progress)
+	}
+	return result
+}
+
+func (c *simCluster) runChunks(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals, numChunks int32) *proto.RaidSimResult {
+	split := core.SplitSimRequestForConcurrency(request, numChunks)
+	if split.ErrorResult != "" {
+		return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: split.ErrorResult}}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Abort signals have no channel to wait on, so poll them.
+	go func() {
+		for ctx.Err() == nil {
+			if signals.Abort.IsTriggered() {
+				cancel()
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+
+	type chunkResult struct {
+		idx    int
+		result *proto.RaidSimResult
+		err    error
+	}
+	chunkResults := make(chan chunkResult, len(split.Requests))
+	for i, chunk := range split.Requests {
+		go func() {
+			result, err := c.runChunk(ctx, chunk)
+			chunkResults <- chunkResult{idx: i, result: result, err: err}
+		}()
+	}
+
+	results := make([]*proto.RaidSimResult, len(split.Requests))
+	var iterationsDone int32
+	for range split.Requests {
+		chunk := <-chunkResults
+		if signals.Abort.IsTriggered() {
+			return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted}}
+		}
+		if chunk.err != nil {
+			return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: chunk.err.Error()}}
+		}
+		if chunk.result.Error != nil {
+			return chunk.result
+		}
+		results[chunk.idx] = chunk.result
+
+		iterationsDone += chunk.result.IterationsDone
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations:     request.SimOptions.Iterations,
+				CompletedIterations: iterationsDone,
+			}
+		}
+	}
+	return core.CombineConcurrentSimResults(results, request.SimOptions.Debug)
+}
+
+// Sims one chunk on the next free worker, moving on to another worker if it fails.
+func (c *simCluster) runChunk(ctx context.Context, request *proto.RaidSimRequest) (*proto.RaidSimResult, error) {
+	body, err := googleProto.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for range maxChunkAttempts {
+		var worker *simWorker
+		select {
+		case worker = <-c.free:
+		case <-c.noWorkers:
+			return nil, errNoWorkers
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		result, err := c.post(ctx, worker, body)
+		if ctx.Err() != nil {
+			c.release(worker, nil)
+			return nil, ctx.Err()
+		}
+		c.release(worker, err)
+		if err == nil {
+			return result, nil
+		}
+		log.Printf("Worker %s failed: %s", worker.address, err)
+		lastErr = err
+	}
+	return nil, fmt.Errorf("chunk failed on %d workers, last error: %w", maxChunkAttempts, lastErr)
+}
+
+// Posts a chunk to the worker, giving up once it takes longer than the chunk timeout so a stalled
+// worker counts as failed.
+func (c *simCluster) post(ctx context.Context, worker *simWorker, body []byte) (*proto.RaidSimResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.chunkTimeout)
+	defer cancel()
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, worker.address+"/raidSim", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/x-protobuf")
+
+	response, err := c.client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s: %s", response.Status, strings.TrimSpace(string(responseBody)))
+	}
+
+	result := &proto.RaidSimResult{}
+	if err := googleProto.Unmarshal(responseBody, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Hands a worker back after a chunk. A worker which failed sits out for a while, and is dropped
+// once it has failed too many times in a row.
+func (c *simCluster) release(worker *simWorker, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		worker.failures = 0
+		c.free <- worker
+		return
+	}
+
+	worker.failures++
+	if worker.failures >= maxWorkerFailures {
+		log.Printf("Dropping worker %s after %d failures in a row.", worker.address, worker.failures)
+		c.live--
+		if c.live == 0 {
+			close(c.noWorkers)
+		}
+		return
+	}
+	time.AfterFunc(workerFailureBackoff, func() {
+		c.free <- worker
+	})
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+func fakeWorkerSim(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
+	iterations := request.SimOptions.Iterations
+	dps := &proto.DistributionMetrics{
+		Avg:            1000,
+		Max:            1000,
+		Min:            1000,
+		Hist:           map[int32]int32{},
+		AggregatorData: &proto.AggregatorData{N: iterations, SumSq: 1000 * 1000 * float64(iterations)},
+	}
+	result := &proto.RaidSimResult{
+		RaidMetrics: &proto.RaidMetrics{
+			Dps: dps,
+			Hps: dps,
+			Parties: []*proto.PartyMetrics{{
+				Dps:     dps,
+				Hps:     dps,
+				Players: []*proto.UnitMetrics{{Dps: dps, Hps: dps, Threat: dps, Dtps: dps, Tmi: dps, Tto: dps}},
+			}},
+		},
+		EncounterMetrics: &proto.EncounterMetrics{},
+		IterationsDone:   iterations,
+	}
+	progress <- &proto.ProgressMetrics{CompletedIterations: iterations, FinalRaidResult: result}
+	close(progress)
+	return result
+}
+
+func TestSimCluster(t *testing.T) {
+	request := &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: 1001, RandomSeed: 1}}
+
+	t.Run("RetriesFailedWorkers", func(t *testing.T) {
+		var chunks atomic.Int32
+		good := httptest.NewServer(newWorkerServer(func(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
+			chunks.Add(1)
+			return fakeWorkerSim(request, progress, signals)
+		}))
+		defer good.Close()
+		broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "broken", http.StatusInternalServerError)
+		}))
+		defer broken.Close()
+		gone := httptest.NewServer(http.NotFoundHandler())
+		gone.Close()
+
+		cluster := newSimCluster([]string{broken.URL, gone.URL, good.URL}, time.Minute)
+		progress := make(chan *proto.ProgressMetrics, 100)
+		result := cluster.runSplitSim(request, progress, simsignals.CreateSignals())
+		if result.Error != nil {
+			t.Fatalf("runSplitSim() returned error: %s", result.Error.Message)
+		}
+		if result.IterationsDone != 1001 {
+			t.Errorf("combined result has %d iterations, want 1001", result.IterationsDone)
+		}
+		if dps := result.RaidMetrics.Parties[0].Players[0].Dps.Avg; math.Abs(dps-1000) > 1e-9 {
+			t.Errorf("combined DPS = %f, want 1000", dps)
+		}
+		if got := chunks.Load(); got != 3*chunksPerWorker {
+			t.Errorf("good worker simmed %d chunks, want all %d", got, 3*chunksPerWorker)
+		}
+
+		var last *proto.ProgressMetrics
+		for metrics := range progress {
+			last = metrics
+		}
+		if last == nil || last.FinalRaidResult != result || last.CompletedIterations != 1001 {
+			t.Errorf("last progress = %v, want the final result", last)
+		}
+	})
+
+	t.Run("RetriesStalledWorkers", func(t *testing.T) {
+		unstall := make(chan struct{})
+		stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-unstall
+		}))
+		defer stalled.Close()
+		defer close(unstall)
+		good := httptest.NewServer(newWorkerServer(fakeWorkerSim))
+		defer good.Close()
+
+		cluster := newSimCluster([]string{stalled.URL, good.URL}, 50*time.Millisecond)
+		result := cluster.runWholeSim(request, nil, simsignals.CreateSignals())
+		if result.Error != nil {
+			t.Fatalf("runWholeSim() returned error: %s", result.Error.Message)
+		}
+		if result.IterationsDone != 1001 {
+			t.Errorf("result has %d iterations, want 1001", result.IterationsDone)
+		}
+	})
+
+	t.Run("AbortCancelsWorkers", func(t *testing.T) {
+		workerAborted := make(chan struct{})
+		worker := httptest.NewServer(newWorkerServer(func(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
+			for !signals.Abort.IsTriggered() {
+				time.Sleep(time.Millisecond)
+			}
+			close(workerAborted)
+			close(progress)
+			return nil
+		}))
+		defer worker.Close()
+
+		cluster := newSimCluster([]string{worker.URL}, time.Minute)
+		signals := simsignals.CreateSignals()
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			signals.Abort.Trigger()
+		}()
+		result := cluster.runWholeSim(request, nil, signals)
+		if result.Error == nil || result.Error.Type != proto.ErrorOutcomeType_ErrorOutcomeAborted {
+			t.Errorf("runWholeSim() = %v, want an aborted result", result)
+		}
+
+		select {
+		case <-workerAborted:
+		case <-time.After(5 * time.Second):
+			t.Errorf("worker sim was not aborted")
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	uuid "github.com/google/uuid"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+var (
+	errJobNotFound     = errors.New("job not found")
+	errEmptyJobRequest = errors.New("job has no request")
+)
+
+// Starts a job in the background, reporting its progress and final result to the reporter. Must
+// not block, since the queue holds its lock while starting jobs.
+type jobRunner func(request *proto.JobRequest, reporter chan *proto.ProgressMetrics, jobId string)
+
+// Runs jobs on the async sim APIs, using the job ID as the request ID for aborts.
+func runJob(request *proto.JobRequest, reporter chan *proto.ProgressMetrics, jobId string) {
+	switch r := request.Request.(type) {
+	case *proto.JobRequest_RaidSim:
+		core.RunRaidSimConcurrentAsync(r.RaidSim, reporter, jobId)
+	case *proto.JobRequest_BulkSim:
+		core.RunBulkSimAsync(r.BulkSim, reporter, jobId)
+	case *proto.JobRequest_StatWeights:
+		core.StatWeightsAsync(r.StatWeights, reporter, jobId)
+	}
+}
+
+// jobQueue runs sim jobs a limited number at a time, highest priority first. Every job is saved to
+// its own file in dir whenever its status changes, so queued jobs and finished results survive a
+// restart of the server.
+type jobQueue struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	dir      string
+	run      jobRunner
+	jobs     map[string]*proto.Job
+	queued   []*proto.Job // In submission order.
+	shutdown bool
+}
+
+// Loads the jobs saved in dir. Jobs which were queued or running when the server stopped are
+// queued again.
+func newJobQueue(dir string, run jobRunner) (*jobQueue, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	q := &jobQueue{
+		dir:  dir,
+		run:  run,
+		jobs: make(map[string]*proto.Job),
+	}
+	q.cond = sync.NewCond(&q.mu)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		job := &proto.Job{}
+		if err := protojson.Unmarshal(data, job); err != nil {
+			return nil, fmt.Errorf("failed to load job file %q: %w", file, err)
+		}
+		q.jobs[job.Info.Id] = job
+
+		if job.Info.Status == proto.JobStatus_JobStatusQueued || job.Info.Status == proto.JobStatus_JobStatusRunning {
+			job.Info.Status = proto.JobStatus_JobStatusQueued
+			job.Info.StartedAt = 0
+			job.Info.Progress = nil
+			if err := q.save(job); err != nil {
+				return nil, err
+			}
+			q.queued = append(q.queued, job)
+		}
+	}
+	slices.SortStableFunc(q.queued, func(a, b *proto.Job) int {
+		return cmp.Compare(a.Info.SubmittedAt, b.Info.SubmittedAt)
+	})
+	return q, nil
+}
+
+// Starts the goroutines which run jobs, at most maxJobs at once.
+func (q *jobQueue) start(maxJobs int) {
+	for range max(maxJobs, 1) {
+		go q.worker()
+	}
+}
+
+// Stops starting new jobs. Running jobs are left to finish.
+func (q *jobQueue) stop() {
+	q.mu.Lock()
+	q.shutdown = true
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+func (q *jobQueue) submit(request *proto.JobRequest) (*proto.JobInfo, error) {
+	if request.Request == nil {
+		return nil, errEmptyJobRequest
+	}
+
+	job := &proto.Job{
+		Info: &proto.JobInfo{
+			Id:          uuid.NewString(),
+			Name:        request.Name,
+			Status:      proto.JobStatus_JobStatusQueued,
+			Priority:    request.Priority,
+			SubmittedAt: time.Now().UnixMilli(),
+		},
+		Request: request,
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.save(job); err != nil {
+		return nil, err
+	}
+	q.jobs[job.Info.Id] = job
+	q.queued = append(q.queued, job)
+	q.cond.Signal()
+	return googleProto.Clone(job.Info).(*proto.JobInfo), nil
+}
+
+func (q *jobQueue) list() *proto.JobListResult {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	result := &proto.JobListResult{}
+	for _, job := range q.jobs {
+		result.Jobs = append(result.Jobs, googleProto.Clone(job.Info).(*proto.JobInfo))
+	}
+	slices.SortFunc(result.Jobs, func(a, b *proto.JobInfo) int {
+		return cmp.Or(cmp.Compare(a.SubmittedAt, b.SubmittedAt), strings.Compare(a.Id, b.Id))
+	})
+	return result
+}
+
+func (q *jobQueue) get(id string) (*proto.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, errJobNotFound
+	}
+	return googleProto.Clone(job).(*proto.Job), nil
+}
+
+// Removes a queued job from the queue, or aborts a running one. Finished jobs are left as they are.
+func (q *jobQueue) cancel(id string) (*proto.JobInfo, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, errJobNotFound
+	}
+
+	switch job.Info.Status {
+	case proto.JobStatus_JobStatusQueued:
+		q.queued = slices.DeleteFunc(q.queued, func(queuedJob *proto.Job) bool { return queuedJob == job })
+		job.Info.Status = proto.JobStatus_JobStatusCancelled
+		job.Info.FinishedAt = time.Now().UnixMilli()
+		if err := q.save(job); err != nil {
+			return nil, err
+		}
+	case proto.JobStatus_JobStatusRunning:
+		// The job becomes cancelled once the sim returns its aborted result.
+		simsignals.AbortById(id)
+	}
+	return googleProto.Clone(job.Info).(*proto.JobInfo), nil
+}
+
+// Deletes finished jobs which finished longer than retention ago.
+func (q *jobQueue) prune(retention time.Duration) {
+	cutoff := time.Now().Add(-retention).UnixMilli()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for id, job := range q.jobs {
+		if job.Info.FinishedAt == 0 || job.Info.FinishedAt > cutoff {
+			continue
+		}
+		if err := os.Remove(q.jobPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Failed to delete job %s: %s", id, err)
+			continue
+		}
+		delete(q.jobs, id)
+	}
+}
+
+func (q *jobQueue) worker() {
+	for {
+		q.mu.Lock()
+		for len(q.queued) == 0 && !q.shutdown {
+			q.cond.Wait()
+		}
+		if q.shutdown {
+			q.mu.Unlock()
+			return
+		}
+
+		// The first of the highest priority jobs, so equal priorities run in submission order.
+		next := 0
+		for i, job := range q.queued {
+			if job.Info.Priority > q.queued[next].Info.Priority {
+				next = i
+			}
+		}
+		job := q.queued[next]
+		q.queued = slices.Delete(q.queued, next, next+1)
+
+		job.Info.Status = proto.JobStatus_JobStatusRunning
+		job.Info.StartedAt = time.Now().UnixMilli()
+		if err := q.save(job); err != nil {
+			log.Printf("Failed to save job %s: %s", job.Info.Id, err)
+		}
+
+		// Started while holding the lock, so a cancel can't come in before the job has registered
+		// its abort signal.
+		reporter := make(chan *proto.ProgressMetrics, 100)
+		q.run(job.Request, reporter, job.Info.Id)
+		q.mu.Unlock()
+
+		q.waitForResult(job, reporter)
+	}
+}
+
+func (q *jobQueue) waitForResult(job *proto.Job, reporter chan *proto.ProgressMetrics) {
+	var errorOutcome *proto.ErrorOutcome
+	finished := false
+	for metrics := range reporter {
+		q.mu.Lock()
+		switch {
+		case metrics.FinalRaidResult != nil:
+			job.Result = &proto.Job_RaidSimResult{RaidSimResult: metrics.FinalRaidResult}
+			errorOutcome = metrics.FinalRaidResult.Error
+			finished = true
+		case metrics.FinalBulkResult != nil:
+			job.Result = &proto.Job_BulkSimResult{BulkSimResult: metrics.FinalBulkResult}
+			errorOutcome = metrics.FinalBulkResult.Error
+			finished = true
+		case metrics.FinalWeightResult != nil:
+			job.Result = &proto.Job_StatWeightsResult{StatWeightsResult: metrics.FinalWeightResult}
+			errorOutcome = metrics.FinalWeightResult.Error
+			finished = true
+		default:
+			job.Info.Progress = metrics
+		}
+		q.mu.Unlock()
+		if finished {
+			break
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job.Info.FinishedAt = time.Now().UnixMilli()
+	switch {
+	case !finished:
+		job.Info.Status = proto.JobStatus_JobStatusFailed
+		job.Info.Error = "sim finished without a result"
+	case errorOutcome == nil:
+		job.Info.Status = proto.JobStatus_JobStatusDone
+	case errorOutcome.Type == proto.ErrorOutcomeType_ErrorOutcomeAborted:
+		job.Info.Status = proto.JobStatus_JobStatusCancelled
+	default:
+		job.Info.Status = proto.JobStatus_JobStatusFailed
+		job.Info.Error = errorOutcome.Message
+	}
+	if err := q.save(job); err != nil {
+		log.Printf("Failed to save job %s: %s", job.Info.Id, err)
+	}
+}
+
+func (q *jobQueue) jobPath(id string) string {
+	return filepath.Join(q.dir, id+".json")
+}
+
+// Writes to a temporary file first, so a crash mid-write doesn't leave a truncated job file.
+func (q *jobQueue) save(job *proto.Job) error {
+	data, err := protojson.Marshal(job)
+	if err != nil {
+		return err
+	}
+	path := q.jobPath(job.Info.Id)
+	if err := os.WriteFile(path+".tmp", data, 0666); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+func waitForJobStatus(t *testing.T, queue *jobQueue, id string, status proto.JobStatus) *proto.Job {
+	t.Helper()
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		job, err := queue.get(id)
+		if err != nil {
+			t.Fatalf("get(%s) returned error: %s", id, err)
+		}
+		if job.Info.Status == status {
+			return job
+		}
+	}
+	t.Fatalf("job %s never reached status %s", id, status)
+	return nil
+}
+
+func TestJobQueue(t *testing.T) {
+	t.Run("PriorityAndPersistence", func(t *testing.T) {
+		dir := t.TempDir()
+		var mu sync.Mutex
+		var order []string
+		queue, err := newJobQueue(dir, func(request *proto.JobRequest, reporter chan *proto.ProgressMetrics, jobId string) {
+			mu.Lock()
+			order = append(order, request.Name)
+			mu.Unlock()
+			go func() {
+				reporter <- &proto.ProgressMetrics{CompletedIterations: 1, TotalIterations: 2}
+				reporter <- &proto.ProgressMetrics{FinalRaidResult: &proto.RaidSimResult{IterationsDone: 2}}
+			}()
+		})
+		if err != nil {
+			t.Fatalf("newJobQueue() returned error: %s", err)
+		}
+
+		var ids []string
+		for _, request := range []*proto.JobRequest{
+			{Name: "low", Request: &proto.JobRequest_RaidSim{RaidSim: &proto.RaidSimRequest{}}},
+			{Name: "high", Priority: 1, Request: &proto.JobRequest_RaidSim{RaidSim: &proto.RaidSimRequest{}}},
+			{Name: "low2", Request: &proto.JobRequest_RaidSim{RaidSim: &proto.RaidSimRequest{}}},
+		} {
+			info, err := queue.submit(request)
+			if err != nil {
+				t.Fatalf("submit() returned error: %s", err)
+			}
+			ids = append(ids, info.Id)
+		}
+		if _, err := queue.submit(&proto.JobRequest{Name: "empty"}); err != errEmptyJobRequest {
+			t.Errorf("submit() of an empty job returned %v, want %v", err, errEmptyJobRequest)
+		}
+
+		queue.start(1)
+		defer queue.stop()
+		for _, id := range ids {
+			waitForJobStatus(t, queue, id, proto.JobStatus_JobStatusDone)
+		}
+		mu.Lock()
+		if !slices.Equal(order, []string{"high", "low", "low2"}) {
+			t.Errorf("jobs ran in order %v, want [high low low2]", order)
+		}
+		mu.Unlock()
+
+		reloaded, err := newJobQueue(dir, runJob)
+		if err != nil {
+			t.Fatalf("newJobQueue() returned error when reloading: %s", err)
+		}
+		if jobs := reloaded.list().Jobs; len(jobs) != 3 {
+			t.Fatalf("reloaded %d jobs, want 3", len(jobs))
+		}
+		job, err := reloaded.get(ids[0])
+		if err != nil {
+			t.Fatalf("get() returned error after reloading: %s", err)
+		}
+		if job.Info.Status != proto.JobStatus_JobStatusDone || job.GetRaidSimResult().GetIterationsDone() != 2 {
+			t.Errorf("reloaded job = %v, want a finished job with its result", job)
+		}
+
+		reloaded.prune(0)
+		if jobs := reloaded.list().Jobs; len(jobs) != 0 {
+			t.Errorf("%d jobs left after pruning, want 0", len(jobs))
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		dir := t.TempDir()
+		queue, err := newJobQueue(dir, func(request *proto.JobRequest, reporter chan *proto.ProgressMetrics, jobId string) {
+			signals, err := simsignals.RegisterWithId(jobId)
+			if err != nil {
+				t.Errorf("RegisterWithId() returned error: %s", err)
+				return
+			}
+			go func() {
+				defer simsignals.UnregisterId(jobId)
+				for !signals.Abort.IsTriggered() {
+					time.Sleep(time.Millisecond)
+				}
+				reporter <- &proto.ProgressMetrics{FinalRaidResult: &proto.RaidSimResult{
+					Error: &proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted},
+				}}
+			}()
+		})
+		if err != nil {
+			t.Fatalf("newJobQueue() returned error: %s", err)
+		}
+		queue.start(1)
+		defer queue.stop()
+
+		running, _ := queue.submit(&proto.JobRequest{Request: &proto.JobRequest_RaidSim{RaidSim: &proto.RaidSimRequest{}}})
+		waitForJobStatus(t, queue, running.Id, proto.JobStatus_JobStatusRunning)
+		queued, _ := queue.submit(&proto.JobRequest{Request: &proto.JobRequest_RaidSim{RaidSim: &proto.RaidSimRequest{}}})
+
+		info, err := queue.cancel(queued.Id)
+		if err != nil {
+			t.Fatalf("cancel() returned error: %s", err)
+		}
+		if info.Status != proto.JobStatus_JobStatusCancelled {
+			t.Errorf("queued job status after cancel = %s, want cancelled", info.Status)
+		}
+
+		if _, err := queue.cancel(running.Id); err != nil {
+			t.Fatalf("cancel() returned error: %s", err)
+		}
+		waitForJobStatus(t, queue, running.Id, proto.JobStatus_JobStatusCancelled)
+
+		if _, err := queue.cancel("missing"); err != errJobNotFound {
+			t.Errorf("cancel() of a missing job returned %v, want %v", err, errJobNotFound)
+		}
+	})
+
+	t.Run("RequeueOnRestart", func(t *testing.T) {
+		dir := t.TempDir()
+		queue, err := newJobQueue(dir, runJob)
+		if err != nil {
+			t.Fatalf("newJobQueue() returned error: %s", err)
+		}
+		info, _ := queue.submit(&proto.JobRequest{Request: &proto.JobRequest_RaidSim{RaidSim: &proto.RaidSimRequest{}}})
+
+		// Saved as running, as if the server stopped while the job was running.
+		job := queue.jobs[info.Id]
+		job.Info.Status = proto.JobStatus_JobStatusRunning
+		job.Info.StartedAt = 1
+		if err := queue.save(job); err != nil {
+			t.Fatalf("save() returned error: %s", err)
+		}
+
+		reloaded, err := newJobQueue(dir, runJob)
+		if err != nil {
+			t.Fatalf("newJobQueue() returned error when reloading: %s", err)
+		}
+		if len(reloaded.queued) != 1 || reloaded.queued[0].Info.Status != proto.JobStatus_JobStatusQueued || reloaded.queued[0].Info.StartedAt != 0 {
+			t.Errorf("reloaded queue = %v, want the interrupted job queued again", reloaded.queued)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var optimizeCmd = &cobra.Command{
+	Use:   "optimize",
+	Short: "optimize gems, enchants and reforges",
+	Long:  "search gems, enchants, reforges and candidate items for the best gear set, confirming the best ones with full sims",
+	Run:   optimizeMain,
+}
+
+func init() {
+	optimizeCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (GearOptimizerRequest in protojson format)")
+	optimizeCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	optimizeCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	optimizeCmd.MarkFlagRequired("infile")
+}
+
+func optimizeMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.GearOptimizerRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	result := core.RunGearOptimizer(input)
+	if result.Error != nil {
+		log.Fatalf("optimizer failed: %s", result.Error.Message)
+	}
+	if verbose {
+		for i, candidate := range result.Candidates {
+			fmt.Printf("#%d: %0.1f DPS (%0.1f EP)\n", i+1, candidate.Dps.Avg, candidate.Ep)
+		}
+		fmt.Printf("Equipped: %0.1f DPS (%0.1f EP)\n", result.EquippedGear.Dps.Avg, result.EquippedGear.Ep)
+	}
+
+	output, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
+	if err != nil {
+		log.Fatalf("failed to marshal final results: %s", err)
+	}
+
+	if outfile == "" {
+		fmt.Print(string(output))
+	} else {
+		err = os.WriteFile(outfile, output, 0666)
+		if err != nil {
+			log.Fatalf("failed to write output file:: %s", err)
+		}
+		if verbose {
+			fmt.Printf("Wrote output file: `%s` successfully.\n", outfile)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var racesCmd = &cobra.Command{
+	Use:   "races",
+	Short: "compare the DPS of each race",
+	Long:  "sim the player as every race its class can be, reforged to each race's hit and expertise caps",
+	Run:   racesMain,
+}
+
+func init() {
+	racesCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaceComparisonRequest in protojson format)")
+	racesCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	racesCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	racesCmd.MarkFlagRequired("infile")
+}
+
+func racesMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.RaceComparisonRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	result := core.RunRaceComparison(input)
+	if result.Error != nil {
+		log.Fatalf("race comparison failed: %s", result.Error.Message)
+	}
+	if verbose {
+		for i, race := range result.Results {
+			current := ""
+			if race.Current {
+				current = " (current)"
+			}
+			fmt.Printf("#%d: %s%s: %0.1f DPS (%0.1f - %0.1f)\n", i+1, race.Race, current, race.Dps.Avg, race.DpsInterval.GetLower(), race.DpsInterval.GetUpper())
+		}
+	}
+
+	output, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
+	if err != nil {
+		log.Fatalf("failed to marshal final results: %s", err)
+	}
+
+	if outfile == "" {
+		fmt.Print(string(output))
+	} else {
+		err = os.WriteFile(outfile, output, 0666)
+		if err != nil {
+			log.Fatalf("failed to write output file:: %s", err)
+		}
+		if verbose {
+			fmt.Printf("Wrote output file: `%s` successfully.\n", outfile)
+		}
+	}
+}
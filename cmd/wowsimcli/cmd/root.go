@@ -18,6 +18,12 @@ func Execute(version string) {
 	rootCmd.AddCommand(simCmd)
 	rootCmd.AddCommand(bulkCmd)
 	rootCmd.AddCommand(decodeLinkCmd)
+	rootCmd.AddCommand(aplCmd)
+	rootCmd.AddCommand(optimizeCmd)
+	rootCmd.AddCommand(upgradesCmd)
+	rootCmd.AddCommand(racesCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(workerCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+var (
+	serveHost         string
+	serveJobsDir      string
+	serveMaxJobs      int
+	serveCpus         int
+	serveRetention    time.Duration
+	serveWorkers      []string
+	serveChunkTimeout time.Duration
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "run a job queue server for raid sims, bulk sims and stat weights",
+	Long: `run a job queue server for raid sims, bulk sims and stat weights, keeping results on disk
+
+Endpoints, taking and returning protojson, or binary protobuf with Content-Type application/x-protobuf:
+  POST /jobs              submit a JobRequest, returns its JobInfo
+  GET  /jobs              list all jobs as a JobListResult
+  GET  /jobs/{id}         get a Job, including its result once finished
+  POST /jobs/{id}/cancel  cancel a queued or running job, returns its JobInfo
+
+With --workers, jobs are simmed on "wowsimcli worker" processes instead of this machine.`,
+	Run: serveMain,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveHost, "host", "localhost:3334", "address to listen on")
+	serveCmd.Flags().StringVar(&serveJobsDir, "jobs-dir", "wowsim-jobs", "directory which jobs and their results are saved to")
+	serveCmd.Flags().IntVar(&serveMaxJobs, "max-jobs", 1, "number of jobs to run at once")
+	serveCmd.Flags().IntVar(&serveCpus, "cpus", 0, "maximum number of CPUs used by running jobs, defaults to all of them")
+	serveCmd.Flags().DurationVar(&serveRetention, "retention", 7*24*time.Hour, "how long finished jobs are kept")
+	serveCmd.Flags().StringSliceVar(&serveWorkers, "workers", nil, "comma separated host:port addresses of workers to sim jobs on")
+	serveCmd.Flags().DurationVar(&serveChunkTimeout, "chunk-timeout", 10*time.Minute, "how long a worker may take to sim one chunk before it is retried on another worker")
+}
+
+func serveMain(cmd *cobra.Command, args []string) {
+	if serveCpus > 0 {
+		runtime.GOMAXPROCS(serveCpus)
+	}
+
+	runner := runJob
+	if len(serveWorkers) > 0 {
+		runner = newSimCluster(serveWorkers, serveChunkTimeout).runJob
+		log.Printf("Running jobs on %d workers.", len(serveWorkers))
+	}
+
+	queue, err := newJobQueue(serveJobsDir, runner)
+	if err != nil {
+		log.Fatalf("failed to load jobs from %q: %s", serveJobsDir, err)
+	}
+	queue.prune(serveRetention)
+	queue.start(serveMaxJobs)
+
+	go func() {
+		for range time.Tick(time.Minute) {
+			queue.prune(serveRetention)
+		}
+	}()
+
+	go func() {
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+		<-interrupt
+		// Jobs which are still queued or running get requeued when the server starts again.
+		queue.stop()
+		os.Exit(0)
+	}()
+
+	log.Printf("Serving jobs on %s, saving them to %q.", serveHost, serveJobsDir)
+	if err := http.ListenAndServe(serveHost, newJobServer(queue)); err != nil {
+		log.Fatalf("failed to serve: %s", err)
+	}
+}
+
+func newJobServer(queue *jobQueue) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs", func(w http.ResponseWriter, r *http.Request) {
+		request := &proto.JobRequest{}
+		if !readJobMessage(w, r, request) {
+			return
+		}
+		info, err := queue.submit(request)
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+		writeJobMessage(w, r, info)
+	})
+	mux.HandleFunc("GET /jobs", func(w http.ResponseWriter, r *http.Request) {
+		writeJobMessage(w, r, queue.list())
+	})
+	mux.HandleFunc("GET /jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		job, err := queue.get(r.PathValue("id"))
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+		writeJobMessage(w, r, job)
+	})
+	mux.HandleFunc("POST /jobs/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		info, err := queue.cancel(r.PathValue("id"))
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+		writeJobMessage(w, r, info)
+	})
+	return mux
+}
+
+func isProtobufRequest(r *http.Request) bool {
+	return r.Header.Get("Content-Type") == "application/x-protobuf" || r.Header.Get("Accept") == "application/x-protobuf"
+}
+
+func readJobMessage(w http.ResponseWriter, r *http.Request, msg googleProto.Message) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false
+	}
+	if isProtobufRequest(r) {
+		err = googleProto.Unmarshal(body, msg)
+	} else {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, msg)
+	}
+	if err != nil {
+		http.Error(w, "failed to parse request: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJobMessage(w http.ResponseWriter, r *http.Request, msg googleProto.Message) {
+	var output []byte
+	var err error
+	if isProtobufRequest(r) {
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		output, err = googleProto.Marshal(msg)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		output, err = protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	}
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Write(output)
+}
+
+func writeJobError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errJobNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, errEmptyJobRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
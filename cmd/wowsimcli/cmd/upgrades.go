@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var upgradesCmd = &cobra.Command{
+	Use:   "upgrades",
+	Short: "rank item upgrades by DPS gain",
+	Long:  "sim each equipped item at its next upgrade step, and optionally the set in Challenge Mode, ranking them by DPS gain",
+	Run:   upgradesMain,
+}
+
+func init() {
+	upgradesCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (ItemUpgradeRequest in protojson format)")
+	upgradesCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	upgradesCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	upgradesCmd.MarkFlagRequired("infile")
+}
+
+func upgradesMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.ItemUpgradeRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	result := core.RunItemUpgrades(input)
+	if result.Error != nil {
+		log.Fatalf("item upgrades failed: %s", result.Error.Message)
+	}
+	if verbose {
+		fmt.Printf("Equipped: %0.1f DPS\n", result.EquippedDps.Avg)
+		for i, upgrade := range result.Upgrades {
+			if upgrade.Item == nil {
+				fmt.Printf("#%d: Challenge Mode set: %+0.1f DPS\n", i+1, upgrade.DpsGain)
+				continue
+			}
+			fmt.Printf("#%d: %s item %d %s -> %s: %+0.1f DPS\n", i+1, upgrade.Item.Slot, upgrade.Item.Item.Id, upgrade.FromState, upgrade.ToState, upgrade.DpsGain)
+		}
+	}
+
+	output, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
+	if err != nil {
+		log.Fatalf("failed to marshal final results: %s", err)
+	}
+
+	if outfile == "" {
+		fmt.Print(string(output))
+	} else {
+		err = os.WriteFile(outfile, output, 0666)
+		if err != nil {
+			log.Fatalf("failed to write output file:: %s", err)
+		}
+		if verbose {
+			fmt.Printf("Wrote output file: `%s` successfully.\n", outfile)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+var (
+	workerHost string
+	workerCpus int
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "run raid sims sent by a serve instance started with --workers",
+	Long: `run raid sims sent by a serve instance started with --workers
+
+Endpoint, taking and returning binary protobuf:
+  POST /raidSim  sim a RaidSimRequest on all threads, returns its RaidSimResult. The sim is aborted if the request is cancelled.`,
+	Run: workerMain,
+}
+
+func init() {
+	workerCmd.Flags().StringVar(&workerHost, "host", ":3340", "address to listen on")
+	workerCmd.Flags().IntVar(&workerCpus, "cpus", 0, "maximum number of CPUs used by sims, defaults to all of them")
+}
+
+func workerMain(cmd *cobra.Command, args []string) {
+	if workerCpus > 0 {
+		runtime.GOMAXPROCS(workerCpus)
+	}
+	log.Printf("Worker listening on %s.", workerHost)
+	if err := http.ListenAndServe(workerHost, newWorkerServer(core.LocalRaidSimFunc())); err != nil {
+		log.Fatalf("failed to serve: %s", err)
+	}
+}
+
+func newWorkerServer(simFunc core.RaidSimFunc) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /raidSim", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		request := &proto.RaidSimRequest{}
+		if err := googleProto.Unmarshal(body, request); err != nil {
+			http.Error(w, "failed to parse request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		signals := simsignals.CreateSignals()
+		reporter := make(chan *proto.ProgressMetrics, 100)
+		go simFunc(request, reporter, signals)
+
+		var result *proto.RaidSimResult
+		for result == nil {
+			select {
+			case metrics, ok := <-reporter:
+				if !ok {
+					http.Error(w, "sim finished without a result", http.StatusInternalServerError)
+					return
+				}
+				result = metrics.FinalRaidResult
+			case <-r.Context().Done():
+				// The coordinator gave up on this chunk, so stop simming it. The sim still reports
+				// until it notices, so keep draining.
+				signals.Abort.Trigger()
+				go func() {
+					for range reporter {
+					}
+				}()
+				return
+			}
+		}
+
+		outbytes, err := googleProto.Marshal(result)
+		if err != nil {
+			log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.Write(outbytes)
+	})
+	return mux
+}
@@ -23,14 +23,30 @@ func ComputeStats(csr *proto.ComputeStatsRequest) *proto.ComputeStatsResult {
 	}
 }
 
+/**
+ * Returns the hit and expertise caps of a player, and which stats count towards them.
+ */
+func ComputeStatCaps(request *proto.StatCapsRequest) *proto.StatCapsResult {
+	caps, err := computeStatCaps(request)
+	if err != nil {
+		return &proto.StatCapsResult{ErrorResult: err.Error()}
+	}
+	return &proto.StatCapsResult{Caps: caps}
+}
+
 /**
  * Returns stat weights and EP values, with standard deviations, for all stats.
  */
 func StatWeights(request *proto.StatWeightsRequest) *proto.StatWeightsResult {
-	return runStatWeights(request, nil, simsignals.CreateSignals())
+	return runStatWeights(request, nil, simsignals.CreateSignals(), LocalRaidSimFunc())
 }
 
 func StatWeightsAsync(request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	StatWeightsWithSimFuncAsync(request, progress, requestId, LocalRaidSimFunc())
+}
+
+// Like StatWeightsAsync, but runs each sim with simFunc, e.g. to run them on other machines.
+func StatWeightsWithSimFuncAsync(request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics, requestId string, simFunc RaidSimFunc) {
 	signals, err := simsignals.RegisterWithId(requestId)
 	if err != nil {
 		progress <- &proto.ProgressMetrics{
@@ -44,7 +60,7 @@ func StatWeightsAsync(request *proto.StatWeightsRequest, progress chan *proto.Pr
 	}
 	go func() {
 		defer simsignals.UnregisterId(requestId)
-		result := runStatWeights(request, progress, signals)
+		result := runStatWeights(request, progress, signals, simFunc)
 		progress <- &proto.ProgressMetrics{
 			FinalWeightResult: result,
 		}
@@ -92,6 +108,11 @@ func RunRaidSimConcurrent(request *proto.RaidSimRequest) *proto.RaidSimResult {
 
 // Threading does not work in WASM!
 func RunRaidSimConcurrentAsync(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	RunRaidSimWithSimFuncAsync(request, progress, requestId, runSimConcurrent)
+}
+
+// Like RunRaidSimConcurrentAsync, but runs the sim with simFunc, e.g. to run it on other machines.
+func RunRaidSimWithSimFuncAsync(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, requestId string, simFunc RaidSimFunc) {
 	signals, err := simsignals.RegisterWithId(requestId)
 	if err != nil {
 		progress <- &proto.ProgressMetrics{
@@ -105,7 +126,7 @@ func RunRaidSimConcurrentAsync(request *proto.RaidSimRequest, progress chan *pro
 	}
 	go func() {
 		defer simsignals.UnregisterId(requestId)
-		runSimConcurrent(request, progress, signals)
+		simFunc(request, progress, signals)
 	}()
 }
 
@@ -114,6 +135,15 @@ func RunBulkSim(request *proto.BulkSimRequest) *proto.BulkSimResult {
 }
 
 func RunBulkSimAsync(request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	RunBulkSimWithOptionsAsync(request, progress, requestId, BulkSimOptions{})
+}
+
+// Like RunBulkSimAsync, but skips combos already stored in the checkpoint and saves new results to it.
+func RunBulkSimWithCheckpointAsync(request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, requestId string, checkpoint *BulkSimCheckpoint) {
+	RunBulkSimWithOptionsAsync(request, progress, requestId, BulkSimOptions{Checkpoint: checkpoint})
+}
+
+func RunBulkSimWithOptionsAsync(request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, requestId string, options BulkSimOptions) {
 	signals, err := simsignals.RegisterWithId(requestId)
 	if err != nil {
 		progress <- &proto.ProgressMetrics{
@@ -127,10 +157,157 @@ func RunBulkSimAsync(request *proto.BulkSimRequest, progress chan *proto.Progres
 	}
 	go func() {
 		defer simsignals.UnregisterId(requestId)
-		BulkSim(signals, request, progress)
+		BulkSimWithOptions(signals, request, progress, options)
+	}()
+}
+
+/**
+ * Returns DPS for each value of a stat in a range, with the marginal value of the stat between points.
+ */
+func RunStatScaling(request *proto.StatScalingRequest) *proto.StatScalingResult {
+	return runStatScaling(request, nil, simsignals.CreateSignals(), LocalRaidSimFunc())
+}
+
+func RunStatScalingAsync(request *proto.StatScalingRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalScalingResult: &proto.StatScalingResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := runStatScaling(request, progress, signals, LocalRaidSimFunc())
+		progress <- &proto.ProgressMetrics{
+			FinalScalingResult: result,
+		}
 	}()
 }
 
+/**
+ * Returns the DPS gain of each equipped item's next upgrade step, best first.
+ */
+func RunItemUpgrades(request *proto.ItemUpgradeRequest) *proto.ItemUpgradeResult {
+	return runItemUpgrades(simsignals.CreateSignals(), request, nil)
+}
+
+func RunItemUpgradesAsync(request *proto.ItemUpgradeRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalUpgradeResult: &proto.ItemUpgradeResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := runItemUpgrades(signals, request, progress)
+		progress <- &proto.ProgressMetrics{
+			FinalUpgradeResult: result,
+		}
+	}()
+}
+
+/**
+ * Returns the DPS of each pair of professions, with the gear changed to use the pair's perks.
+ */
+func RunProfessionComparison(request *proto.ProfessionComparisonRequest) *proto.ProfessionComparisonResult {
+	return runProfessionComparison(simsignals.CreateSignals(), request, nil)
+}
+
+func RunProfessionComparisonAsync(request *proto.ProfessionComparisonRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalProfessionResult: &proto.ProfessionComparisonResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := runProfessionComparison(signals, request, progress)
+		progress <- &proto.ProgressMetrics{
+			FinalProfessionResult: result,
+		}
+	}()
+}
+
+/**
+ * Returns the DPS of each race the player's class can be, reforged to each race's hit and expertise caps.
+ */
+func RunRaceComparison(request *proto.RaceComparisonRequest) *proto.RaceComparisonResult {
+	return runRaceComparison(simsignals.CreateSignals(), request, nil)
+}
+
+func RunRaceComparisonAsync(request *proto.RaceComparisonRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalRaceResult: &proto.RaceComparisonResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := runRaceComparison(signals, request, progress)
+		progress <- &proto.ProgressMetrics{
+			FinalRaceResult: result,
+		}
+	}()
+}
+
+/**
+ * Returns the DPS of each choice of the requested talent tiers and of combinations of major glyphs.
+ */
+func RunTalentSweep(request *proto.TalentSweepRequest) *proto.TalentSweepResult {
+	return runTalentSweep(simsignals.CreateSignals(), request, nil)
+}
+
+func RunTalentSweepAsync(request *proto.TalentSweepRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalSweepResult: &proto.TalentSweepResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := runTalentSweep(signals, request, progress)
+		progress <- &proto.ProgressMetrics{
+			FinalSweepResult: result,
+		}
+	}()
+}
+
+/**
+ * Searches gems, enchants and reforges for the best gear set, confirming the best ones with sims.
+ */
+func RunGearOptimizer(request *proto.GearOptimizerRequest) *proto.GearOptimizerResult {
+	return OptimizeGear(simsignals.CreateSignals(), request)
+}
+
 var runningInWasm = false
 
 func SetRunningInWasm() {
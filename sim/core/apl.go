@@ -15,6 +15,8 @@ type APLRotation struct {
 
 	// Action currently controlling this rotation (only used for certain actions, such as StrictSequence).
 	controllingActions []APLActionImpl
+	// Index into priorityList of the entry which pushed each controlling action, or -1.
+	controllingEntryIdxs []int
 
 	// Value that should evaluate to 'true' if the current channel is to be interrupted.
 	// Will be nil when there is no active channel.
@@ -42,6 +44,22 @@ type APLRotation struct {
 	// Maps indices in filtered sim lists to indices in configs.
 	prepullIdxMap      []int
 	priorityListIdxMap []int
+
+	// Firing counts for each entry in priorityList, summed over all iterations.
+	entryMetrics []aplEntryMetrics
+	// Index into priorityList of the action returned by the last getNextAction call from the main
+	// loop, or -1.
+	lastEntryIdx int
+	// Set while the main loop chooses its next action. Lookaheads, such as deciding whether to
+	// interrupt a channel, don't count towards entryMetrics.
+	recordingMetrics bool
+}
+
+type aplEntryMetrics struct {
+	evaluated     int64
+	conditionTrue int64
+	// Also counts the actions an entry runs after taking control, such as the steps of a strict sequence.
+	executed int64
 }
 
 func (rot *APLRotation) ValidationMessage(log_level proto.LogLevel, message string, vals ...interface{}) {
@@ -141,6 +159,9 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 		})
 	}
 
+	rotation.entryMetrics = make([]aplEntryMetrics, len(rotation.priorityList))
+	rotation.lastEntryIdx = -1
+
 	// Finalize
 	for i, action := range rotation.prepullActions {
 		rotation.doAndRecordWarnings(&rotation.prepullValidations[rotation.prepullIdxMap[i]], true, func() {
@@ -152,6 +173,7 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 			action.Finalize(rotation)
 		})
 	}
+	rotation.lint(config)
 
 	agent := unit.Env.GetAgentFromUnit(unit)
 	if agent != nil {
@@ -241,6 +263,19 @@ func (rot *APLRotation) getStats() *proto.APLStats {
 	}
 }
 
+func (rot *APLRotation) getEntryMetricsProto() []*proto.APLEntryMetrics {
+	entries := make([]*proto.APLEntryMetrics, len(rot.entryMetrics))
+	for i, metrics := range rot.entryMetrics {
+		entries[i] = &proto.APLEntryMetrics{
+			Index:         int32(rot.priorityListIdxMap[i]),
+			Evaluated:     metrics.evaluated,
+			ConditionTrue: metrics.conditionTrue,
+			Executed:      metrics.executed,
+		}
+	}
+	return entries
+}
+
 func (rot *APLRotation) allAPLActions() []*APLAction {
 	if rot == nil || rot.priorityList == nil {
 		return []*APLAction{}
@@ -262,6 +297,8 @@ func (rot *APLRotation) allPrepullActions() []*APLAction {
 
 func (rot *APLRotation) reset(sim *Simulation) {
 	rot.controllingActions = nil
+	rot.controllingEntryIdxs = nil
+	rot.lastEntryIdx = -1
 	rot.inLoop = false
 	rot.interruptChannelIf = nil
 	rot.allowChannelRecastOnInterrupt = false
@@ -294,11 +331,14 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 	apl.inLoop = true
 
 	apl.unit.UpdatePosition(sim)
-	for nextAction := apl.getNextAction(sim); nextAction != nil; i, nextAction = i+1, apl.getNextAction(sim) {
+	for nextAction := apl.getNextLoopAction(sim); nextAction != nil; i, nextAction = i+1, apl.getNextLoopAction(sim) {
 		if i > 1000 {
 			panic(fmt.Sprintf("[USER_ERROR] Infinite loop detected, current action:\n%s", nextAction))
 		}
 
+		if apl.lastEntryIdx != -1 {
+			apl.entryMetrics[apl.lastEntryIdx].executed++
+		}
 		nextAction.Execute(sim)
 	}
 	apl.inLoop = false
@@ -319,13 +359,43 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 	}
 }
 
+// Same as getNextAction, but records entry metrics. Only used by the main loop of DoNextAction.
+func (apl *APLRotation) getNextLoopAction(sim *Simulation) *APLAction {
+	apl.recordingMetrics = true
+	nextAction := apl.getNextAction(sim)
+	apl.recordingMetrics = false
+	return nextAction
+}
+
 func (apl *APLRotation) getNextAction(sim *Simulation) *APLAction {
+	if apl.recordingMetrics {
+		apl.lastEntryIdx = -1
+	}
 	if len(apl.controllingActions) != 0 {
-		return apl.controllingActions[len(apl.controllingActions)-1].GetNextAction(sim)
+		controllingEntryIdx := apl.controllingEntryIdxs[len(apl.controllingEntryIdxs)-1]
+		nextAction := apl.controllingActions[len(apl.controllingActions)-1].GetNextAction(sim)
+		// Actions from the priority list, e.g. once a wait is over, already set lastEntryIdx.
+		if apl.recordingMetrics && nextAction != nil && apl.lastEntryIdx == -1 {
+			apl.lastEntryIdx = controllingEntryIdx
+		}
+		return nextAction
 	}
 
-	for _, action := range apl.priorityList {
-		if action.IsReady(sim) {
+	for i, action := range apl.priorityList {
+		// Same as action.IsReady(), but split up so we can record which part failed.
+		conditionTrue := action.condition == nil || action.condition.GetBool(sim)
+		isReady := conditionTrue && action.impl.IsReady(sim)
+		if apl.recordingMetrics {
+			metrics := &apl.entryMetrics[i]
+			metrics.evaluated++
+			if conditionTrue {
+				metrics.conditionTrue++
+			}
+			if isReady {
+				apl.lastEntryIdx = i
+			}
+		}
+		if isReady {
 			return action
 		}
 	}
@@ -335,6 +405,7 @@ func (apl *APLRotation) getNextAction(sim *Simulation) *APLAction {
 
 func (apl *APLRotation) pushControllingAction(ca APLActionImpl) {
 	apl.controllingActions = append(apl.controllingActions, ca)
+	apl.controllingEntryIdxs = append(apl.controllingEntryIdxs, apl.lastEntryIdx)
 }
 
 func (apl *APLRotation) popControllingAction(ca APLActionImpl) {
@@ -342,6 +413,7 @@ func (apl *APLRotation) popControllingAction(ca APLActionImpl) {
 		panic("Wrong APL controllingAction in pop()")
 	}
 	apl.controllingActions = apl.controllingActions[:len(apl.controllingActions)-1]
+	apl.controllingEntryIdxs = apl.controllingEntryIdxs[:len(apl.controllingEntryIdxs)-1]
 }
 
 func (apl *APLRotation) shouldInterruptChannel(sim *Simulation) bool {
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Static checks over a parsed rotation, for mistakes that are valid APL but almost
+// certainly not what the user intended. Runs after all actions have been finalized.
+func (rot *APLRotation) lint(config *proto.APLRotation) {
+	type castKey struct {
+		spell  *Spell
+		target UnitReference
+	}
+	unconditionalCasts := make(map[castKey]int)
+
+	for i, action := range rot.priorityList {
+		configIdx := rot.priorityListIdxMap[i]
+		item := config.PriorityList[configIdx]
+
+		// Validations for the whole entry are attached to its condition when possible,
+		// and otherwise to the entry itself.
+		report := func(message string, vals ...interface{}) {
+			if uuid := item.Action.Condition.GetUuid(); uuid != nil {
+				rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, message, vals...)
+			} else {
+				rot.doAndRecordWarnings(&rot.priorityListValidations[configIdx], false, func() {
+					rot.ValidationMessage(proto.LogLevel_Warning, message, vals...)
+				})
+			}
+		}
+
+		alwaysTrue := action.condition == nil
+		if action.condition != nil {
+			if val, isConst := aplConstantBool(action.condition); isConst {
+				if !val {
+					report("Condition is always false, this action will never be used")
+					continue
+				}
+				alwaysTrue = true
+			}
+		}
+
+		var key castKey
+		switch impl := action.impl.(type) {
+		case *APLActionCastSpell:
+			key = castKey{spell: impl.spell, target: impl.target}
+		case *APLActionCastFriendlySpell:
+			key = castKey{spell: impl.spell, target: impl.target}
+		default:
+			continue
+		}
+
+		if prevIdx, ok := unconditionalCasts[key]; ok {
+			report("Action is shadowed by action #%d, which casts %s unconditionally, so this action will never be used", prevIdx+1, key.spell.ActionID)
+		} else if alwaysTrue {
+			unconditionalCasts[key] = configIdx
+		}
+	}
+
+	for _, item := range config.PriorityList {
+		if !item.Hide {
+			rot.lintSpellReferences(item.Action.ProtoReflect(), nil)
+		}
+	}
+	for _, item := range config.PrepullActions {
+		if !item.Hide {
+			rot.lintSpellReferences(item.Action.ProtoReflect(), nil)
+		}
+	}
+}
+
+// Values which reference spells that may legitimately be unknown, or belong to another unit.
+var aplLintSpellReferenceExemptions = map[protoreflect.FullName]bool{
+	(&proto.APLValueSpellIsKnown{}).ProtoReflect().Descriptor().FullName():         true,
+	(&proto.APLValueBossSpellIsCasting{}).ProtoReflect().Descriptor().FullName():   true,
+	(&proto.APLValueBossSpellTimeToReady{}).ProtoReflect().Descriptor().FullName(): true,
+}
+
+// Reports spells referenced from within values that the unit does not know, e.g. due to
+// talent choices. Such values have no effect, which is easy to miss in a long condition.
+// Unknown spells in actions are already reported by GetAPLSpell.
+func (rot *APLRotation) lintSpellReferences(msg protoreflect.Message, uuid *proto.UUID) {
+	if !msg.IsValid() || aplLintSpellReferenceExemptions[msg.Descriptor().FullName()] {
+		return
+	}
+	if value, ok := msg.Interface().(*proto.APLValue); ok && value.Uuid != nil {
+		uuid = value.Uuid
+	}
+
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		if fd.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				rot.lintSpellReferences(list.Get(i).Message(), uuid)
+			}
+			return true
+		}
+
+		if fd.Name() == "spell_id" && uuid != nil {
+			actionID := ProtoToActionID(v.Message().Interface().(*proto.ActionID))
+			if actionID.SpellID != 0 && rot.unit.GetSpell(actionID) == nil {
+				rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "%s cannot cast %s with the current talents and setup", rot.unit.Label, actionID)
+			}
+			return true
+		}
+		rot.lintSpellReferences(v.Message(), uuid)
+		return true
+	})
+}
+
+// Returns the value of a boolean condition if it does not depend on the sim state.
+func aplConstantBool(value APLValue) (bool, bool) {
+	switch v := value.(type) {
+	case *APLValueAnd:
+		allTrue := true
+		for _, inner := range v.vals {
+			val, isConst := aplConstantBool(inner)
+			if isConst && !val {
+				return false, true
+			}
+			allTrue = allTrue && isConst
+		}
+		return true, allTrue
+	case *APLValueOr:
+		allFalse := true
+		for _, inner := range v.vals {
+			val, isConst := aplConstantBool(inner)
+			if isConst && val {
+				return true, true
+			}
+			allFalse = allFalse && isConst
+		}
+		return false, allFalse
+	case *APLValueNot:
+		val, isConst := aplConstantBool(v.val)
+		return !val, isConst
+	}
+
+	if value.Type() != proto.APLValueType_ValueTypeBool || !aplValueIsConstant(value) {
+		return false, false
+	}
+	return aplEvalConstantBool(value)
+}
+
+// Coercions between some types panic, in which case the value is left for the sim to report.
+func aplEvalConstantBool(value APLValue) (val bool, isConst bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			val, isConst = false, false
+		}
+	}()
+	return value.GetBool(nil), true
+}
+
+// Whether a value is built only from constants and operators, so it can be evaluated without a sim.
+func aplValueIsConstant(value APLValue) bool {
+	switch value.(type) {
+	case *APLValueConst:
+		return true
+	case *APLValueCoerced, *APLValueAnd, *APLValueOr, *APLValueNot, *APLValueCompare, *APLValueMath, *APLValueMax, *APLValueMin:
+		for _, inner := range value.GetInnerValues() {
+			if inner == nil || !aplValueIsConstant(inner) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
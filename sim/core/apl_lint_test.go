@@ -0,0 +1,43 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestAPLConstantBool(t *testing.T) {
+	rot := &APLRotation{}
+	constant := func(val string) APLValue {
+		return rot.newValueConst(&proto.APLValueConst{Val: val}, nil)
+	}
+	dynamic := &APLValueCurrentTime{}
+
+	check := func(name string, value APLValue, wantVal bool, wantConst bool) {
+		val, isConst := aplConstantBool(rot.coerceTo(value, proto.APLValueType_ValueTypeBool))
+		if isConst != wantConst || (isConst && val != wantVal) {
+			t.Errorf("%s: got (%t, %t), want (%t, %t)", name, val, isConst, wantVal, wantConst)
+		}
+	}
+
+	check("false", constant("false"), false, true)
+	check("dynamic", dynamic, false, false)
+	check("and with false", &APLValueAnd{vals: []APLValue{dynamic, constant("false")}}, false, true)
+	check("and with true", &APLValueAnd{vals: []APLValue{dynamic, constant("true")}}, false, false)
+	check("or with true", &APLValueOr{vals: []APLValue{dynamic, constant("true")}}, true, true)
+	check("not", &APLValueNot{val: constant("true")}, false, true)
+	check("compare", &APLValueCompare{op: proto.APLValueCompare_OpGt, lhs: constant("1s"), rhs: constant("2s")}, false, true)
+	check("compare dynamic", &APLValueCompare{op: proto.APLValueCompare_OpGt, lhs: dynamic, rhs: constant("2s")}, false, false)
+}
+
+func TestAPLValueTypesComparable(t *testing.T) {
+	if !aplValueTypesComparable(proto.APLValueType_ValueTypeInt, proto.APLValueType_ValueTypeDuration) {
+		t.Errorf("int and duration should be comparable")
+	}
+	if aplValueTypesComparable(proto.APLValueType_ValueTypeBool, proto.APLValueType_ValueTypeDuration) {
+		t.Errorf("bool and duration should not be comparable")
+	}
+	if aplValueTypesComparable(proto.APLValueType_ValueTypeString, proto.APLValueType_ValueTypeFloat) {
+		t.Errorf("string and float should not be comparable")
+	}
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+type testAPLActionImpl struct {
+	defaultAPLActionImpl
+	ready bool
+}
+
+func (impl *testAPLActionImpl) IsReady(*Simulation) bool { return impl.ready }
+func (impl *testAPLActionImpl) Execute(*Simulation)      {}
+func (impl *testAPLActionImpl) String() string           { return "Test" }
+
+func TestAPLEntryMetrics(t *testing.T) {
+	rot := &APLRotation{}
+	falseCondition := rot.newValueConst(&proto.APLValueConst{Val: "false"}, nil)
+	rot.priorityList = []*APLAction{
+		{condition: falseCondition, impl: &testAPLActionImpl{ready: true}},
+		{impl: &testAPLActionImpl{ready: false}},
+		{impl: &testAPLActionImpl{ready: true}},
+		{impl: &testAPLActionImpl{ready: true}},
+	}
+	// Entry 1 of the config is hidden.
+	rot.priorityListIdxMap = []int{0, 2, 3, 4}
+	rot.entryMetrics = make([]aplEntryMetrics, len(rot.priorityList))
+
+	for i := 0; i < 3; i++ {
+		if rot.getNextLoopAction(nil) != rot.priorityList[2] {
+			t.Fatalf("unexpected next action")
+		}
+	}
+	// Lookaheads, e.g. for channel interrupts, are not recorded.
+	if rot.getNextAction(nil) != rot.priorityList[2] {
+		t.Fatalf("unexpected next action")
+	}
+
+	want := []*proto.APLEntryMetrics{
+		{Index: 0, Evaluated: 3},
+		{Index: 2, Evaluated: 3, ConditionTrue: 3},
+		{Index: 3, Evaluated: 3, ConditionTrue: 3},
+		{Index: 4},
+	}
+	for i, got := range rot.getEntryMetricsProto() {
+		if got.Index != want[i].Index || got.Evaluated != want[i].Evaluated || got.ConditionTrue != want[i].ConditionTrue || got.Executed != 0 {
+			t.Errorf("entry %d: got %v, want %v", i, got, want[i])
+		}
+	}
+	if rot.lastEntryIdx != 2 {
+		t.Errorf("lastEntryIdx = %d, want 2", rot.lastEntryIdx)
+	}
+}
+
+func TestAPLEntryMetricsInSim(t *testing.T) {
+	result := RunRaidSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{
+			Iterations: 2,
+			RandomSeed: 100,
+		},
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Name:      "Caster",
+			Class:     proto.Class_ClassShaman,
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: &proto.EquipmentSpec{},
+			Rotation: APLRotationFromTextString(`
+type: TypeAPL
+cast_spell(spell:44) if false
+channel_spell(spell:43, false) if current_time < 10s
+strict_sequence(cast_spell(spell:44), cast_spell(spell:44)) if current_time < 20s
+cast_spell(spell:44)
+`),
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon}},
+			Duration: 30,
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("RunRaidSim() returned error: %s", result.Error.Message)
+	}
+
+	playerMetrics := result.RaidMetrics.Parties[0].Players[0]
+	casts := make(map[int32]int64)
+	for _, action := range playerMetrics.Actions {
+		for _, target := range action.Targets {
+			casts[action.Id.GetSpellId()] += int64(target.Casts)
+		}
+	}
+	entries := playerMetrics.AplEntries
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries, want 4", len(entries))
+	}
+
+	if entries[0].ConditionTrue != 0 || entries[0].Executed != 0 {
+		t.Errorf("entry 0 with a false condition: got %v", entries[0])
+	}
+	// Every main loop evaluation starts at the first entry, and each executed action comes from one.
+	executed := entries[1].Executed + entries[2].Executed + entries[3].Executed
+	if entries[0].Evaluated < executed {
+		t.Errorf("entry 0 evaluated %d times, fewer than the %d executed actions", entries[0].Evaluated, executed)
+	}
+	if entries[1].Executed == 0 || entries[1].Executed != casts[43] {
+		t.Errorf("channel entry executed %d times, want the %d channels", entries[1].Executed, casts[43])
+	}
+	// The strict sequence counts taking control plus each of its 2 casts.
+	if entries[2].Executed == 0 || entries[2].Executed%3 != 0 {
+		t.Errorf("strict sequence executed %d times, want a multiple of 3", entries[2].Executed)
+	}
+	if want := entries[2].Executed/3*2 + entries[3].Executed; entries[3].Executed == 0 || casts[44] != want {
+		t.Errorf("got %d casts of the filler, want %d from the sequence and last entry", casts[44], want)
+	}
+}
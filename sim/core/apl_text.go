@@ -0,0 +1,436 @@
+package core
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Text format for APL rotations, as a compact alternative to the protojson format.
+//
+// Each line is one statement:
+//
+//	type: TypeAPL
+//	prepull -1s: cast_spell(spell:8050)
+//	// Notes for the next priority list item.
+//	cast_spell(spell:51505) if dot_remaining_time(spell:8050) > spell_cast_time(spell:51505)
+//	hide wait(0.5s)
+//
+// Actions and values are written with their proto field names, e.g. cast_spell(...) or
+// current_mana_percent. Arguments are written as 'field: value', except for action IDs
+// (spell:123, item:456, other:OtherActionPotion, spell:123:1 for tag 1), unit references
+// (Self, Target:1) and the only APLValue field of a message, which may be given positionally.
+// Comparisons, math, and/or/not and constants use infix syntax. A value UUID is written as
+// a '@"uuid"' suffix. Lines starting with '#' are comments.
+//
+// The format maps 1:1 to proto.APLRotation, so converting in either direction is lossless.
+
+type APLTextOptions struct {
+	// Drops value UUIDs from the output. These are only used by the UI to attach validations.
+	OmitUUIDs bool
+}
+
+var (
+	aplActionDescriptor        = (&proto.APLAction{}).ProtoReflect().Descriptor()
+	aplValueDescriptor         = (&proto.APLValue{}).ProtoReflect().Descriptor()
+	aplActionOneof             = aplActionDescriptor.Oneofs().ByName("action")
+	aplValueOneof              = aplValueDescriptor.Oneofs().ByName("value")
+	actionIDDescriptor         = (&proto.ActionID{}).ProtoReflect().Descriptor()
+	unitReferenceDescriptor    = (&proto.UnitReference{}).ProtoReflect().Descriptor()
+	unitReferenceTypeValues    = unitReferenceDescriptor.Fields().ByName("type").Enum().Values()
+	aplRotationListFieldNames  = []protoreflect.Name{"prepull_actions", "priority_list"}
+	aplTextBareConstPattern    = regexp.MustCompile(`^-?[0-9][0-9A-Za-z.%]*$`)
+	aplTextActionIDLiteralKeys = map[string]protoreflect.Name{
+		"spell": "spell_id",
+		"item":  "item_id",
+		"other": "other_id",
+	}
+)
+
+var aplTextCompareOps = map[proto.APLValueCompare_ComparisonOperator]string{
+	proto.APLValueCompare_OpEq: "==",
+	proto.APLValueCompare_OpNe: "!=",
+	proto.APLValueCompare_OpLt: "<",
+	proto.APLValueCompare_OpLe: "<=",
+	proto.APLValueCompare_OpGt: ">",
+	proto.APLValueCompare_OpGe: ">=",
+}
+
+var aplTextMathOps = map[proto.APLValueMath_MathOperator]string{
+	proto.APLValueMath_OpAdd: "+",
+	proto.APLValueMath_OpSub: "-",
+	proto.APLValueMath_OpMul: "*",
+	proto.APLValueMath_OpDiv: "/",
+}
+
+// Operator precedence levels, from loosest to tightest binding.
+const (
+	aplTextPrecOr = iota + 1
+	aplTextPrecAnd
+	aplTextPrecNot
+	aplTextPrecCmp
+	aplTextPrecAdd
+	aplTextPrecMul
+	aplTextPrecPrimary
+)
+
+func aplTextMathPrec(op proto.APLValueMath_MathOperator) int {
+	if op == proto.APLValueMath_OpMul || op == proto.APLValueMath_OpDiv {
+		return aplTextPrecMul
+	}
+	return aplTextPrecAdd
+}
+
+// Returns the field if it is the only field of the message, and is a repeated message field.
+// Arguments for such messages are list elements, e.g. max(a, b).
+func aplTextSoleRepeatedField(md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	if md.Fields().Len() != 1 {
+		return nil
+	}
+	fd := md.Fields().Get(0)
+	if !fd.IsList() || fd.Kind() != protoreflect.MessageKind {
+		return nil
+	}
+	return fd
+}
+
+// Returns the field if it is the only singular field of the message with the given message type.
+// Such fields may be given positionally.
+func aplTextSoleFieldOfType(md protoreflect.MessageDescriptor, fieldType protoreflect.FullName) protoreflect.FieldDescriptor {
+	var found protoreflect.FieldDescriptor
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.IsList() || fd.Kind() != protoreflect.MessageKind || fd.Message().FullName() != fieldType {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = fd
+	}
+	return found
+}
+
+// Parses the APL text format into a rotation.
+func ParseAPLText(text string) (rotation *proto.APLRotation, err error) {
+	tokens, err := lexAPLText(text)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &aplTextParser{tokens: tokens}
+	defer func() {
+		if r := recover(); r != nil {
+			if parseErr, ok := r.(*aplTextError); ok {
+				rotation = nil
+				err = parseErr
+				return
+			}
+			panic(r)
+		}
+	}()
+	return parser.parseRotation(), nil
+}
+
+// Same as APLRotationFromJsonString, but for the text format.
+func APLRotationFromTextString(text string) *proto.APLRotation {
+	apl, err := ParseAPLText(text)
+	if err != nil {
+		panic(err)
+	}
+	return apl
+}
+
+// Formats a rotation using the APL text format.
+func FormatAPLText(rotation *proto.APLRotation, options APLTextOptions) string {
+	printer := &aplTextPrinter{options: options}
+	return printer.rotation(rotation)
+}
+
+///////////////////////////////////////////////////////////////////////////
+//                                 PRINTER
+///////////////////////////////////////////////////////////////////////////
+
+type aplTextPrinter struct {
+	options APLTextOptions
+}
+
+func (p *aplTextPrinter) rotation(rotation *proto.APLRotation) string {
+	var sections []string
+
+	var header []string
+	msg := rotation.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !msg.Has(fd) || fd.Name() == aplRotationListFieldNames[0] || fd.Name() == aplRotationListFieldNames[1] {
+			continue
+		}
+		header = append(header, string(fd.Name())+": "+p.field(fd, msg.Get(fd)))
+	}
+	if len(header) > 0 {
+		sections = append(sections, strings.Join(header, "\n"))
+	}
+
+	if len(rotation.PrepullActions) > 0 {
+		lines := MapSlice(rotation.PrepullActions, func(item *proto.APLPrepullAction) string {
+			line := Ternary(item.Hide, "hide prepull", "prepull")
+			if item.DoAtValue != nil {
+				line += " " + p.value(item.DoAtValue, 0)
+			}
+			return line + ": " + p.action(item.Action)
+		})
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	if len(rotation.PriorityList) > 0 {
+		lines := MapSlice(rotation.PriorityList, func(item *proto.APLListItem) string {
+			line := ""
+			if item.Notes != "" {
+				for _, note := range strings.Split(item.Notes, "\n") {
+					line += Ternary(note == "", "//", "// "+note) + "\n"
+				}
+			}
+			if item.Hide {
+				line += "hide "
+			}
+			return line + p.action(item.Action)
+		})
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	if len(sections) == 0 {
+		return ""
+	}
+	return strings.Join(sections, "\n\n") + "\n"
+}
+
+func (p *aplTextPrinter) action(action *proto.APLAction) string {
+	if action == nil {
+		return "nil"
+	}
+
+	str := "none"
+	msg := action.ProtoReflect()
+	if fd := msg.WhichOneof(aplActionOneof); fd != nil {
+		str = p.call(string(fd.Name()), msg.Get(fd).Message())
+	}
+	if action.Condition != nil {
+		str += " if " + p.value(action.Condition, 0)
+	}
+	return str
+}
+
+// Prints a value, wrapping it in parentheses if it binds looser than minPrec.
+func (p *aplTextPrinter) value(value *proto.APLValue, minPrec int) string {
+	str, prec := p.valueBody(value)
+	if value.GetUuid() != nil && !p.options.OmitUUIDs {
+		if prec < aplTextPrecPrimary {
+			str = "(" + str + ")"
+		}
+		str += "@" + strconv.Quote(value.Uuid.Value)
+		prec = aplTextPrecPrimary
+	}
+	if prec < minPrec {
+		return "(" + str + ")"
+	}
+	return str
+}
+
+func (p *aplTextPrinter) valueBody(value *proto.APLValue) (string, int) {
+	switch v := value.GetValue().(type) {
+	case nil:
+		return "none", aplTextPrecPrimary
+	case *proto.APLValue_Const:
+		if v.Const.Val == "true" || v.Const.Val == "false" || aplTextBareConstPattern.MatchString(v.Const.Val) {
+			return v.Const.Val, aplTextPrecPrimary
+		}
+		return strconv.Quote(v.Const.Val), aplTextPrecPrimary
+	case *proto.APLValue_And:
+		if len(v.And.Vals) >= 2 {
+			return p.joinValues(v.And.Vals, " and ", aplTextPrecAnd+1), aplTextPrecAnd
+		}
+	case *proto.APLValue_Or:
+		if len(v.Or.Vals) >= 2 {
+			return p.joinValues(v.Or.Vals, " or ", aplTextPrecOr+1), aplTextPrecOr
+		}
+	case *proto.APLValue_Not:
+		if v.Not.Val != nil {
+			return "not " + p.value(v.Not.Val, aplTextPrecNot), aplTextPrecNot
+		}
+		return "not()", aplTextPrecPrimary
+	case *proto.APLValue_Cmp:
+		if opStr, ok := aplTextCompareOps[v.Cmp.Op]; ok && v.Cmp.Lhs != nil && v.Cmp.Rhs != nil {
+			return p.value(v.Cmp.Lhs, aplTextPrecCmp+1) + " " + opStr + " " + p.value(v.Cmp.Rhs, aplTextPrecCmp+1), aplTextPrecCmp
+		}
+	case *proto.APLValue_Math:
+		if opStr, ok := aplTextMathOps[v.Math.Op]; ok && v.Math.Lhs != nil && v.Math.Rhs != nil {
+			prec := aplTextMathPrec(v.Math.Op)
+			return p.value(v.Math.Lhs, prec) + " " + opStr + " " + p.value(v.Math.Rhs, prec+1), prec
+		}
+	}
+
+	msg := value.ProtoReflect()
+	fd := msg.WhichOneof(aplValueOneof)
+	return p.call(string(fd.Name()), msg.Get(fd).Message()), aplTextPrecPrimary
+}
+
+func (p *aplTextPrinter) joinValues(values []*proto.APLValue, sep string, minPrec int) string {
+	return strings.Join(MapSlice(values, func(value *proto.APLValue) string {
+		return p.value(value, minPrec)
+	}), sep)
+}
+
+func (p *aplTextPrinter) call(name string, msg protoreflect.Message) string {
+	args := p.args(msg)
+	if len(args) == 0 {
+		return name
+	}
+	return name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (p *aplTextPrinter) args(msg protoreflect.Message) []string {
+	md := msg.Descriptor()
+	repeatedFd := aplTextSoleRepeatedField(md)
+	actionIDFd := aplTextSoleFieldOfType(md, actionIDDescriptor.FullName())
+	unitFd := aplTextSoleFieldOfType(md, unitReferenceDescriptor.FullName())
+	valueFd := aplTextSoleFieldOfType(md, aplValueDescriptor.FullName())
+
+	var args []string
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !msg.Has(fd) {
+			continue
+		}
+
+		v := msg.Get(fd)
+		switch {
+		case fd == repeatedFd:
+			list := v.List()
+			for j := 0; j < list.Len(); j++ {
+				args = append(args, p.single(fd, list.Get(j)))
+			}
+			continue
+		case fd == actionIDFd:
+			if str, ok := p.actionIDLiteral(v.Message().Interface().(*proto.ActionID)); ok {
+				args = append(args, str)
+				continue
+			}
+		case fd == unitFd:
+			if str, ok := p.unitLiteral(v.Message().Interface().(*proto.UnitReference)); ok {
+				args = append(args, str)
+				continue
+			}
+		case fd == valueFd:
+			args = append(args, p.value(v.Message().Interface().(*proto.APLValue), 0))
+			continue
+		}
+		args = append(args, string(fd.Name())+": "+p.field(fd, v))
+	}
+	return args
+}
+
+func (p *aplTextPrinter) field(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	if !fd.IsList() {
+		return p.single(fd, v)
+	}
+	list := v.List()
+	elements := make([]string, list.Len())
+	for i := range elements {
+		elements[i] = p.single(fd, list.Get(i))
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+func (p *aplTextPrinter) single(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return strconv.FormatBool(v.Bool())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(v.Int(), 10)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(v.Uint(), 10)
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case protoreflect.StringKind:
+		return strconv.Quote(v.String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(v.Bytes()))
+	case protoreflect.EnumKind:
+		return aplTextEnumName(fd.Enum(), v.Enum())
+	default:
+		return p.message(v.Message())
+	}
+}
+
+func (p *aplTextPrinter) message(msg protoreflect.Message) string {
+	switch m := msg.Interface().(type) {
+	case *proto.APLValue:
+		return p.value(m, 0)
+	case *proto.APLAction:
+		return p.action(m)
+	case *proto.ActionID:
+		if str, ok := p.actionIDLiteral(m); ok {
+			return str
+		}
+	case *proto.UnitReference:
+		if str, ok := p.unitLiteral(m); ok {
+			return str
+		}
+	}
+
+	md := msg.Descriptor()
+	var args []string
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if msg.Has(fd) {
+			args = append(args, string(fd.Name())+": "+p.field(fd, msg.Get(fd)))
+		}
+	}
+	return "{" + strings.Join(args, ", ") + "}"
+}
+
+func (p *aplTextPrinter) actionIDLiteral(actionID *proto.ActionID) (string, bool) {
+	var str string
+	switch id := actionID.RawId.(type) {
+	case *proto.ActionID_SpellId:
+		str = "spell:" + strconv.Itoa(int(id.SpellId))
+	case *proto.ActionID_ItemId:
+		str = "item:" + strconv.Itoa(int(id.ItemId))
+	case *proto.ActionID_OtherId:
+		str = "other:" + aplTextEnumName(id.OtherId.Descriptor(), id.OtherId.Number())
+	default:
+		return "", false
+	}
+	if actionID.Tag != 0 {
+		str += ":" + strconv.Itoa(int(actionID.Tag))
+	}
+	return str, true
+}
+
+func (p *aplTextPrinter) unitLiteral(unit *proto.UnitReference) (string, bool) {
+	if unit.Owner != nil || unitReferenceTypeValues.ByNumber(protoreflect.EnumNumber(unit.Type)) == nil {
+		return "", false
+	}
+	str := unit.Type.String()
+	if unit.Index != 0 {
+		str += ":" + strconv.Itoa(int(unit.Index))
+	}
+	return str, true
+}
+
+func aplTextEnumName(ed protoreflect.EnumDescriptor, number protoreflect.EnumNumber) string {
+	if value := ed.Values().ByNumber(number); value != nil {
+		return string(value.Name())
+	}
+	return strconv.Itoa(int(number))
+}
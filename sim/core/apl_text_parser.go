@@ -0,0 +1,678 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type aplTextTokenKind int
+
+const (
+	aplTokenEOF aplTextTokenKind = iota
+	aplTokenNewline
+	aplTokenNote
+	aplTokenIdent
+	aplTokenNumber
+	aplTokenString
+	aplTokenPunct
+)
+
+type aplTextToken struct {
+	kind aplTextTokenKind
+	text string
+
+	line int
+	col  int
+	// Byte offsets into the source, used to check whether tokens are adjacent.
+	start int
+	end   int
+}
+
+func (tok aplTextToken) is(kind aplTextTokenKind, text string) bool {
+	return tok.kind == kind && tok.text == text
+}
+
+func (tok aplTextToken) String() string {
+	switch tok.kind {
+	case aplTokenEOF:
+		return "end of input"
+	case aplTokenNewline:
+		return "end of line"
+	default:
+		return strconv.Quote(tok.text)
+	}
+}
+
+type aplTextError struct {
+	line int
+	col  int
+	msg  string
+}
+
+func (err *aplTextError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", err.line, err.col, err.msg)
+}
+
+// Keywords after which a '-' starts a negative number rather than a subtraction.
+var aplTextPrefixKeywords = map[string]bool{
+	"prepull": true,
+	"hide":    true,
+	"if":      true,
+	"not":     true,
+	"and":     true,
+	"or":      true,
+}
+
+func lexAPLText(text string) ([]aplTextToken, error) {
+	var tokens []aplTextToken
+	line, lineStart := 1, 0
+	depth := 0
+	atLineStart := true
+
+	isOperandEnd := func() bool {
+		if len(tokens) == 0 {
+			return false
+		}
+		prev := tokens[len(tokens)-1]
+		switch prev.kind {
+		case aplTokenIdent:
+			return !aplTextPrefixKeywords[prev.text]
+		case aplTokenNumber, aplTokenString:
+			return true
+		case aplTokenPunct:
+			return prev.text == ")" || prev.text == "]" || prev.text == "}"
+		}
+		return false
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		col := i - lineStart + 1
+		emit := func(kind aplTextTokenKind, end int, value string) {
+			tokens = append(tokens, aplTextToken{kind: kind, text: value, line: line, col: col, start: i, end: end})
+			i = end
+			atLineStart = false
+		}
+
+		switch {
+		case c == '\n':
+			if depth == 0 && len(tokens) > 0 && tokens[len(tokens)-1].kind != aplTokenNewline {
+				emit(aplTokenNewline, i+1, "\n")
+			} else {
+				i++
+			}
+			line, lineStart = line+1, i
+			atLineStart = true
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case c == '/' && atLineStart && depth == 0 && strings.HasPrefix(text[i:], "//"):
+			end := strings.IndexByte(text[i:], '\n')
+			if end == -1 {
+				end = len(text)
+			} else {
+				end += i
+			}
+			note := strings.TrimPrefix(strings.TrimSuffix(text[i+2:end], "\r"), " ")
+			emit(aplTokenNote, end, note)
+		case c == '"':
+			end := i + 1
+			for end < len(text) && text[end] != '"' && text[end] != '\n' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(text) || text[end] != '"' {
+				return nil, &aplTextError{line: line, col: col, msg: "unterminated string"}
+			}
+			value, err := strconv.Unquote(text[i : end+1])
+			if err != nil {
+				return nil, &aplTextError{line: line, col: col, msg: "invalid string: " + err.Error()}
+			}
+			emit(aplTokenString, end+1, value)
+		case isDigit(c) || (c == '-' && i+1 < len(text) && isDigit(text[i+1]) && !isOperandEnd()):
+			end := i + 1
+			for end < len(text) && (isIdentChar(text[end]) || text[end] == '.' || text[end] == '%') {
+				end++
+			}
+			emit(aplTokenNumber, end, text[i:end])
+		case isIdentChar(c):
+			end := i + 1
+			for end < len(text) && isIdentChar(text[end]) {
+				end++
+			}
+			emit(aplTokenIdent, end, text[i:end])
+		default:
+			if i+1 < len(text) {
+				switch two := text[i : i+2]; two {
+				case "==", "!=", "<=", ">=":
+					emit(aplTokenPunct, i+2, two)
+					continue
+				}
+			}
+			if !strings.ContainsRune("<>+-*/()[]{},:@", rune(c)) {
+				return nil, &aplTextError{line: line, col: col, msg: fmt.Sprintf("unexpected character %q", rune(c))}
+			}
+			switch c {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				depth = max(0, depth-1)
+			}
+			emit(aplTokenPunct, i+1, string(c))
+		}
+	}
+
+	tokens = append(tokens, aplTextToken{kind: aplTokenEOF, line: line, col: len(text) - lineStart + 1, start: len(text), end: len(text)})
+	return tokens, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || isDigit(c) || unicode.IsLetter(rune(c))
+}
+
+type aplTextParser struct {
+	tokens []aplTextToken
+	pos    int
+}
+
+func (p *aplTextParser) peek() aplTextToken {
+	return p.tokens[p.pos]
+}
+
+func (p *aplTextParser) peekAt(offset int) aplTextToken {
+	return p.tokens[min(p.pos+offset, len(p.tokens)-1)]
+}
+
+func (p *aplTextParser) next() aplTextToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != aplTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *aplTextParser) accept(kind aplTextTokenKind, text string) bool {
+	if p.peek().is(kind, text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *aplTextParser) expect(kind aplTextTokenKind, text string) aplTextToken {
+	tok := p.next()
+	if !tok.is(kind, text) {
+		p.fail(tok, "expected %q, got %s", text, tok)
+	}
+	return tok
+}
+
+func (p *aplTextParser) expectKind(kind aplTextTokenKind, what string) aplTextToken {
+	tok := p.next()
+	if tok.kind != kind {
+		p.fail(tok, "expected %s, got %s", what, tok)
+	}
+	return tok
+}
+
+func (p *aplTextParser) fail(tok aplTextToken, msg string, vals ...interface{}) {
+	panic(&aplTextError{line: tok.line, col: tok.col, msg: fmt.Sprintf(msg, vals...)})
+}
+
+func (p *aplTextParser) parseRotation() *proto.APLRotation {
+	rotation := &proto.APLRotation{}
+	msg := rotation.ProtoReflect()
+
+	var notes []string
+	var notesTok aplTextToken
+	for {
+		tok := p.peek()
+		switch tok.kind {
+		case aplTokenEOF:
+			if len(notes) > 0 {
+				p.fail(notesTok, "notes must be followed by a priority list item")
+			}
+			return rotation
+		case aplTokenNewline:
+			p.next()
+			continue
+		case aplTokenNote:
+			if len(notes) == 0 {
+				notesTok = tok
+			}
+			notes = append(notes, p.next().text)
+			continue
+		}
+
+		hide := p.accept(aplTokenIdent, "hide")
+		tok = p.peek()
+		if tok.is(aplTokenIdent, "prepull") {
+			if len(notes) > 0 {
+				p.fail(notesTok, "notes are only supported on priority list items")
+			}
+			p.next()
+			item := &proto.APLPrepullAction{Hide: hide}
+			if !p.peek().is(aplTokenPunct, ":") {
+				item.DoAtValue = p.parseValue()
+			}
+			p.expect(aplTokenPunct, ":")
+			item.Action = p.parseStatementAction()
+			rotation.PrepullActions = append(rotation.PrepullActions, item)
+		} else if fd := p.rotationField(tok); fd != nil && !hide {
+			if len(notes) > 0 {
+				p.fail(notesTok, "notes are only supported on priority list items")
+			}
+			p.next()
+			p.expect(aplTokenPunct, ":")
+			if msg.Has(fd) {
+				p.fail(tok, "duplicate field %q", fd.Name())
+			}
+			p.parseField(msg, fd)
+		} else {
+			item := &proto.APLListItem{Hide: hide, Notes: strings.Join(notes, "\n")}
+			item.Action = p.parseStatementAction()
+			rotation.PriorityList = append(rotation.PriorityList, item)
+			notes = nil
+		}
+
+		if end := p.next(); end.kind != aplTokenNewline && end.kind != aplTokenEOF {
+			p.fail(end, "unexpected %s", end)
+		}
+	}
+}
+
+// Returns the rotation-level field named by tok, if tok starts a 'field: value' statement.
+func (p *aplTextParser) rotationField(tok aplTextToken) protoreflect.FieldDescriptor {
+	if tok.kind != aplTokenIdent || !p.peekAt(1).is(aplTokenPunct, ":") {
+		return nil
+	}
+	name := protoreflect.Name(tok.text)
+	if name == aplRotationListFieldNames[0] || name == aplRotationListFieldNames[1] {
+		return nil
+	}
+	return (&proto.APLRotation{}).ProtoReflect().Descriptor().Fields().ByName(name)
+}
+
+func (p *aplTextParser) parseStatementAction() *proto.APLAction {
+	if p.accept(aplTokenIdent, "nil") {
+		return nil
+	}
+	return p.parseAction()
+}
+
+func (p *aplTextParser) parseAction() *proto.APLAction {
+	action := &proto.APLAction{}
+	tok := p.expectKind(aplTokenIdent, "action")
+	if tok.text != "none" {
+		fd := aplActionOneof.Fields().ByName(protoreflect.Name(tok.text))
+		if fd == nil {
+			p.fail(tok, "unknown action %q", tok.text)
+		}
+		msg := action.ProtoReflect()
+		msg.Set(fd, protoreflect.ValueOfMessage(p.parseCallArgs(msg.NewField(fd).Message())))
+	}
+
+	if p.accept(aplTokenIdent, "if") {
+		action.Condition = p.parseValue()
+	}
+	return action
+}
+
+func (p *aplTextParser) parseValue() *proto.APLValue {
+	return p.parseOr()
+}
+
+func (p *aplTextParser) parseOr() *proto.APLValue {
+	value := p.parseAnd()
+	if !p.peek().is(aplTokenIdent, "or") {
+		return value
+	}
+	vals := []*proto.APLValue{value}
+	for p.accept(aplTokenIdent, "or") {
+		vals = append(vals, p.parseAnd())
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Or{Or: &proto.APLValueOr{Vals: vals}}}
+}
+
+func (p *aplTextParser) parseAnd() *proto.APLValue {
+	value := p.parseNot()
+	if !p.peek().is(aplTokenIdent, "and") {
+		return value
+	}
+	vals := []*proto.APLValue{value}
+	for p.accept(aplTokenIdent, "and") {
+		vals = append(vals, p.parseNot())
+	}
+	return &proto.APLValue{Value: &proto.APLValue_And{And: &proto.APLValueAnd{Vals: vals}}}
+}
+
+func (p *aplTextParser) parseNot() *proto.APLValue {
+	// 'not()' is an empty not value, handled as a call in parsePrimary.
+	if p.peek().is(aplTokenIdent, "not") && !(p.peekAt(1).is(aplTokenPunct, "(") && p.peekAt(2).is(aplTokenPunct, ")")) {
+		p.next()
+		return &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: p.parseNot()}}}
+	}
+	return p.parseCmp()
+}
+
+func (p *aplTextParser) parseCmp() *proto.APLValue {
+	lhs := p.parseMath(aplTextPrecAdd)
+	tok := p.peek()
+	if tok.kind != aplTokenPunct {
+		return lhs
+	}
+	for op, opStr := range aplTextCompareOps {
+		if tok.text == opStr {
+			p.next()
+			rhs := p.parseMath(aplTextPrecAdd)
+			return &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{Op: op, Lhs: lhs, Rhs: rhs}}}
+		}
+	}
+	return lhs
+}
+
+func (p *aplTextParser) parseMath(prec int) *proto.APLValue {
+	if prec > aplTextPrecMul {
+		return p.parsePostfix()
+	}
+
+	lhs := p.parseMath(prec + 1)
+	for {
+		tok := p.peek()
+		matched := false
+		if tok.kind == aplTokenPunct {
+			for op, opStr := range aplTextMathOps {
+				if tok.text == opStr && aplTextMathPrec(op) == prec {
+					p.next()
+					rhs := p.parseMath(prec + 1)
+					lhs = &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{Op: op, Lhs: lhs, Rhs: rhs}}}
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return lhs
+		}
+	}
+}
+
+func (p *aplTextParser) parsePostfix() *proto.APLValue {
+	value := p.parsePrimary()
+	if p.accept(aplTokenPunct, "@") {
+		uuid := p.expectKind(aplTokenString, "uuid string")
+		value.Uuid = &proto.UUID{Value: uuid.text}
+	}
+	return value
+}
+
+func (p *aplTextParser) parsePrimary() *proto.APLValue {
+	tok := p.next()
+	switch tok.kind {
+	case aplTokenNumber, aplTokenString:
+		return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: tok.text}}}
+	case aplTokenPunct:
+		if tok.text == "(" {
+			value := p.parseValue()
+			p.expect(aplTokenPunct, ")")
+			return value
+		}
+	case aplTokenIdent:
+		switch tok.text {
+		case "true", "false":
+			return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: tok.text}}}
+		case "none":
+			return &proto.APLValue{}
+		}
+		fd := aplValueOneof.Fields().ByName(protoreflect.Name(tok.text))
+		if fd == nil {
+			p.fail(tok, "unknown value %q", tok.text)
+		}
+		value := &proto.APLValue{}
+		msg := value.ProtoReflect()
+		msg.Set(fd, protoreflect.ValueOfMessage(p.parseCallArgs(msg.NewField(fd).Message())))
+		return value
+	}
+	p.fail(tok, "expected value, got %s", tok)
+	return nil
+}
+
+// Parses the optional parenthesized argument list of an action or value.
+func (p *aplTextParser) parseCallArgs(msg protoreflect.Message) protoreflect.Message {
+	if p.accept(aplTokenPunct, "(") {
+		p.parseArgs(msg, ")")
+	}
+	return msg
+}
+
+// Parses comma-separated arguments into msg, up to and including the closing token.
+func (p *aplTextParser) parseArgs(msg protoreflect.Message, closing string) {
+	md := msg.Descriptor()
+	repeatedFd := aplTextSoleRepeatedField(md)
+	actionIDFd := aplTextSoleFieldOfType(md, actionIDDescriptor.FullName())
+	unitFd := aplTextSoleFieldOfType(md, unitReferenceDescriptor.FullName())
+	valueFd := aplTextSoleFieldOfType(md, aplValueDescriptor.FullName())
+
+	for !p.accept(aplTokenPunct, closing) {
+		tok := p.peek()
+
+		var fd protoreflect.FieldDescriptor
+		switch {
+		case p.isActionIDLiteral() && actionIDFd != nil:
+			fd = actionIDFd
+		case p.isUnitLiteral() && unitFd != nil:
+			fd = unitFd
+		case tok.kind == aplTokenIdent && p.peekAt(1).is(aplTokenPunct, ":"):
+			fd = md.Fields().ByName(protoreflect.Name(tok.text))
+			if fd == nil {
+				p.fail(tok, "unknown field %q for %s", tok.text, md.Name())
+			}
+			p.next()
+			p.next()
+		case repeatedFd != nil:
+			msg.Mutable(repeatedFd).List().Append(p.parseSingle(msg, repeatedFd))
+		case valueFd != nil:
+			fd = valueFd
+		default:
+			p.fail(tok, "expected 'field: value' argument for %s, got %s", md.Name(), tok)
+		}
+
+		if fd != nil {
+			if msg.Has(fd) {
+				p.fail(tok, "duplicate field %q", fd.Name())
+			}
+			p.parseField(msg, fd)
+		}
+
+		if !p.accept(aplTokenPunct, ",") {
+			p.expect(aplTokenPunct, closing)
+			return
+		}
+	}
+}
+
+func (p *aplTextParser) parseField(msg protoreflect.Message, fd protoreflect.FieldDescriptor) {
+	if !fd.IsList() {
+		msg.Set(fd, p.parseSingle(msg, fd))
+		return
+	}
+
+	p.expect(aplTokenPunct, "[")
+	list := msg.Mutable(fd).List()
+	for !p.accept(aplTokenPunct, "]") {
+		list.Append(p.parseSingle(msg, fd))
+		if !p.accept(aplTokenPunct, ",") {
+			p.expect(aplTokenPunct, "]")
+			return
+		}
+	}
+}
+
+// Parses a single (non-list) value for the given field of msg.
+func (p *aplTextParser) parseSingle(msg protoreflect.Message, fd protoreflect.FieldDescriptor) protoreflect.Value {
+	tok := p.peek()
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		p.next()
+		if tok.kind == aplTokenIdent && (tok.text == "true" || tok.text == "false") {
+			return protoreflect.ValueOfBool(tok.text == "true")
+		}
+		p.fail(tok, "expected true or false, got %s", tok)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(p.parseInt(32)))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(p.parseInt(64))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(p.parseUint(32)))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(p.parseUint(64))
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(p.parseFloat(32)))
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(p.parseFloat(64))
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(p.expectKind(aplTokenString, "string").text)
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(p.expectKind(aplTokenString, "string").text))
+	case protoreflect.EnumKind:
+		return protoreflect.ValueOfEnum(p.parseEnum(fd.Enum()))
+	default:
+		var newMsg protoreflect.Message
+		if fd.IsList() {
+			newMsg = msg.Mutable(fd).List().NewElement().Message()
+		} else {
+			newMsg = msg.NewField(fd).Message()
+		}
+		return protoreflect.ValueOfMessage(p.parseMessage(newMsg))
+	}
+	return protoreflect.Value{}
+}
+
+func (p *aplTextParser) parseMessage(msg protoreflect.Message) protoreflect.Message {
+	switch msg.Interface().(type) {
+	case *proto.APLValue:
+		return p.parseValue().ProtoReflect()
+	case *proto.APLAction:
+		return p.parseAction().ProtoReflect()
+	case *proto.ActionID:
+		if p.isActionIDLiteral() {
+			return p.parseActionIDLiteral().ProtoReflect()
+		}
+	case *proto.UnitReference:
+		if p.isUnitLiteral() {
+			return p.parseUnitLiteral().ProtoReflect()
+		}
+	}
+
+	p.expect(aplTokenPunct, "{")
+	p.parseArgs(msg, "}")
+	return msg
+}
+
+// Whether the next tokens form a literal like 'spell:123', with no spaces around the ':'.
+func (p *aplTextParser) isActionIDLiteral() bool {
+	tok := p.peek()
+	colon := p.peekAt(1)
+	_, ok := aplTextActionIDLiteralKeys[tok.text]
+	return ok && tok.kind == aplTokenIdent && colon.is(aplTokenPunct, ":") && colon.start == tok.end && p.peekAt(2).start == colon.end
+}
+
+func (p *aplTextParser) parseActionIDLiteral() *proto.ActionID {
+	kind := p.next()
+	p.next()
+	actionID := &proto.ActionID{}
+	msg := actionID.ProtoReflect()
+	fd := msg.Descriptor().Fields().ByName(aplTextActionIDLiteralKeys[kind.text])
+	msg.Set(fd, p.parseSingle(msg, fd))
+	if p.parseLiteralSuffix() {
+		actionID.Tag = int32(p.parseInt(32))
+	}
+	return actionID
+}
+
+func (p *aplTextParser) isUnitLiteral() bool {
+	tok := p.peek()
+	return tok.kind == aplTokenIdent && unitReferenceTypeValues.ByName(protoreflect.Name(tok.text)) != nil
+}
+
+func (p *aplTextParser) parseUnitLiteral() *proto.UnitReference {
+	tok := p.next()
+	unit := &proto.UnitReference{
+		Type: proto.UnitReference_Type(unitReferenceTypeValues.ByName(protoreflect.Name(tok.text)).Number()),
+	}
+	if p.parseLiteralSuffix() {
+		unit.Index = int32(p.parseInt(32))
+	}
+	return unit
+}
+
+// Consumes a ':' directly attached to the previous token, as used by action ID and unit literals.
+func (p *aplTextParser) parseLiteralSuffix() bool {
+	prev := p.tokens[p.pos-1]
+	colon := p.peek()
+	if colon.is(aplTokenPunct, ":") && colon.start == prev.end && p.peekAt(1).start == colon.end && p.peekAt(1).kind == aplTokenNumber {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *aplTextParser) parseEnum(ed protoreflect.EnumDescriptor) protoreflect.EnumNumber {
+	tok := p.next()
+	switch tok.kind {
+	case aplTokenIdent:
+		if value := ed.Values().ByName(protoreflect.Name(tok.text)); value != nil {
+			return value.Number()
+		}
+		p.fail(tok, "unknown %s value %q", ed.Name(), tok.text)
+	case aplTokenNumber:
+		number, err := strconv.ParseInt(tok.text, 10, 32)
+		if err != nil {
+			p.fail(tok, "invalid %s value %q", ed.Name(), tok.text)
+		}
+		return protoreflect.EnumNumber(number)
+	}
+	p.fail(tok, "expected %s value, got %s", ed.Name(), tok)
+	return 0
+}
+
+func (p *aplTextParser) parseInt(bitSize int) int64 {
+	tok := p.expectKind(aplTokenNumber, "integer")
+	value, err := strconv.ParseInt(tok.text, 10, bitSize)
+	if err != nil {
+		p.fail(tok, "invalid integer %q", tok.text)
+	}
+	return value
+}
+
+func (p *aplTextParser) parseUint(bitSize int) uint64 {
+	tok := p.expectKind(aplTokenNumber, "integer")
+	value, err := strconv.ParseUint(tok.text, 10, bitSize)
+	if err != nil {
+		p.fail(tok, "invalid integer %q", tok.text)
+	}
+	return value
+}
+
+func (p *aplTextParser) parseFloat(bitSize int) float64 {
+	tok := p.expectKind(aplTokenNumber, "number")
+	value, err := strconv.ParseFloat(tok.text, bitSize)
+	if err != nil {
+		p.fail(tok, "invalid number %q", tok.text)
+	}
+	return value
+}
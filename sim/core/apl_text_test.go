@@ -0,0 +1,91 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestAPLTextRoundTripsPresetRotations(t *testing.T) {
+	files, err := filepath.Glob("../../ui/*/*/apls/*.apl.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Skip("no preset APL files found")
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Some presets still carry fields that were since removed from the proto.
+		want := &proto.APLRotation{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, want); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		text := FormatAPLText(want, APLTextOptions{})
+		got, err := ParseAPLText(text)
+		if err != nil {
+			t.Fatalf("%s: failed to parse formatted rotation: %v\n%s", file, err, text)
+		}
+		if !googleProto.Equal(got, want) {
+			t.Fatalf("%s: rotation changed after round trip\n%s", file, text)
+		}
+		if reformatted := FormatAPLText(got, APLTextOptions{}); reformatted != text {
+			t.Fatalf("%s: formatting is not stable\n%s\n---\n%s", file, text, reformatted)
+		}
+	}
+}
+
+func TestAPLTextParsesOperators(t *testing.T) {
+	rotation, err := ParseAPLText(`
+type: TypeAPL
+prepull -1.5s: cast_spell(spell:8050)
+
+# Comments are ignored.
+// Keep up the dot.
+cast_spell(spell:8050) if not dot_is_active(spell:8050) or dot_remaining_time(spell:8050) < 3s - 1s * 2
+hide wait(1s)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rotation.Type != proto.APLRotation_TypeAPL || len(rotation.PrepullActions) != 1 || len(rotation.PriorityList) != 2 {
+		t.Fatalf("unexpected rotation: %v", rotation)
+	}
+	if rotation.PrepullActions[0].DoAtValue.GetConst().GetVal() != "-1.5s" {
+		t.Fatalf("unexpected prepull timing: %v", rotation.PrepullActions[0].DoAtValue)
+	}
+
+	item := rotation.PriorityList[0]
+	if item.Notes != "Keep up the dot." || item.Action.GetCastSpell().GetSpellId().GetSpellId() != 8050 {
+		t.Fatalf("unexpected item: %v", item)
+	}
+	or := item.Action.Condition.GetOr()
+	if len(or.GetVals()) != 2 || or.Vals[0].GetNot() == nil {
+		t.Fatalf("unexpected condition: %v", item.Action.Condition)
+	}
+	sub := or.Vals[1].GetCmp().GetRhs().GetMath()
+	if sub.GetOp() != proto.APLValueMath_OpSub || sub.Rhs.GetMath().GetOp() != proto.APLValueMath_OpMul {
+		t.Fatalf("unexpected math precedence: %v", or.Vals[1])
+	}
+	if !rotation.PriorityList[1].Hide {
+		t.Fatalf("expected hidden item")
+	}
+}
+
+func TestAPLTextReportsErrorPosition(t *testing.T) {
+	_, err := ParseAPLText("cast_spell(spell:1)\ncast_spel(spell:2)\n")
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2, column 1:") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
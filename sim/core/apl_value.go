@@ -166,6 +166,10 @@ func (rot *APLRotation) newAPLValue(config *proto.APLValue) APLValue {
 		value = rot.newValueUnitIsMoving(config.GetUnitIsMoving(), config.Uuid)
 	case *proto.APLValue_UnitDistance:
 		value = rot.newValueUnitDistance(config.GetUnitDistance(), config.Uuid)
+	case *proto.APLValue_TargetDistance:
+		value = rot.newValueTargetDistance(config.GetTargetDistance(), config.Uuid)
+	case *proto.APLValue_TargetsInRadius:
+		value = rot.newValueTargetsInRadius(config.GetTargetsInRadius(), config.Uuid)
 
 	// GCD
 	case *proto.APLValue_GcdIsReady:
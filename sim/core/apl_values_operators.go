@@ -262,6 +262,14 @@ func getConstAPLFloatValue(value APLValue) float64 {
 	return -1
 }
 
+// Numeric types can be compared with each other, while bools and strings are only comparable to themselves.
+func aplValueTypesComparable(type1 proto.APLValueType, type2 proto.APLValueType) bool {
+	isNumeric := func(valueType proto.APLValueType) bool {
+		return valueType == proto.APLValueType_ValueTypeInt || valueType == proto.APLValueType_ValueTypeFloat || valueType == proto.APLValueType_ValueTypeDuration
+	}
+	return type1 == type2 || (isNumeric(type1) && isNumeric(type2))
+}
+
 type APLValueCompare struct {
 	DefaultAPLValueImpl
 	op  proto.APLValueCompare_ComparisonOperator
@@ -270,12 +278,17 @@ type APLValueCompare struct {
 }
 
 func (rot *APLRotation) newValueCompare(config *proto.APLValueCompare, uuid *proto.UUID) APLValue {
-	lhs, rhs := rot.coerceToSameType(rot.newAPLValue(config.Lhs), rot.newAPLValue(config.Rhs))
-
+	lhs, rhs := rot.newAPLValue(config.Lhs), rot.newAPLValue(config.Rhs)
 	if lhs == nil || rhs == nil {
 		return nil
 	}
 
+	if !aplValueTypesComparable(lhs.Type(), rhs.Type()) {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "Comparing %s with %s will not give a meaningful result!",
+			strings.TrimPrefix(lhs.Type().String(), "ValueType"), strings.TrimPrefix(rhs.Type().String(), "ValueType"))
+	}
+	lhs, rhs = rot.coerceToSameType(lhs, rhs)
+
 	if lhs.Type() == proto.APLValueType_ValueTypeBool && !(config.Op == proto.APLValueCompare_OpEq || config.Op == proto.APLValueCompare_OpNe) {
 		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "Bool types only allow Equals and NotEquals comparisons!")
 		return nil
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"fmt"
+
 	"github.com/wowsims/mop/sim/core/proto"
 )
 
@@ -47,3 +49,59 @@ func (value *APLValueUnitDistance) GetFloat(sim *Simulation) float64 {
 func (value *APLValueUnitDistance) String() string {
 	return "Unit Distance From Target"
 }
+
+type APLValueTargetDistance struct {
+	DefaultAPLValueImpl
+	unit   *Unit
+	target UnitReference
+}
+
+func (rot *APLRotation) newValueTargetDistance(config *proto.APLValueTargetDistance, _ *proto.UUID) APLValue {
+	target := rot.GetTargetUnit(config.TargetUnit)
+	if target.Get() == nil {
+		return nil
+	}
+	return &APLValueTargetDistance{
+		unit:   rot.unit,
+		target: target,
+	}
+}
+func (value *APLValueTargetDistance) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueTargetDistance) GetFloat(sim *Simulation) float64 {
+	return value.unit.DistanceTo(sim, value.target.Get())
+}
+func (value *APLValueTargetDistance) String() string {
+	return fmt.Sprintf("Distance to %s", value.target.String())
+}
+
+type APLValueTargetsInRadius struct {
+	DefaultAPLValueImpl
+	center UnitReference
+	radius float64
+}
+
+func (rot *APLRotation) newValueTargetsInRadius(config *proto.APLValueTargetsInRadius, _ *proto.UUID) APLValue {
+	center := rot.GetSourceUnit(config.CenterUnit)
+	if center.Get() == nil {
+		return nil
+	}
+	if config.Radius <= 0 {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Targets in Radius needs a radius above 0")
+		return nil
+	}
+	return &APLValueTargetsInRadius{
+		center: center,
+		radius: config.Radius,
+	}
+}
+func (value *APLValueTargetsInRadius) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeInt
+}
+func (value *APLValueTargetsInRadius) GetInt(sim *Simulation) int32 {
+	return sim.Encounter.NumTargetsInRadius(sim, value.center.Get().GetPosition(sim), value.radius)
+}
+func (value *APLValueTargetsInRadius) String() string {
+	return fmt.Sprintf("Targets within %.1f yards of %s", value.radius, value.center.String())
+}
@@ -203,7 +203,12 @@ func (aura *Aura) SetStacks(sim *Simulation, newStacks int32) {
 	}
 
 	if sim.Log != nil {
-		aura.Unit.Log(sim, "%s stacks: %d --> %d", aura.ActionID, oldStacks, newStacks)
+		aura.Unit.LogEvent(sim, &proto.CombatLogEvent{
+			Type:      proto.CombatLogEventType_CombatLogEventAuraStacks,
+			ActionId:  aura.ActionID.ToProto(),
+			OldStacks: oldStacks,
+			NewStacks: newStacks,
+		}, "%s stacks: %d --> %d", aura.ActionID, oldStacks, newStacks)
 	}
 	aura.stacks = newStacks
 	if aura.OnStacksChange != nil {
@@ -647,7 +652,7 @@ func (aura *Aura) Activate(sim *Simulation) {
 	aura.metrics.Procs++
 	if aura.IsActive() {
 		if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
-			aura.Unit.Log(sim, "Aura refreshed: %s", aura.ActionID)
+			aura.logEvent(sim, proto.CombatLogEventType_CombatLogEventAuraRefreshed, "Aura refreshed: %s")
 		}
 		aura.Refresh(sim)
 		return
@@ -737,7 +742,7 @@ func (aura *Aura) Activate(sim *Simulation) {
 	}
 
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
-		aura.Unit.Log(sim, "Aura gained: %s", aura.ActionID)
+		aura.logEvent(sim, proto.CombatLogEventType_CombatLogEventAuraGained, "Aura gained: %s")
 	}
 
 	// don't invoke possible callbacks until the internal state is consistent
@@ -766,7 +771,7 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 		oldTime := sim.CurrentTime
 		sim.CurrentTime = min(sim.CurrentTime, aura.expires)
 		if sim.Log != nil {
-			aura.Unit.Log(sim, "Aura faded: %s", aura.ActionID)
+			aura.logEvent(sim, proto.CombatLogEventType_CombatLogEventAuraFaded, "Aura faded: %s")
 		}
 		sim.CurrentTime = oldTime
 	}
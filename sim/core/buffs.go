@@ -1,6 +1,7 @@
 package core
 
 import (
+	"slices"
 	"time"
 
 	googleProto "google.golang.org/protobuf/proto"
@@ -190,28 +191,28 @@ func applyBuffEffects(agent Agent, raidBuffs *proto.RaidBuffs, _ *proto.PartyBuf
 	// Stamina & Strength/Agility secondary grouping
 	applyStaminaBuffs(u, raidBuffs)
 
-	registerManaTideTotemCD(agent, raidBuffs.ManaTideTotemCount)
-	registerSkullBannerCD(agent, raidBuffs.SkullBannerCount)
-	registerStormLashCD(agent, raidBuffs.StormlashTotemCount)
+	registerManaTideTotemCD(agent, raidBuffs.ManaTideTotemCount, raidBuffs.ManaTideTotemSchedule)
+	registerSkullBannerCD(agent, raidBuffs.SkullBannerCount, raidBuffs.SkullBannerSchedule)
+	registerStormLashCD(agent, raidBuffs.StormlashTotemCount, raidBuffs.StormlashTotemSchedule)
 
 	// Individual cooldowns and major buffs
 	if len(char.Env.Raid.AllPlayerUnits)-char.Env.Raid.NumTargetDummies == 1 {
 		// Major Haste
 		if raidBuffs.Bloodlust {
-			registerBloodlustCD(agent, 2825)
+			registerBloodlustCD(agent, 2825, raidBuffs.BloodlustSchedule)
 		}
 
 		// Other individual CDs
-		registerUnholyFrenzyCD(agent, individual.UnholyFrenzyCount)
+		registerUnholyFrenzyCD(agent, individual.UnholyFrenzyCount, individual.UnholyFrenzySchedule)
 		if individual.TricksOfTheTrade {
-			registerTricksOfTheTradeCD(agent)
+			registerTricksOfTheTradeCD(agent, individual.TricksOfTheTradeSchedule)
 		}
 		registerDevotionAuraCD(agent, individual.DevotionAuraCount)
 		registerVigilanceCD(agent, individual.VigilanceCount)
 		registerPainSuppressionCD(agent, individual.PainSuppressionCount)
 		registerGuardianSpiritCD(agent, individual.GuardianSpiritCount)
 		registerRallyingCryCD(agent, individual.RallyingCryCount)
-		registerShatteringThrowCD(agent, individual.ShatteringThrowCount)
+		registerShatteringThrowCD(agent, individual.ShatteringThrowCount, individual.ShatteringThrowSchedule)
 	}
 }
 
@@ -552,10 +553,67 @@ type externalConsecutiveCDApproximation struct {
 	// Callback for extra activation conditions.
 	ShouldActivate CooldownActivationCondition
 
+	// User plan for when the buff is applied, on top of ShouldActivate. Optional.
+	Schedule *proto.ExternalCooldownSchedule
+
 	// Applies the buff.
 	AddAura CooldownActivation
 }
 
+// Tracks an ExternalCooldownSchedule through an iteration.
+type externalCooldownSchedule struct {
+	timings       []time.Duration
+	executePhase  int32
+	withBloodlust bool
+
+	nextTiming    int
+	usedInExecute bool
+}
+
+// Returns nil if config is nil, i.e. the cooldown isn't scheduled.
+func newExternalCooldownSchedule(character *Character, config *proto.ExternalCooldownSchedule) *externalCooldownSchedule {
+	if config == nil {
+		return nil
+	}
+
+	schedule := &externalCooldownSchedule{
+		timings:       MapSlice(config.Timings, DurationFromSeconds),
+		executePhase:  config.ExecutePhase,
+		withBloodlust: config.WithBloodlust,
+	}
+	slices.Sort(schedule.timings)
+	character.RegisterResetEffect(func(_ *Simulation) {
+		schedule.nextTiming = 0
+		schedule.usedInExecute = false
+	})
+	return schedule
+}
+
+func (schedule *externalCooldownSchedule) shouldActivate(sim *Simulation, character *Character) bool {
+	if schedule.nextTiming < len(schedule.timings) && sim.CurrentTime >= schedule.timings[schedule.nextTiming] {
+		return true
+	}
+	if schedule.inExecutePhase(sim) && !schedule.usedInExecute {
+		return true
+	}
+	return schedule.withBloodlust && character.HasActiveAuraWithTag(BloodlustAuraTag)
+}
+
+func (schedule *externalCooldownSchedule) inExecutePhase(sim *Simulation) bool {
+	return schedule.executePhase != 0 && sim.executePhase <= schedule.executePhase
+}
+
+// Must be called whenever the cooldown is used.
+func (schedule *externalCooldownSchedule) onUse(sim *Simulation) {
+	if schedule.nextTiming < len(schedule.timings) && sim.CurrentTime >= schedule.timings[schedule.nextTiming] {
+		schedule.nextTiming++
+	}
+	// The execute phase asks for a single use, whatever triggered it.
+	if schedule.inExecutePhase(sim) {
+		schedule.usedInExecute = true
+	}
+}
+
 // numSources is the number of other players assigned to apply the buff to this player.
 // E.g. the number of other shaman in the group using bloodlust.
 func registerExternalConsecutiveCDApproximation(agent Agent, config externalConsecutiveCDApproximation, numSources int32) {
@@ -572,6 +630,17 @@ func registerExternalConsecutiveCDApproximation(agent Agent, config externalCons
 	}
 	sharedTimer := character.NewTimer()
 
+	shouldActivate := config.ShouldActivate
+	schedule := newExternalCooldownSchedule(character, config.Schedule)
+	if schedule != nil {
+		shouldActivate = func(sim *Simulation, character *Character) bool {
+			if !schedule.shouldActivate(sim, character) {
+				return false
+			}
+			return config.ShouldActivate == nil || config.ShouldActivate(sim, character)
+		}
+	}
+
 	spell := character.RegisterSpell(SpellConfig{
 		ActionID: config.ActionID,
 		Flags:    SpellFlagNoOnCastComplete | SpellFlagNoMetrics | SpellFlagNoLogs,
@@ -597,6 +666,9 @@ func registerExternalConsecutiveCDApproximation(agent Agent, config externalCons
 		ApplyEffects: func(sim *Simulation, _ *Unit, _ *Spell) {
 			config.AddAura(sim, character)
 			externalTimers[nextExternalIndex].Set(sim.CurrentTime + config.AuraCD)
+			if schedule != nil {
+				schedule.onUse(sim)
+			}
 
 			nextExternalIndex = (nextExternalIndex + 1) % len(externalTimers)
 
@@ -613,7 +685,7 @@ func registerExternalConsecutiveCDApproximation(agent Agent, config externalCons
 		Priority: config.CooldownPriority,
 		Type:     config.Type,
 
-		ShouldActivate: config.ShouldActivate,
+		ShouldActivate: shouldActivate,
 	})
 }
 
@@ -624,10 +696,11 @@ const BloodlustAuraTag = "Bloodlust"
 const BloodlustDuration = time.Second * 40
 const BloodlustCD = time.Minute * 10
 
-func registerBloodlustCD(agent Agent, spellID int32) {
+func registerBloodlustCD(agent Agent, spellID int32, scheduleConfig *proto.ExternalCooldownSchedule) {
 	character := agent.GetCharacter()
 	BloodlustActionID.SpellID = spellID
 	bloodlustAura := BloodlustAura(character, -1)
+	schedule := newExternalCooldownSchedule(character, scheduleConfig)
 
 	spell := character.RegisterSpell(SpellConfig{
 		ActionID: bloodlustAura.ActionID,
@@ -644,6 +717,9 @@ func registerBloodlustCD(agent Agent, spellID int32) {
 			if !target.HasActiveAura(SatedAuraLabel) {
 				bloodlustAura.Activate(sim)
 			}
+			if schedule != nil {
+				schedule.onUse(sim)
+			}
 		},
 	})
 
@@ -652,6 +728,9 @@ func registerBloodlustCD(agent Agent, spellID int32) {
 		Priority: CooldownPriorityBloodlust,
 		Type:     CooldownTypeDPS,
 		ShouldActivate: func(sim *Simulation, character *Character) bool {
+			if schedule != nil && !schedule.shouldActivate(sim, character) {
+				return false
+			}
 			return !character.HasActiveAura(SatedAuraLabel)
 		},
 	})
@@ -698,7 +777,7 @@ func multiplyCastSpeedEffect(aura *Aura, multiplier float64) *ExclusiveEffect {
 
 var TricksOfTheTradeAuraTag = "TricksOfTheTrade"
 
-func registerTricksOfTheTradeCD(agent Agent) {
+func registerTricksOfTheTradeCD(agent Agent, schedule *proto.ExternalCooldownSchedule) {
 	unit := &agent.GetCharacter().Unit
 	tricksAura := TricksOfTheTradeAura(unit, -1, 1.15)
 
@@ -715,6 +794,7 @@ func registerTricksOfTheTradeCD(agent Agent) {
 			AuraDuration:     tricksAura.Duration,
 			AuraCD:           effectiveCD,
 			Type:             CooldownTypeDPS,
+			Schedule:         schedule,
 
 			ShouldActivate: func(sim *Simulation, character *Character) bool {
 				return !character.GetExclusiveEffectCategory("PercentDamageModifier").AnyActive()
@@ -745,7 +825,7 @@ var UnholyFrenzyAuraTag = "UnholyFrenzy"
 const UnholyFrenzyDuration = time.Second * 30
 const UnholyFrenzyCD = time.Minute * 3
 
-func registerUnholyFrenzyCD(agent Agent, numUnholyFrenzy int32) {
+func registerUnholyFrenzyCD(agent Agent, numUnholyFrenzy int32, schedule *proto.ExternalCooldownSchedule) {
 	if numUnholyFrenzy == 0 {
 		return
 	}
@@ -761,6 +841,7 @@ func registerUnholyFrenzyCD(agent Agent, numUnholyFrenzy int32) {
 			AuraDuration:     UnholyFrenzyDuration,
 			AuraCD:           UnholyFrenzyCD,
 			Type:             CooldownTypeDPS,
+			Schedule:         schedule,
 
 			ShouldActivate: func(sim *Simulation, character *Character) bool {
 				return !character.GetExclusiveEffectCategory("PercentDamageModifier").AnyActive()
@@ -1068,7 +1149,7 @@ func RallyingCryAura(character *Character, actionTag int32) *Aura {
 
 const ShatteringThrowCD = time.Minute * 5
 
-func registerShatteringThrowCD(agent Agent, numShatteringThrows int32) {
+func registerShatteringThrowCD(agent Agent, numShatteringThrows int32, schedule *proto.ExternalCooldownSchedule) {
 	if numShatteringThrows == 0 {
 		return
 	}
@@ -1084,6 +1165,7 @@ func registerShatteringThrowCD(agent Agent, numShatteringThrows int32) {
 			AuraDuration:     ShatteringThrowDuration,
 			AuraCD:           ShatteringThrowCD,
 			Type:             CooldownTypeDPS,
+			Schedule:         schedule,
 
 			ShouldActivate: func(sim *Simulation, character *Character) bool {
 				return true
@@ -1101,7 +1183,7 @@ const SkullBannerAuraTag = "SkullBanner"
 const SkullBannerDuration = time.Second * 10
 const SkullBannerCD = time.Minute * 3
 
-func registerSkullBannerCD(agent Agent, numSkullBanners int32) {
+func registerSkullBannerCD(agent Agent, numSkullBanners int32, schedule *proto.ExternalCooldownSchedule) {
 	if numSkullBanners == 0 {
 		return
 	}
@@ -1117,6 +1199,7 @@ func registerSkullBannerCD(agent Agent, numSkullBanners int32) {
 			AuraDuration:     SkullBannerDuration,
 			AuraCD:           SkullBannerCD,
 			Type:             CooldownTypeDPS,
+			Schedule:         schedule,
 
 			ShouldActivate: func(sim *Simulation, character *Character) bool {
 				return true
@@ -1143,7 +1226,7 @@ var ManaTideTotemAuraTag = "ManaTideTotem"
 const ManaTideTotemDuration = time.Second * 12
 const ManaTideTotemCD = time.Minute * 5
 
-func registerManaTideTotemCD(agent Agent, numManaTideTotems int32) {
+func registerManaTideTotemCD(agent Agent, numManaTideTotems int32, schedule *proto.ExternalCooldownSchedule) {
 	if numManaTideTotems == 0 {
 		return
 	}
@@ -1168,6 +1251,7 @@ func registerManaTideTotemCD(agent Agent, numManaTideTotems int32) {
 			AuraDuration:     ManaTideTotemDuration,
 			AuraCD:           ManaTideTotemCD,
 			Type:             CooldownTypeMana,
+			Schedule:         schedule,
 			ShouldActivate: func(sim *Simulation, character *Character) bool {
 				// A normal resto shaman would wait to use MTT.
 				return sim.CurrentTime >= initialDelay
@@ -1194,7 +1278,7 @@ const StormLashAuraTag = "StormLash"
 const StormLashDuration = time.Second * 10
 const StormLashCD = time.Minute * 5
 
-func registerStormLashCD(agent Agent, numStormLashes int32) {
+func registerStormLashCD(agent Agent, numStormLashes int32, schedule *proto.ExternalCooldownSchedule) {
 	if numStormLashes == 0 {
 		return
 	}
@@ -1210,6 +1294,7 @@ func registerStormLashCD(agent Agent, numStormLashes int32) {
 			AuraDuration:     StormLashDuration,
 			AuraCD:           StormLashCD,
 			Type:             CooldownTypeDPS,
+			Schedule:         schedule,
 
 			ShouldActivate: func(sim *Simulation, character *Character) bool {
 				return true
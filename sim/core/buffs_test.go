@@ -0,0 +1,71 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+func TestExternalCooldownSchedule(t *testing.T) {
+	sim := NewSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{RandomSeed: 1},
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Race:      proto.Race_RaceTroll,
+			Class:     proto.Class_ClassShaman,
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: &proto.EquipmentSpec{},
+			Buffs:     &proto.IndividualBuffs{},
+			Rotation:  &proto.APLRotation{Type: proto.APLRotation_TypeAPL},
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{
+			SkullBannerCount: 1,
+			SkullBannerSchedule: &proto.ExternalCooldownSchedule{
+				Timings:      []float64{20},
+				ExecutePhase: 45,
+			},
+		}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration:             600,
+			ExecuteProportion_20: 0.2,
+			ExecuteProportion_25: 0.25,
+			ExecuteProportion_35: 0.35,
+			ExecuteProportion_45: 0.45,
+			ExecuteProportion_90: 0.9,
+			Targets:              []*proto.Target{{Level: 93}},
+		},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+
+	character := sim.Raid.Parties[0].Players[0].GetCharacter()
+	mcd := character.GetMajorCooldown(SkullBannerActionID.WithTag(-1))
+	if mcd == nil {
+		t.Fatalf("Skull Banner cooldown not registered")
+	}
+
+	// The schedule starts over each iteration.
+	for iteration := range 2 {
+		if iteration > 0 {
+			sim.Cleanup()
+			sim.Reset()
+		}
+		for _, step := range []struct {
+			at   time.Duration
+			want bool
+		}{
+			{at: 0, want: false},
+			{at: 19 * time.Second, want: false},
+			{at: 20 * time.Second, want: true},
+			// Off cooldown again, but not scheduled until execute at 45% health, i.e. 330s in.
+			{at: 300 * time.Second, want: false},
+			{at: 330 * time.Second, want: true},
+			// Off cooldown again during execute, which only asks for one use.
+			{at: 510 * time.Second, want: false},
+		} {
+			sim.advance(step.at)
+			if got := mcd.TryActivate(sim, character); got != step.want {
+				t.Errorf("Iteration %d: TryActivate() at %s = %t, want %t", iteration, step.at, got, step.want)
+			}
+		}
+	}
+}
@@ -17,7 +17,9 @@ import (
 )
 
 const (
-	defaultIterationsPerCombo = 1000
+	defaultIterationsPerCombo  = 1000
+	defaultBulkTargetResults   = 30
+	defaultBulkConfidenceLevel = 0.95
 )
 
 // raidSimRunner runs a standard raid simulation.
@@ -29,12 +31,42 @@ type bulkSimRunner struct {
 	SingleRaidSimRunner raidSimRunner
 	// Request used for this bulk simulation.
 	Request *proto.BulkSimRequest
+	// Optional checkpoint, used to skip combos which were already simmed and to save new results.
+	Checkpoint *BulkSimCheckpoint
+	// Number of combos simmed at once, defaults to one more than the number of CPUs.
+	Concurrency int
+}
+
+// BulkSimOptions change where and how many combos of a bulk sim are simmed.
+type BulkSimOptions struct {
+	// Optional checkpoint, used to skip combos which were already simmed and to save new results.
+	Checkpoint *BulkSimCheckpoint
+	// Optional runner for each combo, e.g. to sim them on other machines. Combos are simmed on a
+	// single local thread by default.
+	SimFunc RaidSimFunc
+	// Number of combos simmed at once, defaults to one more than the number of CPUs.
+	Concurrency int
 }
 
 func BulkSim(signals simsignals.Signals, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics) *proto.BulkSimResult {
+	return BulkSimWithOptions(signals, request, progress, BulkSimOptions{})
+}
+
+func BulkSimWithCheckpoint(signals simsignals.Signals, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, checkpoint *BulkSimCheckpoint) *proto.BulkSimResult {
+	return BulkSimWithOptions(signals, request, progress, BulkSimOptions{Checkpoint: checkpoint})
+}
+
+func BulkSimWithOptions(signals simsignals.Signals, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, options BulkSimOptions) *proto.BulkSimResult {
 	bulk := &bulkSimRunner{
 		SingleRaidSimRunner: runSim,
 		Request:             request,
+		Checkpoint:          options.Checkpoint,
+		Concurrency:         options.Concurrency,
+	}
+	if options.SimFunc != nil {
+		bulk.SingleRaidSimRunner = func(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, _ bool, signals simsignals.Signals) *proto.RaidSimResult {
+			return options.SimFunc(rsr, progress, signals)
+		}
 	}
 
 	result := bulk.Run(signals, progress)
@@ -77,7 +109,7 @@ func BulkSimCombos(signals simsignals.Signals, req *proto.BulkSimCombosRequest)
 	// clean to reduce memory
 	player.Database = nil
 
-	validCombos, iterations, err := buildCombos(signals, req.BaseSettings, req.BulkSettings, player)
+	combos, err := buildCombos(signals, req.BaseSettings, req.BulkSettings, player)
 	if err != nil {
 		return &proto.BulkSimCombosResult{
 			ErrorResult: err.Error(),
@@ -85,13 +117,38 @@ func BulkSimCombos(signals simsignals.Signals, req *proto.BulkSimCombosRequest)
 	}
 
 	result := &proto.BulkSimCombosResult{
-		NumCombinations: int32(len(validCombos)),
-		NumIterations:   int32(len(validCombos)) * iterations,
+		NumCombinations: combos.numCombos,
+		NumIterations:   combos.numCombos * combos.iterations,
 	}
 
 	return result
 }
 
+// Copies a request and reduces it to its single player, for the tools which sim variations of one
+// player like bulk sims do. The player's database is loaded, then dropped from the copy.
+func singlePlayerBaseSettings(request *proto.RaidSimRequest) (*proto.RaidSimRequest, *proto.Player, error) {
+	baseSettings := goproto.Clone(request).(*proto.RaidSimRequest)
+	var playerCount int
+	var player *proto.Player
+	for _, p := range baseSettings.GetRaid().GetParties() {
+		for _, pl := range p.GetPlayers() {
+			if pl.Name != "" {
+				player = pl
+				playerCount++
+			}
+		}
+	}
+	if playerCount != 1 || player == nil {
+		return nil, nil, fmt.Errorf("expected exactly 1 player, found %d", playerCount)
+	}
+	if player.GetDatabase() != nil {
+		addToDatabase(player.GetDatabase())
+	}
+	baseSettings.Raid.Parties = []*proto.Party{{Players: []*proto.Player{player}, Buffs: baseSettings.Raid.Parties[0].Buffs}}
+	player.Database = nil
+	return baseSettings, player, nil
+}
+
 type singleBulkSim struct {
 	req *proto.RaidSimRequest
 	cl  *raidSimRequestChangeLog
@@ -143,35 +200,65 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 		originalIterations = defaultIterationsPerCombo
 	}
 
-	validCombos, newIters, err := buildCombos(signals, b.Request.BaseSettings, b.Request.BulkSettings, player)
+	combos, err := buildCombos(signals, b.Request.BaseSettings, b.Request.BulkSettings, player)
 	if err != nil {
 		return &proto.BulkSimResult{
 			Error: &proto.ErrorOutcome{Message: err.Error()},
 		}
 	}
+	newIters := combos.iterations
 
-	// TODO(Riotdog-GehennasEU): Make this configurable?
-	maxResults := 30
+	// Stops any combo streams which are still running when we return early.
+	done := make(chan struct{})
+	defer close(done)
+
+	bulkSettings := b.Request.BulkSettings
+	maxResults := int(bulkSettings.TargetResults)
+	if maxResults <= 0 {
+		maxResults = defaultBulkTargetResults
+	}
+	confidence := bulkSettings.ConfidenceLevel
+	if confidence == 0 {
+		confidence = defaultBulkConfidenceLevel
+	} else if confidence < 0 || confidence >= 1 {
+		return &proto.BulkSimResult{
+			Error: &proto.ErrorOutcome{Message: fmt.Sprintf("bulksim: confidence level must be between 0 and 1, got %f", confidence)},
+		}
+	}
 
 	var rankedResults []*itemSubstitutionSimResult
 	var baseResult *itemSubstitutionSimResult
 
+	// The first round sims all combos, streamed as they are generated. Fast mode rounds after
+	// that only resim the combos which survived the previous round.
+	var validCombos []singleBulkSim
+	var usedIterations int64
+
 	for {
 		var tempBase *itemSubstitutionSimResult
 		var errorOutcome *proto.ErrorOutcome
-		// TODO: we could theoretically make getRankedResults accept a channel of validCombos that stream in to it and launches sims as it gets them...
-		rankedResults, tempBase, errorOutcome = b.getRankedResults(signals, validCombos, newIters, progress)
+		if validCombos == nil {
+			rankedResults, tempBase, errorOutcome = b.getRankedResults(signals, combos.stream(done), combos.numCombos, newIters, progress)
+		} else {
+			rankedResults, tempBase, errorOutcome = b.getRankedResults(signals, bulkSimsToChan(validCombos, done), int32(len(validCombos)), newIters, progress)
+		}
 
 		if errorOutcome != nil {
 			return &proto.BulkSimResult{Error: errorOutcome}
 		}
+		usedIterations += int64(len(rankedResults)) * int64(newIters)
 		// keep replacing the base result with more refined base until we don't have base in the ranked results anymore.
 		if tempBase != nil {
 			baseResult = tempBase
 		}
 
-		// If we aren't doing fast mode, or if halving our results will be less than the maxResults, be done.
-		if !b.Request.BulkSettings.FastMode || len(rankedResults) <= maxResults*2 {
+		if !bulkSettings.FastMode {
+			break
+		}
+
+		// The top results are statistically separated from all other combos.
+		survivors := raceSurvivors(rankedResults, maxResults, confidence)
+		if len(survivors) <= maxResults {
 			break
 		}
 
@@ -181,11 +268,13 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 		}
 
 		// Increase accuracy
-		newIters *= 2
-		newNumCombos := len(rankedResults) / 2
-		validCombos = validCombos[:newNumCombos]
-		rankedResults = rankedResults[:newNumCombos]
-		for i, comb := range rankedResults {
+		nextIters := newIters * 2
+		if bulkSettings.IterationBudget > 0 && usedIterations+int64(len(survivors))*int64(nextIters) > bulkSettings.IterationBudget {
+			break
+		}
+		newIters = nextIters
+		validCombos = make([]singleBulkSim, len(survivors))
+		for i, comb := range survivors {
 			validCombos[i] = singleBulkSim{
 				req: comb.Request,
 				cl:  comb.ChangeLog,
@@ -206,28 +295,20 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 		rankedResults = rankedResults[:maxResults]
 	}
 
-	bum := baseResult.Result.GetRaidMetrics().GetParties()[0].GetPlayers()[0]
-	bum.Actions = nil
-	bum.Auras = nil
-	bum.Resources = nil
-	bum.Pets = nil
-
 	result = &proto.BulkSimResult{
 		EquippedGearResult: &proto.BulkComboResult{
-			UnitMetrics: bum,
+			UnitMetrics: baseResult.Result.GetRaidMetrics().GetParties()[0].GetPlayers()[0],
+			DpsInterval: baseResult.confidenceInterval(confidence),
 		},
 	}
 
 	for _, r := range rankedResults {
 		um := r.Result.GetRaidMetrics().GetParties()[0].GetPlayers()[0]
-		um.Actions = nil
-		um.Auras = nil
-		um.Resources = nil
-		um.Pets = nil
 		result.Results = append(result.Results, &proto.BulkComboResult{
 			ItemsAdded:    r.ChangeLog.AddedItems,
 			UnitMetrics:   um,
 			TalentLoadout: r.ChangeLog.TalentLoadout,
+			DpsInterval:   r.confidenceInterval(confidence),
 		})
 	}
 
@@ -240,8 +321,11 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 	return result
 }
 
-func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos []singleBulkSim, iterations int32, progress chan *proto.ProgressMetrics) ([]*itemSubstitutionSimResult, *itemSubstitutionSimResult, *proto.ErrorOutcome) {
-	concurrency := runtime.NumCPU() + 1
+func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos <-chan singleBulkSim, numCombinations int32, iterations int32, progress chan *proto.ProgressMetrics) ([]*itemSubstitutionSimResult, *itemSubstitutionSimResult, *proto.ErrorOutcome) {
+	concurrency := b.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU() + 1
+	}
 	if concurrency <= 0 {
 		concurrency = 2
 	}
@@ -253,7 +337,6 @@ func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos
 
 	results := make(chan *itemSubstitutionSimResult, 10)
 
-	numCombinations := int32(len(validCombos))
 	totalIterationsUpperBound := numCombinations * iterations
 
 	var totalCompletedIterations int32
@@ -268,7 +351,7 @@ func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos
 			complSims := atomic.LoadInt32(&totalCompletedSims)
 
 			// stop reporting
-			if complIters == int32(totalIterationsUpperBound) || numCombinations == complSims {
+			if complIters == int32(totalIterationsUpperBound) || numCombinations == complSims || progress == nil {
 				return
 			}
 
@@ -284,7 +367,23 @@ func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos
 
 	// launcher for all combos (limited by concurrency max)
 	go func() {
-		for _, singleCombo := range validCombos {
+		for singleCombo := range validCombos {
+			if b.Checkpoint != nil {
+				if result := b.Checkpoint.get(bulkSimCheckpointKey(singleCombo, iterations)); result != nil {
+					singleCombo.req.SimOptions.Iterations = iterations
+					atomic.AddInt32(&totalCompletedIterations, iterations)
+					atomic.AddInt32(&totalCompletedSims, 1)
+					results <- &itemSubstitutionSimResult{
+						Request:      singleCombo.req,
+						Result:       result,
+						Substitution: singleCombo.eq,
+						ChangeLog:    singleCombo.cl,
+						resumed:      true,
+					}
+					continue
+				}
+			}
+
 			<-tickets
 			singleSimProgress := make(chan *proto.ProgressMetrics)
 
@@ -325,6 +424,13 @@ func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos
 			reporterSignal.Abort.Trigger() // cancel reporter
 			return nil, nil, result.Result.Error
 		}
+		trimBulkSimResult(result.Result)
+		if b.Checkpoint != nil && !result.resumed {
+			if err := b.Checkpoint.save(bulkSimCheckpointKey(singleBulkSim{req: result.Request, cl: result.ChangeLog, eq: result.Substitution}, iterations), result.Result); err != nil {
+				reporterSignal.Abort.Trigger()
+				return nil, nil, &proto.ErrorOutcome{Message: "failed to write bulk sim checkpoint: " + err.Error()}
+			}
+		}
 		if !result.Substitution.HasItemReplacements() && result.ChangeLog.TalentLoadout == nil {
 			baseResult = result
 		}
@@ -338,7 +444,33 @@ func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos
 	return rankedResults, baseResult, nil
 }
 
-func buildCombos(signals simsignals.Signals, baseSettings *proto.RaidSimRequest, bulkSettings *proto.BulkSettings, player *proto.Player) ([]singleBulkSim, int32, error) {
+// Drops the detailed metrics from a result, which are not part of bulk sim results. This keeps
+// memory and checkpoint sizes down for large bulk sims.
+func trimBulkSimResult(result *proto.RaidSimResult) {
+	unitMetrics := result.GetRaidMetrics().GetParties()[0].GetPlayers()[0]
+	unitMetrics.Actions = nil
+	unitMetrics.Auras = nil
+	unitMetrics.Resources = nil
+	unitMetrics.Pets = nil
+	unitMetrics.AplEntries = nil
+}
+
+func bulkSimsToChan(combos []singleBulkSim, done <-chan struct{}) <-chan singleBulkSim {
+	results := make(chan singleBulkSim)
+	go func() {
+		defer close(results)
+		for _, combo := range combos {
+			select {
+			case results <- combo:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return results
+}
+
+func buildCombos(signals simsignals.Signals, baseSettings *proto.RaidSimRequest, bulkSettings *proto.BulkSettings, player *proto.Player) (*bulkCombos, error) {
 	// Gemming for now can happen before slots are decided.
 	// We might have to add logic after slot decisions if we want to enforce keeping meta gem active.
 	if bulkSettings.AutoGem {
@@ -405,7 +537,7 @@ func buildCombos(signals simsignals.Signals, baseSettings *proto.RaidSimRequest,
 	for index, is := range items {
 		item, ok := ItemsByID[is.Id]
 		if !ok {
-			return nil, 0, fmt.Errorf("unknown item with id %d in bulk settings", is.Id)
+			return nil, fmt.Errorf("unknown item with id %d in bulk settings", is.Id)
 		}
 		for _, slot := range eligibleSlotsForItem(&item, isFuryWarrior) {
 			distinctItemSlotCombos = append(distinctItemSlotCombos, &itemWithSlot{
@@ -415,46 +547,31 @@ func buildCombos(signals simsignals.Signals, baseSettings *proto.RaidSimRequest,
 			})
 		}
 	}
-	baseItems := player.Equipment.Items
-
-	allCombos := generateAllEquipmentSubstitutions(signals, baseItems, bulkSettings.Combinations, distinctItemSlotCombos, isFuryWarrior)
-
-	var validCombos []singleBulkSim
-	count := 0
-	for sub := range allCombos {
-		count++
-		if count > 1000000 {
-			panic("over 1 million combos, abandoning attempt")
-		}
-
-		substitutedRequest, changeLog := createNewRequestWithSubstitution(baseSettings, sub, bulkSettings.AutoEnchant, isFuryWarrior)
-		if isValidEquipment(substitutedRequest.Raid.Parties[0].Players[0].Equipment, isFuryWarrior) {
-			// Need to sim base dps of gear loudout
-			validCombos = append(validCombos, singleBulkSim{req: substitutedRequest, cl: changeLog, eq: sub})
-			// Todo(Netzone-GehennasEU): Make this its own step?
-			if !bulkSettings.SimTalents {
-
-			} else {
-				var talentsToSim = bulkSettings.GetTalentsToSim()
-
-				if len(talentsToSim) > 0 {
-					for _, talent := range talentsToSim {
-						sr := goproto.Clone(substitutedRequest).(*proto.RaidSimRequest)
-						cl := *changeLog
-						if sr.Raid.Parties[0].Players[0].TalentsString == talent.TalentsString && goproto.Equal(talent.Glyphs, sr.Raid.Parties[0].Players[0].Glyphs) {
-							continue
-						}
-
-						sr.Raid.Parties[0].Players[0].TalentsString = talent.TalentsString
-						sr.Raid.Parties[0].Players[0].Glyphs = talent.Glyphs
-						cl.TalentLoadout = talent
-						validCombos = append(validCombos, singleBulkSim{req: sr, cl: &cl, eq: sub})
-					}
-				}
+	combos := &bulkCombos{
+		signals:                signals,
+		baseSettings:           baseSettings,
+		bulkSettings:           bulkSettings,
+		baseItems:              player.Equipment.Items,
+		distinctItemSlotCombos: distinctItemSlotCombos,
+		isFuryWarrior:          isFuryWarrior,
+	}
+	if bulkSettings.SimTalents {
+		// Loadouts matching the base player are already covered by the plain gear combos.
+		for _, talent := range bulkSettings.GetTalentsToSim() {
+			if player.TalentsString == talent.TalentsString && goproto.Equal(talent.Glyphs, player.Glyphs) {
+				continue
 			}
+			combos.talentsToSim = append(combos.talentsToSim, talent)
 		}
 	}
 
+	// Generate and validate the substitutions once up front, for progress reporting and the
+	// iteration limit below. Only the requests are rebuilt when the combos are simmed.
+	if err := combos.collectSubstitutions(); err != nil {
+		return nil, err
+	}
+	numCombos := int64(len(combos.substitutions)) * int64(1+len(combos.talentsToSim))
+
 	// In fast mode try to keep starting iterations between 1000 and 2000
 	if bulkSettings.FastMode {
 		iterations /= 10
@@ -466,12 +583,102 @@ func buildCombos(signals simsignals.Signals, baseSettings *proto.RaidSimRequest,
 		}
 	}
 
-	maxIterations := int64(iterations) * int64(len(validCombos))
+	maxIterations := int64(iterations) * numCombos
 	if maxIterations > math.MaxInt32 {
-		return nil, 0, fmt.Errorf("number of total iterations %d too large", maxIterations)
+		return nil, fmt.Errorf("number of total iterations %d too large", maxIterations)
 	}
 
-	return validCombos, iterations, nil
+	combos.numCombos = int32(numCombos)
+	combos.iterations = iterations
+	return combos, nil
+}
+
+// bulkCombos generates the requests for all combos of a bulk sim. Only the valid substitutions
+// are stored; requests are built on demand, since large bulk sims have far too many to keep in
+// memory at once.
+type bulkCombos struct {
+	signals                simsignals.Signals
+	baseSettings           *proto.RaidSimRequest
+	bulkSettings           *proto.BulkSettings
+	baseItems              []*proto.ItemSpec
+	distinctItemSlotCombos []*itemWithSlot
+	isFuryWarrior          bool
+
+	substitutions []*equipmentSubstitution
+	talentsToSim  []*proto.TalentLoadout
+
+	numCombos  int32
+	iterations int32
+}
+
+// Generates all equipment substitutions and keeps the ones that make valid gear sets.
+func (bc *bulkCombos) collectSubstitutions() error {
+	allCombos := generateAllEquipmentSubstitutions(bc.signals, bc.baseItems, bc.bulkSettings.Combinations, bc.distinctItemSlotCombos, bc.isFuryWarrior)
+	// Let the generator run to completion if we stop early, so it doesn't block forever.
+	defer func() {
+		for range allCombos {
+		}
+	}()
+
+	count := 0
+	for sub := range allCombos {
+		count++
+		if count > 1000000 {
+			return fmt.Errorf("over 1 million combos, abandoning attempt")
+		}
+
+		substitutedRequest, _ := createNewRequestWithSubstitution(bc.baseSettings, sub, bc.bulkSettings.AutoEnchant, bc.isFuryWarrior)
+		if isValidEquipment(substitutedRequest.Raid.Parties[0].Players[0].Equipment, bc.isFuryWarrior) && hasValidSubstitutions(substitutedRequest.Raid.Parties[0].Players[0], sub) {
+			bc.substitutions = append(bc.substitutions, sub)
+		}
+	}
+	return nil
+}
+
+// Calls fn for each combo, until fn returns false or done is closed.
+func (bc *bulkCombos) forEach(done <-chan struct{}, fn func(singleBulkSim) bool) {
+	for _, sub := range bc.substitutions {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		// Need to sim base dps of gear loudout
+		substitutedRequest, changeLog := createNewRequestWithSubstitution(bc.baseSettings, sub, bc.bulkSettings.AutoEnchant, bc.isFuryWarrior)
+		if !fn(singleBulkSim{req: substitutedRequest, cl: changeLog, eq: sub}) {
+			return
+		}
+
+		// Todo(Netzone-GehennasEU): Make this its own step?
+		for _, talent := range bc.talentsToSim {
+			sr := goproto.Clone(substitutedRequest).(*proto.RaidSimRequest)
+			cl := *changeLog
+			sr.Raid.Parties[0].Players[0].TalentsString = talent.TalentsString
+			sr.Raid.Parties[0].Players[0].Glyphs = talent.Glyphs
+			cl.TalentLoadout = talent
+			if !fn(singleBulkSim{req: sr, cl: &cl, eq: sub}) {
+				return
+			}
+		}
+	}
+}
+
+// Streams all combos, until done is closed.
+func (bc *bulkCombos) stream(done <-chan struct{}) <-chan singleBulkSim {
+	results := make(chan singleBulkSim)
+	go func() {
+		defer close(results)
+		bc.forEach(done, func(combo singleBulkSim) bool {
+			select {
+			case results <- combo:
+				return true
+			case <-done:
+				return false
+			}
+		})
+	}()
+	return results
 }
 
 // itemSubstitutionSimResult stores the request and response of a simulation, along with the used
@@ -482,6 +689,9 @@ type itemSubstitutionSimResult struct {
 	Result       *proto.RaidSimResult
 	Substitution *equipmentSubstitution
 	ChangeLog    *raidSimRequestChangeLog
+
+	// True if the result was loaded from a checkpoint rather than simmed.
+	resumed bool
 }
 
 // Score used to rank results.
@@ -489,7 +699,70 @@ func (r *itemSubstitutionSimResult) Score() float64 {
 	if r.Result == nil || r.Result.Error != nil {
 		return 0
 	}
-	return r.Result.RaidMetrics.Dps.Avg
+	return r.dps().GetAvg()
+}
+
+// The DPS of the single player results are reported and ranked by, which includes its pets.
+func (r *itemSubstitutionSimResult) dps() *proto.DistributionMetrics {
+	parties := r.Result.GetRaidMetrics().GetParties()
+	if len(parties) == 0 || len(parties[0].GetPlayers()) == 0 {
+		return nil
+	}
+	return parties[0].Players[0].GetDps()
+}
+
+// Confidence interval of the mean DPS. The mean over many iterations is close to normally
+// distributed, so this uses the normal quantile for the given two-sided confidence level.
+func (r *itemSubstitutionSimResult) confidenceInterval(confidence float64) *proto.ConfidenceInterval {
+	dps := r.dps()
+	iterations := r.Request.GetSimOptions().GetIterations()
+	if iterations <= 0 {
+		return &proto.ConfidenceInterval{Lower: dps.GetAvg(), Upper: dps.GetAvg()}
+	}
+	halfWidth := math.Sqrt2 * math.Erfinv(confidence) * dps.GetStdev() / math.Sqrt(float64(iterations))
+	return &proto.ConfidenceInterval{
+		Lower: dps.GetAvg() - halfWidth,
+		Upper: dps.GetAvg() + halfWidth,
+	}
+}
+
+// The ranked DPS without the values of each iteration, which results don't report, along with its
+// confidence interval.
+func (r *itemSubstitutionSimResult) playerDps(confidence float64) (*proto.DistributionMetrics, *proto.ConfidenceInterval) {
+	dps := r.dps()
+	if dps != nil {
+		dps.AllValues = nil
+	}
+	return dps, r.confidenceInterval(confidence)
+}
+
+// Returns the ranked results which may still belong to the top n, i.e. all results whose
+// upper bound is at least the lower bound of the nth result. Like successive halving, at most
+// half the results survive each round so the number of iterations per round stays bounded.
+func raceSurvivors(rankedResults []*itemSubstitutionSimResult, n int, confidence float64) []*itemSubstitutionSimResult {
+	survivors := undominatedResults(rankedResults, n, confidence)
+	if maxSurvivors := max(n, len(rankedResults)/2); len(survivors) > maxSurvivors {
+		survivors = survivors[:maxSurvivors]
+	}
+	return survivors
+}
+
+// Returns the ranked results whose upper bound is at least the lower bound of the nth result,
+// i.e. all results which aren't clearly worse than n others.
+func undominatedResults(rankedResults []*itemSubstitutionSimResult, n int, confidence float64) []*itemSubstitutionSimResult {
+	if len(rankedResults) <= n {
+		return rankedResults
+	}
+
+	cutoff := rankedResults[n-1].confidenceInterval(confidence).Lower
+	survivors := make([]*itemSubstitutionSimResult, 0, len(rankedResults))
+	survivors = append(survivors, rankedResults[:n]...)
+	for _, r := range rankedResults[n:] {
+		if r.confidenceInterval(confidence).Upper >= cutoff {
+			survivors = append(survivors, r)
+		}
+	}
+	return survivors
 }
 
 // equipmentSubstitution specifies all items to be used as replacements for the equipped gear.
@@ -541,6 +814,18 @@ func (es *equipmentSubstitution) CanonicalHash() string {
 	return strings.Join(parts, ":")
 }
 
+// hasValidSubstitutions returns true if the enchants, tinkers and extra sockets of all substituted
+// items can be used by the player. The equipped items aren't checked, so the equipped gear is
+// still simmed as a base even if it has enchants the player can't use.
+func hasValidSubstitutions(player *proto.Player, substitution *equipmentSubstitution) bool {
+	for _, is := range substitution.Items {
+		if !hasValidEnhancements(player.Equipment.Items[is.Slot], player) {
+			return false
+		}
+	}
+	return true
+}
+
 // isValidEquipment returns true if the specified equipment spec is valid.
 // An equipment spec is valid if:
 // - The main-hand is not empty.
@@ -750,18 +1035,10 @@ func createNewRequestWithSubstitution(readonlyInputRequest *proto.RaidSimRequest
 	for _, is := range substitution.Items {
 		oldItem := equipment.Items[is.Slot]
 		newItem := is.Item
-		if autoEnchant && oldItem.Enchant > 0 && is.Item.Enchant == 0 {
-			equipment.Items[is.Slot] = goproto.Clone(is.Item).(*proto.ItemSpec)
-			equipment.Items[is.Slot].Enchant = oldItem.Enchant
-			// TODO: logic to decide if the enchant can be applied to the new item...
-			// Specifically, offhand shouldn't get shield enchant
-			// Main/One hand shouldn't get staff enchant
-			// Later: replace normal enchant if replacement is staff.
-
-			newItem = equipment.Items[is.Slot]
-		} else {
-			equipment.Items[is.Slot] = is.Item
+		if autoEnchant {
+			newItem = carryOverEnhancements(oldItem, is.Item, player)
 		}
+		equipment.Items[is.Slot] = newItem
 
 		// If the item is an off-hand and the combo doesn't have a main-hand, insert a main-hand before
 		if is.Slot == proto.ItemSlot_ItemSlotOffHand && !hasMainHand {
@@ -836,6 +1113,87 @@ func createNewRequestWithSubstitution(readonlyInputRequest *proto.RaidSimRequest
 	return request, changeLog
 }
 
+// Returns the new item with the enchant, tinker and extra socket gem of the old item in the same
+// slot, where the new item has none of its own and can take the old one. Returns the new item
+// itself if nothing is carried over.
+func carryOverEnhancements(oldItem *proto.ItemSpec, newItem *proto.ItemSpec, player *proto.Player) *proto.ItemSpec {
+	newItemData, ok := ItemsByID[newItem.Id]
+	if !ok {
+		return newItem
+	}
+	result := newItem
+	withChanges := func() *proto.ItemSpec {
+		if result == newItem {
+			result = goproto.Clone(newItem).(*proto.ItemSpec)
+		}
+		return result
+	}
+
+	// Only carry over enhancements which are valid for the new item, e.g. an off-hand doesn't get
+	// a shield enchant and a one-hander doesn't get a staff enchant.
+	if oldItem.Enchant > 0 && newItem.Enchant == 0 {
+		if enchant, ok := EnchantsByEffectID[oldItem.Enchant]; ok && canUseEnchant(&enchant, &newItemData, player) {
+			withChanges().Enchant = oldItem.Enchant
+		}
+	}
+	if oldItem.Tinker > 0 && newItem.Tinker == 0 {
+		if tinker, ok := EnchantsByEffectID[oldItem.Tinker]; ok && canUseEnchant(&tinker, &newItemData, player) {
+			withChanges().Tinker = oldItem.Tinker
+		}
+	}
+
+	isBlacksmith := playerHasProfession(player, proto.Profession_Blacksmithing)
+	if oldItemData, ok := ItemsByID[oldItem.Id]; ok && newItemData.hasExtraSocket(isBlacksmith) {
+		numSockets := len(newItemData.GemSockets)
+		oldExtraGem := int32(0)
+		if len(oldItem.Gems) > len(oldItemData.GemSockets) {
+			oldExtraGem = oldItem.Gems[len(oldItemData.GemSockets)]
+		}
+		if oldExtraGem != 0 && (len(newItem.Gems) <= numSockets || newItem.Gems[numSockets] == 0) {
+			gems := make([]int32, numSockets+1)
+			copy(gems, newItem.Gems)
+			gems[numSockets] = oldExtraGem
+			withChanges().Gems = gems
+		}
+	}
+
+	return result
+}
+
+// Whether the item's enchant, tinker, gems and extra socket can be used by the player, i.e. they
+// fit the item's type and the player has the professions they require.
+func hasValidEnhancements(itemSpec *proto.ItemSpec, player *proto.Player) bool {
+	item, ok := ItemsByID[itemSpec.Id]
+	if !ok {
+		return true
+	}
+	for _, effectID := range []int32{itemSpec.Enchant, itemSpec.Tinker} {
+		if enchant, ok := EnchantsByEffectID[effectID]; ok && effectID != 0 && !canUseEnchant(&enchant, &item, player) {
+			return false
+		}
+	}
+	for _, gemID := range itemSpec.Gems {
+		if gem, ok := GemsByID[gemID]; ok && gem.RequiredProfession != proto.Profession_ProfessionUnknown && !playerHasProfession(player, gem.RequiredProfession) {
+			return false
+		}
+	}
+	if len(itemSpec.Gems) > len(item.GemSockets) && itemSpec.Gems[len(item.GemSockets)] != 0 {
+		return item.hasExtraSocket(playerHasProfession(player, proto.Profession_Blacksmithing))
+	}
+	return true
+}
+
+func canUseEnchant(enchant *Enchant, item *Item, player *proto.Player) bool {
+	if enchant.RequiredProfession != proto.Profession_ProfessionUnknown && !playerHasProfession(player, enchant.RequiredProfession) {
+		return false
+	}
+	return enchant.appliesToItem(item)
+}
+
+func playerHasProfession(player *proto.Player, profession proto.Profession) bool {
+	return player.Profession1 == profession || player.Profession2 == profession
+}
+
 type ItemComboChecker map[int64]struct{}
 
 func (ic *ItemComboChecker) HasCombo(itema int32, itemb int32) bool {
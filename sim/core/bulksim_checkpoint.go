@@ -0,0 +1,203 @@
+package core
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	goproto "google.golang.org/protobuf/proto"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// BulkSimCheckpoint persists the result of each finished bulk sim combo to a file, so that
+// a long running bulk sim which was cancelled or crashed can be resumed without resimming
+// the combos which already finished.
+//
+// The file holds one JSON object per line. The first line identifies the bulk sim request,
+// and every following line holds the result of one combo.
+type BulkSimCheckpoint struct {
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	results map[string]*proto.RaidSimResult
+}
+
+type bulkSimCheckpointHeader struct {
+	Request string `json:"request"`
+}
+
+type bulkSimCheckpointEntry struct {
+	Key    string          `json:"key"`
+	Result json.RawMessage `json:"result"`
+}
+
+// Opens a checkpoint file for the given request. If resume is true, results from an existing
+// checkpoint file are loaded, which must have been created for the same request. Otherwise
+// any existing file is overwritten.
+func OpenBulkSimCheckpoint(path string, request *proto.BulkSimRequest, resume bool) (*BulkSimCheckpoint, error) {
+	fingerprint, err := bulkSimRequestFingerprint(request)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &BulkSimCheckpoint{
+		results: make(map[string]*proto.RaidSimResult),
+	}
+
+	if resume {
+		file, err := os.OpenFile(path, os.O_RDWR, 0666)
+		if err == nil {
+			err = checkpoint.load(file, fingerprint)
+			if err != nil {
+				file.Close()
+				return nil, fmt.Errorf("failed to resume from checkpoint %q: %w", path, err)
+			}
+			checkpoint.file = file
+			checkpoint.writer = bufio.NewWriter(file)
+			return checkpoint, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint.file = file
+	checkpoint.writer = bufio.NewWriter(file)
+	if err := checkpoint.writeLine(bulkSimCheckpointHeader{Request: fingerprint}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// Reads all entries from the file, leaving the file offset at the end of the last complete entry.
+func (c *BulkSimCheckpoint) load(file *os.File, fingerprint string) error {
+	reader := bufio.NewReader(file)
+	var offset int64
+	lineNum := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// A partial line means we were interrupted while writing it, so drop it.
+			break
+		} else if err != nil {
+			return err
+		}
+		lineNum++
+
+		if lineNum == 1 {
+			header := bulkSimCheckpointHeader{}
+			if err := json.Unmarshal(line, &header); err != nil {
+				return fmt.Errorf("invalid header: %w", err)
+			}
+			if header.Request != fingerprint {
+				return errors.New("checkpoint was created for a different bulk sim request")
+			}
+		} else {
+			entry := bulkSimCheckpointEntry{}
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return fmt.Errorf("invalid entry on line %d: %w", lineNum, err)
+			}
+			result := &proto.RaidSimResult{}
+			if err := protojson.Unmarshal(entry.Result, result); err != nil {
+				return fmt.Errorf("invalid result on line %d: %w", lineNum, err)
+			}
+			c.results[entry.Key] = result
+		}
+		offset += int64(len(line))
+	}
+
+	if lineNum == 0 {
+		return errors.New("missing header")
+	}
+	if err := file.Truncate(offset); err != nil {
+		return err
+	}
+	_, err := file.Seek(offset, io.SeekStart)
+	return err
+}
+
+// Number of combo results in the checkpoint.
+func (c *BulkSimCheckpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.results)
+}
+
+func (c *BulkSimCheckpoint) get(key string) *proto.RaidSimResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.results[key]
+}
+
+func (c *BulkSimCheckpoint) save(key string, result *proto.RaidSimResult) error {
+	data, err := protojson.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[key] = result
+	return c.writeLine(bulkSimCheckpointEntry{Key: key, Result: data})
+}
+
+func (c *BulkSimCheckpoint) writeLine(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := c.writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	// Flush every line, so a crash loses at most the combos which were still running.
+	return c.writer.Flush()
+}
+
+func (c *BulkSimCheckpoint) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.writer.Flush(); err != nil {
+		c.file.Close()
+		return err
+	}
+	return c.file.Close()
+}
+
+func bulkSimRequestFingerprint(request *proto.BulkSimRequest) (string, error) {
+	data, err := goproto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Key identifying a combo within a checkpoint. Starts with the readable equipment substitution
+// hash, followed by a digest of everything else that can differ between combos (gems, enchants
+// and talents), and the number of iterations since fast mode resims combos with more iterations.
+func bulkSimCheckpointKey(combo singleBulkSim, iterations int32) string {
+	player := combo.req.Raid.Parties[0].Players[0]
+	data, err := goproto.MarshalOptions{Deterministic: true}.Marshal(&proto.Player{
+		Equipment:     player.Equipment,
+		TalentsString: player.TalentsString,
+		Glyphs:        player.Glyphs,
+	})
+	if err != nil {
+		panic(err)
+	}
+	sum := sha256.Sum256(data)
+	return combo.eq.CanonicalHash() + "#" + hex.EncodeToString(sum[:8]) + "@" + strconv.Itoa(int(iterations))
+}
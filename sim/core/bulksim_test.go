@@ -1,12 +1,15 @@
 package core
 
 import (
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/simsignals"
 	"google.golang.org/protobuf/encoding/protojson"
+	goproto "google.golang.org/protobuf/proto"
 )
 
 const (
@@ -130,6 +133,291 @@ func TestBulkSim(t *testing.T) {
 	}
 }
 
+func newTestBulkSimRequest(items ...*itemWithSlot) *proto.BulkSimRequest {
+	request := &proto.BulkSimRequest{
+		BaseSettings: &proto.RaidSimRequest{
+			Raid: &proto.Raid{
+				Parties: []*proto.Party{{
+					Players: []*proto.Player{{
+						Name:      "Player",
+						Equipment: createEquipmentFromItems(starshardEdge1),
+					}},
+				}},
+			},
+			SimOptions: &proto.SimOptions{},
+		},
+		BulkSettings: &proto.BulkSettings{
+			IterationsPerCombo: 100,
+		},
+	}
+	for _, item := range items {
+		request.BulkSettings.Items = append(request.BulkSettings.Items, item.Item)
+	}
+	return request
+}
+
+// Returns a fake sim runner whose DPS is the score of the player, and which counts its runs if
+// numSims isn't nil. When all values are saved, each iteration is one more than the last.
+func newFakeBulkRunSim(score func(*proto.Player) float64, stdev float64, numSims *int32) raidSimRunner {
+	return func(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, skipPresim bool, signals simsignals.Signals) *proto.RaidSimResult {
+		if numSims != nil {
+			atomic.AddInt32(numSims, 1)
+		}
+		dps := &proto.DistributionMetrics{
+			Avg:   score(rsr.Raid.Parties[0].Players[0]),
+			Stdev: stdev,
+		}
+		if rsr.SimOptions.SaveAllValues {
+			for i := range rsr.SimOptions.Iterations {
+				dps.AllValues = append(dps.AllValues, dps.Avg+float64(i)-float64(rsr.SimOptions.Iterations-1)/2)
+			}
+		}
+		result := &proto.RaidSimResult{
+			RaidMetrics: &proto.RaidMetrics{
+				Dps: dps,
+				Parties: []*proto.PartyMetrics{{
+					Dps:     dps,
+					Players: []*proto.UnitMetrics{{Dps: dps}},
+				}},
+			},
+		}
+		progress <- &proto.ProgressMetrics{CompletedIterations: rsr.SimOptions.Iterations, FinalRaidResult: result}
+		return result
+	}
+}
+
+// Scores a player by the sum of its weapon item IDs.
+func weaponIdScore(player *proto.Player) float64 {
+	items := player.Equipment.Items
+	return float64(items[proto.ItemSlot_ItemSlotMainHand].Id + items[proto.ItemSlot_ItemSlotOffHand].Id)
+}
+
+func TestBulkSimCheckpointResume(t *testing.T) {
+	addToDatabase(tinyItemDatabase)
+
+	var numSims int32
+	fakeRunSim := newFakeBulkRunSim(weaponIdScore, 0, &numSims)
+	newRequest := func() *proto.BulkSimRequest {
+		return newTestBulkSimRequest(pillarOfFortitude, bookOfBindingWill)
+	}
+
+	path := filepath.Join(t.TempDir(), "bulk.checkpoint")
+	run := func(resume bool) *proto.BulkSimResult {
+		request := newRequest()
+		checkpoint, err := OpenBulkSimCheckpoint(path, request, resume)
+		if err != nil {
+			t.Fatalf("OpenBulkSimCheckpoint() returned error: %v", err)
+		}
+		defer checkpoint.Close()
+
+		bulk := &bulkSimRunner{
+			SingleRaidSimRunner: fakeRunSim,
+			Request:             request,
+			Checkpoint:          checkpoint,
+		}
+		result := bulk.Run(simsignals.CreateSignals(), nil)
+		if result.Error != nil {
+			t.Fatalf("BulkSim() returned error: %v", result.Error.Message)
+		}
+		return result
+	}
+
+	first := run(false)
+	simsInFirstRun := atomic.LoadInt32(&numSims)
+	if simsInFirstRun != 3 {
+		t.Fatalf("first run simmed %d combos, want 3", simsInFirstRun)
+	}
+
+	second := run(true)
+	if got := atomic.LoadInt32(&numSims) - simsInFirstRun; got != 0 {
+		t.Errorf("resumed run simmed %d combos, want 0", got)
+	}
+	if got, want := protojson.Format(second), protojson.Format(first); got != want {
+		t.Errorf("resumed run returned a different result:\ngot:  %s\nwant: %s", got, want)
+	}
+
+	run(false)
+	if got := atomic.LoadInt32(&numSims) - simsInFirstRun; got != 3 {
+		t.Errorf("run without resume simmed %d combos, want 3", got)
+	}
+}
+
+func TestBulkSimRacing(t *testing.T) {
+	addToDatabase(tinyItemDatabase)
+
+	for _, tc := range []struct {
+		comment  string
+		stdev    float64
+		budget   int64
+		wantSims int32
+	}{
+		{
+			comment:  "separated combos finish after the first round",
+			stdev:    1,
+			wantSims: 4,
+		},
+		{
+			comment:  "overlapping combos are halved each round until one remains",
+			stdev:    1e6,
+			wantSims: 4 + 2,
+		},
+		{
+			comment:  "no further rounds once the iteration budget is exhausted",
+			stdev:    1e6,
+			budget:   5000,
+			wantSims: 4,
+		},
+	} {
+		t.Run(tc.comment, func(t *testing.T) {
+			var numSims int32
+			request := newTestBulkSimRequest(pillarOfFortitude, bookOfBindingWill, ironmender)
+			request.BulkSettings.FastMode = true
+			request.BulkSettings.IterationsPerCombo = 8000
+			request.BulkSettings.TargetResults = 1
+			request.BulkSettings.IterationBudget = tc.budget
+
+			bulk := &bulkSimRunner{
+				SingleRaidSimRunner: newFakeBulkRunSim(weaponIdScore, tc.stdev, &numSims),
+				Request:             request,
+			}
+			result := bulk.Run(simsignals.CreateSignals(), nil)
+			if result.Error != nil {
+				t.Fatalf("BulkSim() returned error: %v", result.Error.Message)
+			}
+
+			if numSims != tc.wantSims {
+				t.Errorf("simmed %d combos, want %d", numSims, tc.wantSims)
+			}
+			if len(result.Results) != 1 {
+				t.Fatalf("got %d results, want 1", len(result.Results))
+			}
+			best := result.Results[0]
+			if offHand := best.ItemsAdded[len(best.ItemsAdded)-1]; offHand.Item.Id != itemBookOfBindingWIll {
+				t.Errorf("best combo added off hand %d, want %d", offHand.Item.Id, itemBookOfBindingWIll)
+			}
+			avg := best.UnitMetrics.Dps.Avg
+			if best.DpsInterval.Lower >= avg || best.DpsInterval.Upper <= avg {
+				t.Errorf("confidence interval [%f, %f] does not contain mean %f", best.DpsInterval.Lower, best.DpsInterval.Upper, avg)
+			}
+		})
+	}
+}
+
+func TestBulkSimResultDps(t *testing.T) {
+	// Other units in the raid don't count towards the player's DPS.
+	r := &itemSubstitutionSimResult{
+		Request: &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: 100}},
+		Result: &proto.RaidSimResult{
+			RaidMetrics: &proto.RaidMetrics{
+				Dps: &proto.DistributionMetrics{Avg: 3000, Stdev: 1000},
+				Parties: []*proto.PartyMetrics{{
+					Players: []*proto.UnitMetrics{{Dps: &proto.DistributionMetrics{Avg: 1000, Stdev: 10}}},
+				}},
+			},
+		},
+	}
+	if score := r.Score(); score != 1000 {
+		t.Errorf("Score() = %f, want 1000", score)
+	}
+	dps, interval := r.playerDps(0.95)
+	if dps.Avg != 1000 || interval.Lower >= 1000 || interval.Upper <= 1000 || interval.Upper-interval.Lower > 10 {
+		t.Errorf("playerDps() = %f in [%f, %f], want 1000 in an interval narrower than 10", dps.Avg, interval.Lower, interval.Upper)
+	}
+}
+
+func TestBulkSimCheckpointLoad(t *testing.T) {
+	request := &proto.BulkSimRequest{BulkSettings: &proto.BulkSettings{IterationsPerCombo: 100}}
+	path := filepath.Join(t.TempDir(), "bulk.checkpoint")
+
+	checkpoint, err := OpenBulkSimCheckpoint(path, request, false)
+	if err != nil {
+		t.Fatalf("OpenBulkSimCheckpoint() returned error: %v", err)
+	}
+	if err := checkpoint.save("a", &proto.RaidSimResult{}); err != nil {
+		t.Fatalf("save() returned error: %v", err)
+	}
+	// Simulate being interrupted while writing the next entry.
+	checkpoint.writer.WriteString(`{"key":"b","res`)
+	checkpoint.Close()
+
+	checkpoint, err = OpenBulkSimCheckpoint(path, request, true)
+	if err != nil {
+		t.Fatalf("OpenBulkSimCheckpoint() returned error: %v", err)
+	}
+	if checkpoint.Len() != 1 || checkpoint.get("a") == nil {
+		t.Errorf("resumed checkpoint has %d results, want only %q", checkpoint.Len(), "a")
+	}
+	if err := checkpoint.save("c", &proto.RaidSimResult{}); err != nil {
+		t.Fatalf("save() returned error: %v", err)
+	}
+	checkpoint.Close()
+
+	checkpoint, err = OpenBulkSimCheckpoint(path, request, true)
+	if err != nil {
+		t.Fatalf("OpenBulkSimCheckpoint() returned error: %v", err)
+	}
+	if checkpoint.Len() != 2 {
+		t.Errorf("checkpoint has %d results after appending, want 2", checkpoint.Len())
+	}
+	checkpoint.Close()
+
+	otherRequest := &proto.BulkSimRequest{BulkSettings: &proto.BulkSettings{IterationsPerCombo: 200}}
+	if _, err := OpenBulkSimCheckpoint(path, otherRequest, true); err == nil {
+		t.Errorf("OpenBulkSimCheckpoint() for a different request succeeded, want error")
+	}
+}
+
+func TestBulkSimCheckpointResumeIterations(t *testing.T) {
+	addToDatabase(tinyItemDatabase)
+
+	request := newTestBulkSimRequest(pillarOfFortitude)
+	request.BaseSettings.SimOptions.Iterations = 10
+	player := request.BaseSettings.Raid.Parties[0].Players[0]
+	combos, err := buildCombos(simsignals.CreateSignals(), request.BaseSettings, request.BulkSettings, player)
+	if err != nil {
+		t.Fatalf("buildCombos() returned error: %v", err)
+	}
+
+	checkpoint, err := OpenBulkSimCheckpoint(filepath.Join(t.TempDir(), "bulk.checkpoint"), request, false)
+	if err != nil {
+		t.Fatalf("OpenBulkSimCheckpoint() returned error: %v", err)
+	}
+	defer checkpoint.Close()
+	combos.forEach(nil, func(combo singleBulkSim) bool {
+		dps := &proto.DistributionMetrics{Avg: 1000}
+		result := &proto.RaidSimResult{
+			RaidMetrics: &proto.RaidMetrics{
+				Dps:     dps,
+				Parties: []*proto.PartyMetrics{{Dps: dps, Players: []*proto.UnitMetrics{{Dps: dps}}}},
+			},
+		}
+		return checkpoint.save(bulkSimCheckpointKey(combo, 50), result) == nil
+	})
+
+	bulk := &bulkSimRunner{
+		SingleRaidSimRunner: func(*proto.RaidSimRequest, chan *proto.ProgressMetrics, bool, simsignals.Signals) *proto.RaidSimResult {
+			t.Fatalf("resumed combo was simmed again")
+			return nil
+		},
+		Request:    request,
+		Checkpoint: checkpoint,
+	}
+	done := make(chan struct{})
+	defer close(done)
+	results, _, errorOutcome := bulk.getRankedResults(simsignals.CreateSignals(), combos.stream(done), combos.numCombos, 50, nil)
+	if errorOutcome != nil {
+		t.Fatalf("getRankedResults() returned error: %v", errorOutcome.Message)
+	}
+	for i, result := range results {
+		if !result.resumed {
+			t.Errorf("combo %d was not resumed", i)
+		}
+		if got := result.Request.SimOptions.Iterations; got != 50 {
+			t.Errorf("resumed combo %d has %d iterations, want 50", i, got)
+		}
+	}
+}
+
 func TestGenerateAllEquipmentSubstitutions(t *testing.T) {
 	baseItems := make([]*proto.ItemSpec, len(proto.ItemSlot_name))
 	for i := range baseItems {
@@ -474,3 +762,118 @@ func TestGenerateAllEquipmentSubstitutions(t *testing.T) {
 		})
 	}
 }
+
+func TestCarryOverEnhancements(t *testing.T) {
+	const (
+		bracers         = 990201
+		socketedBracers = 990202
+		gloves          = 990203
+		staff           = 990204
+		sword           = 990205
+		gem             = 990211
+		furLining       = 990221
+		bracerEnchant   = 990222
+		staffEnchant    = 990223
+		weaponEnchant   = 990224
+		synapseSprings  = 990225
+	)
+	addToDatabase(&proto.SimDatabase{
+		Items: []*proto.SimItem{
+			{Id: bracers, Type: proto.ItemType_ItemTypeWrist},
+			{Id: socketedBracers, Type: proto.ItemType_ItemTypeWrist, GemSockets: []proto.GemColor{proto.GemColor_GemColorRed}},
+			{Id: gloves, Type: proto.ItemType_ItemTypeHands},
+			{Id: staff, Type: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeTwoHand, WeaponType: proto.WeaponType_WeaponTypeStaff},
+			{Id: sword, Type: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeMainHand, WeaponType: proto.WeaponType_WeaponTypeSword},
+		},
+		Gems: []*proto.SimGem{
+			{Id: gem, Color: proto.GemColor_GemColorRed},
+		},
+		Enchants: []*proto.SimEnchant{
+			{EffectId: furLining, Type: proto.ItemType_ItemTypeWrist, RequiredProfession: proto.Profession_Leatherworking},
+			{EffectId: bracerEnchant, Type: proto.ItemType_ItemTypeWrist},
+			{EffectId: staffEnchant, Type: proto.ItemType_ItemTypeWeapon, EnchantType: proto.EnchantType_EnchantTypeStaff},
+			{EffectId: weaponEnchant, Type: proto.ItemType_ItemTypeWeapon},
+			{EffectId: synapseSprings, Type: proto.ItemType_ItemTypeHands, RequiredProfession: proto.Profession_Engineering},
+		},
+	})
+
+	blacksmithEngineer := &proto.Player{Profession1: proto.Profession_Blacksmithing, Profession2: proto.Profession_Engineering}
+	leatherworker := &proto.Player{Profession1: proto.Profession_Leatherworking}
+
+	for _, tc := range []struct {
+		comment string
+		player  *proto.Player
+		oldItem *proto.ItemSpec
+		newItem *proto.ItemSpec
+		want    *proto.ItemSpec
+	}{
+		{
+			comment: "enchant is carried over to an item of the same type",
+			player:  leatherworker,
+			oldItem: &proto.ItemSpec{Id: bracers, Enchant: furLining},
+			newItem: &proto.ItemSpec{Id: socketedBracers, Gems: []int32{gem}},
+			want:    &proto.ItemSpec{Id: socketedBracers, Enchant: furLining, Gems: []int32{gem}},
+		},
+		{
+			comment: "profession enchant is not carried over without the profession",
+			player:  blacksmithEngineer,
+			oldItem: &proto.ItemSpec{Id: bracers, Enchant: furLining},
+			newItem: &proto.ItemSpec{Id: socketedBracers},
+			want:    &proto.ItemSpec{Id: socketedBracers},
+		},
+		{
+			comment: "staff enchant is not carried over to a sword",
+			player:  leatherworker,
+			oldItem: &proto.ItemSpec{Id: staff, Enchant: staffEnchant},
+			newItem: &proto.ItemSpec{Id: sword},
+			want:    &proto.ItemSpec{Id: sword},
+		},
+		{
+			comment: "tinker is carried over for engineers",
+			player:  blacksmithEngineer,
+			oldItem: &proto.ItemSpec{Id: gloves, Enchant: weaponEnchant, Tinker: synapseSprings},
+			newItem: &proto.ItemSpec{Id: gloves},
+			want:    &proto.ItemSpec{Id: gloves, Tinker: synapseSprings},
+		},
+		{
+			comment: "Blacksmithing socket gem is carried over for blacksmiths",
+			player:  blacksmithEngineer,
+			oldItem: &proto.ItemSpec{Id: bracers, Gems: []int32{gem}},
+			newItem: &proto.ItemSpec{Id: socketedBracers},
+			want:    &proto.ItemSpec{Id: socketedBracers, Gems: []int32{0, gem}},
+		},
+		{
+			comment: "Blacksmithing socket gem is not carried over for others",
+			player:  leatherworker,
+			oldItem: &proto.ItemSpec{Id: bracers, Gems: []int32{gem}},
+			newItem: &proto.ItemSpec{Id: socketedBracers},
+			want:    &proto.ItemSpec{Id: socketedBracers},
+		},
+	} {
+		got := carryOverEnhancements(tc.oldItem, tc.newItem, tc.player)
+		if !goproto.Equal(got, tc.want) {
+			t.Errorf("%s: carryOverEnhancements() = %v, want %v", tc.comment, got, tc.want)
+		}
+		if !hasValidEnhancements(got, tc.player) {
+			t.Errorf("%s: carried over enhancements %v are not valid", tc.comment, got)
+		}
+	}
+
+	for _, tc := range []struct {
+		comment string
+		player  *proto.Player
+		item    *proto.ItemSpec
+		want    bool
+	}{
+		{"bracer enchant on bracers", leatherworker, &proto.ItemSpec{Id: bracers, Enchant: bracerEnchant}, true},
+		{"bracer enchant on a sword", leatherworker, &proto.ItemSpec{Id: sword, Enchant: bracerEnchant}, false},
+		{"fur lining without Leatherworking", blacksmithEngineer, &proto.ItemSpec{Id: bracers, Enchant: furLining}, false},
+		{"tinker without Engineering", leatherworker, &proto.ItemSpec{Id: gloves, Tinker: synapseSprings}, false},
+		{"extra socket without Blacksmithing", leatherworker, &proto.ItemSpec{Id: gloves, Gems: []int32{gem}}, false},
+		{"extra socket with Blacksmithing", blacksmithEngineer, &proto.ItemSpec{Id: gloves, Gems: []int32{gem}}, true},
+	} {
+		if got := hasValidEnhancements(tc.item, tc.player); got != tc.want {
+			t.Errorf("%s: hasValidEnhancements() = %t, want %t", tc.comment, got, tc.want)
+		}
+	}
+}
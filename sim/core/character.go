@@ -103,6 +103,7 @@ func NewCharacter(party *Party, partyIndex int, player *proto.Player) Character
 
 			ReactionTime:            time.Duration(max(player.ReactionTimeMs, 10)) * time.Millisecond,
 			ChannelClipDelay:        max(0, time.Duration(player.ChannelClipDelayMs)*time.Millisecond),
+			humanError:              newHumanErrorModel(player.HumanError),
 			StartDistanceFromTarget: player.DistanceFromTarget,
 		},
 
@@ -676,6 +677,9 @@ func (character *Character) GetMetricsProto() *proto.UnitMetrics {
 	metrics.Name = character.Name
 	metrics.UnitIndex = character.UnitIndex
 	metrics.Auras = character.auraTracker.GetMetricsProto()
+	if character.Rotation != nil {
+		metrics.AplEntries = character.Rotation.getEntryMetricsProto()
+	}
 
 	metrics.Pets = make([]*proto.UnitMetrics, len(character.Pets))
 	for i, pet := range character.Pets {
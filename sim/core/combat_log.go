@@ -0,0 +1,94 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// CombatLog collects structured events during debug iterations. The text log
+// returned in RaidSimResult.Logs is rendered from these events, so tooling can
+// consume either representation without them drifting apart.
+type CombatLog struct {
+	Events []*proto.CombatLogEvent
+}
+
+func (cl *CombatLog) add(event *proto.CombatLogEvent) {
+	cl.Events = append(cl.Events, event)
+}
+
+// Renders the events in the same format as the classic text log.
+func (cl *CombatLog) String() string {
+	sb := &strings.Builder{}
+	for _, event := range cl.Events {
+		sb.WriteString(FormatCombatLogEvent(event))
+	}
+	return sb.String()
+}
+
+func FormatCombatLogEvent(event *proto.CombatLogEvent) string {
+	return fmt.Sprintf("[%0.2f] %s\n", event.Timestamp, event.Message)
+}
+
+// LogEvent records a structured event along with its text message. When no
+// structured log is attached (e.g. a custom sim.Log was installed), only the
+// text message is forwarded to sim.Log.
+//
+// Callers are expected to check sim.Log != nil first, same as for sim.Log().
+func (sim *Simulation) LogEvent(event *proto.CombatLogEvent, message string, vals ...interface{}) {
+	if sim.combatLog == nil {
+		sim.Log(message, vals...)
+		return
+	}
+
+	event.Timestamp = sim.CurrentTime.Seconds()
+	event.Message = fmt.Sprintf(message, vals...)
+	sim.combatLog.add(event)
+}
+
+func (unit *Unit) LogEvent(sim *Simulation, event *proto.CombatLogEvent, message string, vals ...interface{}) {
+	event.Unit = unit.Label
+	sim.LogEvent(event, unit.LogLabel()+" "+message, vals...)
+}
+
+func (spell *Spell) logCastStart(sim *Simulation, cost float64, castTime time.Duration, effectiveTime time.Duration) {
+	spell.Unit.LogEvent(sim, &proto.CombatLogEvent{
+		Type:          proto.CombatLogEventType_CombatLogEventCastStart,
+		ActionId:      spell.ActionID.ToProto(),
+		Cost:          cost,
+		CastTime:      castTime.Seconds(),
+		EffectiveTime: effectiveTime.Seconds(),
+	}, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)", spell.ActionID, cost, castTime, effectiveTime)
+}
+
+func (spell *Spell) logCastFinish(sim *Simulation) {
+	spell.Unit.LogEvent(sim, &proto.CombatLogEvent{
+		Type:     proto.CombatLogEventType_CombatLogEventCastFinish,
+		ActionId: spell.ActionID.ToProto(),
+	}, "Completed cast %s", spell.ActionID)
+}
+
+func (spell *Spell) logInstantCast(sim *Simulation) {
+	spell.logCastStart(sim, 0, 0, 0)
+	spell.logCastFinish(sim)
+}
+
+func (unit *Unit) logResourceChange(sim *Simulation, actionID ActionID, resourceType proto.ResourceType, amount float64, oldValue float64, newValue float64, message string, vals ...interface{}) {
+	unit.LogEvent(sim, &proto.CombatLogEvent{
+		Type:         proto.CombatLogEventType_CombatLogEventResourceChange,
+		ActionId:     actionID.ToProto(),
+		ResourceType: resourceType,
+		Amount:       amount,
+		OldValue:     oldValue,
+		NewValue:     newValue,
+	}, message, vals...)
+}
+
+func (aura *Aura) logEvent(sim *Simulation, eventType proto.CombatLogEventType, message string) {
+	aura.Unit.LogEvent(sim, &proto.CombatLogEvent{
+		Type:     eventType,
+		ActionId: aura.ActionID.ToProto(),
+	}, message, aura.ActionID)
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestCombatLogRendersTextLog(t *testing.T) {
+	sim := &Simulation{combatLog: &CombatLog{}}
+	sim.Log = func(message string, vals ...interface{}) {
+		sim.LogEvent(&proto.CombatLogEvent{}, message, vals...)
+	}
+	unit := &Unit{Label: "Player 1"}
+	aura := &Aura{Unit: unit, ActionID: ActionID{SpellID: 2825}}
+
+	sim.CurrentTime = -time.Second
+	sim.Log("Prepull %d", 1)
+	sim.CurrentTime = time.Millisecond * 1500
+	aura.logEvent(sim, proto.CombatLogEventType_CombatLogEventAuraGained, "Aura gained: %s")
+
+	want := "[-1.00] Prepull 1\n[1.50] [Player 1] Aura gained: {SpellID: 2825}\n"
+	if got := sim.combatLog.String(); got != want {
+		t.Fatalf("text log = %q, want %q", got, want)
+	}
+
+	event := sim.combatLog.Events[1]
+	if event.Type != proto.CombatLogEventType_CombatLogEventAuraGained || event.Unit != "Player 1" || event.ActionId.GetSpellId() != 2825 {
+		t.Fatalf("unexpected aura event: %v", event)
+	}
+}
+
+func TestLogEventWithoutCombatLogFallsBackToText(t *testing.T) {
+	var lines []string
+	sim := &Simulation{}
+	sim.Log = func(message string, vals ...interface{}) {
+		lines = append(lines, message)
+	}
+
+	(&Unit{Label: "Target 1"}).LogEvent(sim, &proto.CombatLogEvent{Type: proto.CombatLogEventType_CombatLogEventTargetEnabled}, "Target enabled")
+
+	if len(lines) != 1 || lines[0] != "[Target 1] Target enabled" {
+		t.Fatalf("unexpected fallback lines: %v", lines)
+	}
+}
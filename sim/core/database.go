@@ -244,17 +244,24 @@ type Enchant struct {
 	EffectID      int32 // Used by UI to apply effect to tooltip
 	Stats         stats.Stats
 	EnchantEffect *proto.ItemEffect
-	Name          string         // Only needed for unit tests
-	Type          proto.ItemType // Only needed for unit tests
+	Name          string // Only needed for unit tests
+	Type          proto.ItemType
+
+	ExtraTypes         []proto.ItemType
+	EnchantType        proto.EnchantType
+	RequiredProfession proto.Profession
 }
 
 func EnchantFromProto(pData *proto.SimEnchant) Enchant {
 	return Enchant{
-		EffectID:      pData.EffectId,
-		Stats:         stats.FromProtoArray(pData.Stats),
-		EnchantEffect: pData.EnchantEffect,
-		Name:          pData.Name,
-		Type:          pData.Type,
+		EffectID:           pData.EffectId,
+		Stats:              stats.FromProtoArray(pData.Stats),
+		EnchantEffect:      pData.EnchantEffect,
+		Name:               pData.Name,
+		Type:               pData.Type,
+		ExtraTypes:         pData.ExtraTypes,
+		EnchantType:        pData.EnchantType,
+		RequiredProfession: pData.RequiredProfession,
 	}
 }
 
@@ -264,6 +271,7 @@ type Gem struct {
 	Stats                   stats.Stats
 	Color                   proto.GemColor
 	DisabledInChallengeMode bool
+	RequiredProfession      proto.Profession
 }
 
 func GemFromProto(pData *proto.SimGem) Gem {
@@ -273,6 +281,7 @@ func GemFromProto(pData *proto.SimGem) Gem {
 		Stats:                   stats.FromProtoArray(pData.Stats),
 		Color:                   pData.Color,
 		DisabledInChallengeMode: pData.DisabledInChallengeMode,
+		RequiredProfession:      pData.RequiredProfession,
 	}
 }
 
@@ -820,6 +829,73 @@ func eligibleSlotsForItem(item *Item, isFuryWarrior bool) []proto.ItemSlot {
 	return nil
 }
 
+// Whether the enchant, or tinker, can be applied to the item. See enchantAppliesToItem in
+// proto_utils/utils.ts.
+func (enchant *Enchant) appliesToItem(item *Item) bool {
+	itemSlots := eligibleSlotsForItem(item, true)
+	// Some shield enchants parse as ItemTypeUnknown, see Equipment.EquipEnchant, so those aren't
+	// restricted by slot.
+	sharesSlot := enchant.Type == proto.ItemType_ItemTypeUnknown && len(enchant.ExtraTypes) == 0
+	for _, enchantType := range append([]proto.ItemType{enchant.Type}, enchant.ExtraTypes...) {
+		enchantSlots, ok := itemTypeToSlotsMap[enchantType]
+		if enchantType == proto.ItemType_ItemTypeWeapon {
+			enchantSlots, ok = []proto.ItemSlot{proto.ItemSlot_ItemSlotMainHand, proto.ItemSlot_ItemSlotOffHand}, true
+		}
+		if ok && slices.ContainsFunc(enchantSlots, func(slot proto.ItemSlot) bool { return slices.Contains(itemSlots, slot) }) {
+			sharesSlot = true
+		}
+	}
+	if !sharesSlot {
+		return false
+	}
+
+	switch enchant.EnchantType {
+	case proto.EnchantType_EnchantTypeTwoHand:
+		if item.HandType != proto.HandType_HandTypeTwoHand {
+			return false
+		}
+	case proto.EnchantType_EnchantTypeStaff:
+		if item.WeaponType != proto.WeaponType_WeaponTypeStaff {
+			return false
+		}
+	case proto.EnchantType_EnchantTypeShield:
+		if item.WeaponType != proto.WeaponType_WeaponTypeShield {
+			return false
+		}
+	}
+
+	// All off-hand enchants can be applied to shields as well.
+	isOffHandItem := item.WeaponType == proto.WeaponType_WeaponTypeOffHand ||
+		(item.WeaponType == proto.WeaponType_WeaponTypeShield && enchant.EnchantType != proto.EnchantType_EnchantTypeShield)
+	if (enchant.EnchantType == proto.EnchantType_EnchantTypeOffHand) != isOffHandItem {
+		return false
+	}
+
+	if enchant.Type == proto.ItemType_ItemTypeRanged {
+		switch item.RangedWeaponType {
+		case proto.RangedWeaponType_RangedWeaponTypeBow, proto.RangedWeaponType_RangedWeaponTypeCrossbow, proto.RangedWeaponType_RangedWeaponTypeGun:
+		default:
+			return false
+		}
+	}
+	if item.RangedWeaponType != proto.RangedWeaponType_RangedWeaponTypeWand && item.RangedWeaponType > 0 && enchant.Type != proto.ItemType_ItemTypeRanged {
+		return false
+	}
+
+	return true
+}
+
+// Whether the item has an extra prismatic socket, from a belt buckle or Blacksmithing.
+func (item *Item) hasExtraSocket(isBlacksmith bool) bool {
+	switch item.Type {
+	case proto.ItemType_ItemTypeWaist:
+		return true
+	case proto.ItemType_ItemTypeWrist, proto.ItemType_ItemTypeHands:
+		return isBlacksmith
+	}
+	return false
+}
+
 func ColorIntersects(g proto.GemColor, o proto.GemColor) bool {
 	if g == o {
 		return true
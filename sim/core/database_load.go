@@ -53,11 +53,14 @@ func init() {
 
 	for i, enchant := range db.Enchants {
 		simDB.Enchants[i] = &proto.SimEnchant{
-			EffectId:      enchant.EffectId,
-			Stats:         enchant.Stats,
-			EnchantEffect: enchant.EnchantEffect,
-			Name:          enchant.Name,
-			Type:          enchant.Type,
+			EffectId:           enchant.EffectId,
+			Stats:              enchant.Stats,
+			EnchantEffect:      enchant.EnchantEffect,
+			Name:               enchant.Name,
+			Type:               enchant.Type,
+			ExtraTypes:         enchant.ExtraTypes,
+			EnchantType:        enchant.EnchantType,
+			RequiredProfession: enchant.RequiredProfession,
 		}
 	}
 
@@ -68,6 +71,7 @@ func init() {
 			Color:                   gem.Color,
 			Stats:                   gem.Stats,
 			DisabledInChallengeMode: gem.DisabledInChallengeMode,
+			RequiredProfession:      gem.RequiredProfession,
 		}
 	}
 
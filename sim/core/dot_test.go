@@ -89,6 +89,45 @@ func NewFakeElementalShaman(char *Character, _ *proto.Player) Agent {
 			},
 		})
 		fa.Dot = fa.Spell.CurDot()
+
+		fa.RegisterSpell(SpellConfig{
+			ActionID:    ActionID{SpellID: 43},
+			SpellSchool: SpellSchoolShadow,
+			ProcMask:    ProcMaskSpellDamage,
+			Flags:       SpellFlagChanneled | SpellFlagAPL,
+			Cast: CastConfig{
+				DefaultCast: Cast{
+					GCD: GCDDefault,
+				},
+			},
+
+			Dot: DotConfig{
+				Aura: Aura{
+					Label: "fakechannel",
+				},
+				NumberOfTicks: 3,
+				TickLength:    time.Second,
+				OnTick:        func(sim *Simulation, target *Unit, dot *Dot) {},
+			},
+
+			ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+				spell.Dot(target).Apply(sim)
+			},
+		})
+
+		fa.RegisterSpell(SpellConfig{
+			ActionID:    ActionID{SpellID: 44},
+			SpellSchool: SpellSchoolShadow,
+			ProcMask:    ProcMaskSpellDamage,
+			Flags:       SpellFlagAPL,
+			Cast: CastConfig{
+				DefaultCast: Cast{
+					GCD: GCDDefault,
+				},
+			},
+
+			ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {},
+		})
 	}
 
 	return fa
@@ -115,7 +115,7 @@ func (eb *energyBar) AddEnergy(sim *Simulation, amount float64, metrics *Resourc
 	metrics.AddEvent(amount, newEnergy-eb.currentEnergy)
 
 	if sim.Log != nil {
-		eb.unit.Log(sim, "Gained %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
+		eb.unit.logResourceChange(sim, metrics.ActionID, proto.ResourceType_ResourceTypeEnergy, amount, eb.currentEnergy, newEnergy, "Gained %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
 	}
 
 	eb.currentEnergy = newEnergy
@@ -130,7 +130,7 @@ func (eb *energyBar) SpendEnergy(sim *Simulation, amount float64, metrics *Resou
 	metrics.AddEvent(-amount, -amount)
 
 	if sim.Log != nil {
-		eb.unit.Log(sim, "Spent %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
+		eb.unit.logResourceChange(sim, metrics.ActionID, proto.ResourceType_ResourceTypeEnergy, -amount, eb.currentEnergy, newEnergy, "Spent %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
 	}
 
 	eb.currentEnergy = newEnergy
@@ -209,7 +209,7 @@ func (eb *energyBar) AddComboPoints(sim *Simulation, pointsToAdd int32, metrics
 	metrics.AddEvent(float64(pointsToAdd), float64(newComboPoints-eb.comboPoints))
 
 	if sim.Log != nil {
-		eb.unit.Log(sim, "Gained %d %s from %s (%d --> %d) of %0.0f total.", pointsToAdd, eb.comboPointsResourceName, metrics.ActionID, eb.comboPoints, newComboPoints, eb.maxComboPoints)
+		eb.unit.logResourceChange(sim, metrics.ActionID, Ternary(eb.ownerClass == proto.Class_ClassMonk, proto.ResourceType_ResourceTypeChi, proto.ResourceType_ResourceTypeComboPoints), float64(pointsToAdd), float64(eb.comboPoints), float64(newComboPoints), "Gained %d %s from %s (%d --> %d) of %0.0f total.", pointsToAdd, eb.comboPointsResourceName, metrics.ActionID, eb.comboPoints, newComboPoints, eb.maxComboPoints)
 	}
 
 	eb.comboPoints = newComboPoints
@@ -227,7 +227,7 @@ func (eb *energyBar) spendComboPointsInternal(sim *Simulation, pointsToSpend int
 	pointsToSpend = min(pointsToSpend, eb.comboPoints)
 	newComboPoints := eb.comboPoints - pointsToSpend
 	if sim.Log != nil {
-		eb.unit.Log(sim, "Spent %d %s from %s (%d --> %d) of %0.0f total.", pointsToSpend, eb.comboPointsResourceName, metrics.ActionID, eb.comboPoints, newComboPoints, eb.maxComboPoints)
+		eb.unit.logResourceChange(sim, metrics.ActionID, Ternary(eb.ownerClass == proto.Class_ClassMonk, proto.ResourceType_ResourceTypeChi, proto.ResourceType_ResourceTypeComboPoints), -float64(pointsToSpend), float64(eb.comboPoints), float64(newComboPoints), "Spent %d %s from %s (%d --> %d) of %0.0f total.", pointsToSpend, eb.comboPointsResourceName, metrics.ActionID, eb.comboPoints, newComboPoints, eb.maxComboPoints)
 	}
 	metrics.AddEvent(float64(-pointsToSpend), float64(-pointsToSpend))
 	eb.comboPoints = newComboPoints
@@ -190,6 +190,7 @@ const (
 	SpellFlagAoE                                           // Indicates that this spell is an AoE spell. Spells flagged with this will use the AoE Cap multiplier when calculating damage.
 	SpellFlagRanged                                        // Indicates that this spell is a ranged spell. Spells flagged with this will have increased damage when Hunters Mark is active.
 	SpellFlagReadinessTrinket                              // Indicates that this spell part of Readiness. Used by Siege of Orgrimmar CDR trinkets.
+	SpellFlagAreaAroundCaster                              // The Radius of this spell is measured from the caster, e.g. for Consecration.
 
 	// Used to let agents categorize their spells.
 	SpellFlagAgentReserved1
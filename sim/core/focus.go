@@ -95,7 +95,7 @@ func (fb *focusBar) AddFocus(sim *Simulation, amount float64, metrics *ResourceM
 	}
 	newFocus := min(fb.currentFocus+amount, fb.maxFocus)
 	if (fb.isPlayer || fb.currentFocus != newFocus) && sim.Log != nil {
-		fb.unit.Log(sim, "Gained %0.3f focus from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, fb.currentFocus, newFocus, fb.maxFocus)
+		fb.unit.logResourceChange(sim, metrics.ActionID, proto.ResourceType_ResourceTypeFocus, amount, fb.currentFocus, newFocus, "Gained %0.3f focus from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, fb.currentFocus, newFocus, fb.maxFocus)
 	}
 	if fb.isPlayer {
 		metrics.AddEvent(amount, newFocus-fb.currentFocus)
@@ -117,7 +117,7 @@ func (fb *focusBar) SpendFocus(sim *Simulation, amount float64, metrics *Resourc
 	metrics.AddEvent(-amount, -amount)
 
 	if sim.Log != nil {
-		fb.unit.Log(sim, "Spent %0.3f focus from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, fb.currentFocus, newFocus, fb.maxFocus)
+		fb.unit.logResourceChange(sim, metrics.ActionID, proto.ResourceType_ResourceTypeFocus, -amount, fb.currentFocus, newFocus, "Spent %0.3f focus from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, fb.currentFocus, newFocus, fb.maxFocus)
 	}
 
 	fb.currentFocus = newFocus
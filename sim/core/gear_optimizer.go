@@ -0,0 +1,684 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+const defaultGearOptimizerFinalists = 5
+
+// Stats weighed when the request does not provide stat weights.
+var gearOptimizerWeightedStats = []proto.Stat{
+	proto.Stat_StatStrength,
+	proto.Stat_StatAgility,
+	proto.Stat_StatStamina,
+	proto.Stat_StatIntellect,
+	proto.Stat_StatSpirit,
+	proto.Stat_StatHitRating,
+	proto.Stat_StatCritRating,
+	proto.Stat_StatHasteRating,
+	proto.Stat_StatExpertiseRating,
+	proto.Stat_StatMasteryRating,
+	proto.Stat_StatDodgeRating,
+	proto.Stat_StatParryRating,
+}
+
+type gearOptimizer struct {
+	// Function used to run the finalist sims.
+	SingleRaidSimRunner raidSimRunner
+	Request             *proto.GearOptimizerRequest
+}
+
+func OptimizeGear(signals simsignals.Signals, request *proto.GearOptimizerRequest) *proto.GearOptimizerResult {
+	optimizer := &gearOptimizer{
+		SingleRaidSimRunner: runSim,
+		Request:             request,
+	}
+	return optimizer.Run(signals)
+}
+
+func (o *gearOptimizer) Run(signals simsignals.Signals) (result *proto.GearOptimizerResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.GearOptimizerResult{
+				Error: &proto.ErrorOutcome{
+					Message: fmt.Sprintf("%v\nStack Trace:\n%s", err, string(debug.Stack())),
+				},
+			}
+		}
+	}()
+
+	baseSettings, player, err := singlePlayerBaseSettings(o.Request.GetBaseSettings())
+	if err != nil {
+		return &proto.GearOptimizerResult{
+			Error: &proto.ErrorOutcome{Message: "optimizer: " + err.Error()},
+		}
+	}
+
+	weights := o.Request.StatWeights
+	if weights == nil {
+		var errorOutcome *proto.ErrorOutcome
+		weights, errorOutcome = gearStatWeights(signals, baseSettings, player)
+		if errorOutcome != nil {
+			return &proto.GearOptimizerResult{Error: errorOutcome}
+		}
+	}
+
+	caps, err := gearCaps(baseSettings, player, o.Request.StatCaps, o.Request.AutoStatCaps)
+	if err != nil {
+		return &proto.GearOptimizerResult{
+			Error: &proto.ErrorOutcome{Message: err.Error()},
+		}
+	}
+
+	search, err := newGearSearch(player, o.Request, stats.FromProtoArray(weights.Stats), caps)
+	if err != nil {
+		return &proto.GearOptimizerResult{
+			Error: &proto.ErrorOutcome{Message: err.Error()},
+		}
+	}
+
+	numFinalists := int(o.Request.NumFinalists)
+	if numFinalists <= 0 {
+		numFinalists = defaultGearOptimizerFinalists
+	}
+	finalists := search.run(numFinalists)
+
+	equipped := search.result(search.initial)
+	candidates := make([]*proto.GearOptimizerCandidate, 0, len(finalists)+1)
+	for _, finalist := range finalists {
+		candidates = append(candidates, search.result(finalist))
+	}
+	if err := o.simCandidates(signals, baseSettings, append(candidates, equipped)); err != nil {
+		return &proto.GearOptimizerResult{Error: err}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Dps.Avg > candidates[j].Dps.Avg
+	})
+	return &proto.GearOptimizerResult{
+		Candidates:   candidates,
+		EquippedGear: equipped,
+		StatWeights:  weights,
+	}
+}
+
+// Runs a full sim for each candidate, filling in its DPS.
+func (o *gearOptimizer) simCandidates(signals simsignals.Signals, baseSettings *proto.RaidSimRequest, candidates []*proto.GearOptimizerCandidate) *proto.ErrorOutcome {
+	results := make([]*proto.RaidSimResult, len(candidates))
+	// Limit the finalists simmed at once like bulk sims do, since each sim already saturates a core.
+	tickets := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i, candidate := range candidates {
+		request := goproto.Clone(baseSettings).(*proto.RaidSimRequest)
+		request.Raid.Parties[0].Players[0].Equipment = candidate.Equipment
+		if o.Request.Iterations > 0 {
+			request.SimOptions.Iterations = o.Request.Iterations
+		}
+
+		wg.Add(1)
+		tickets <- struct{}{}
+		go func(i int, request *proto.RaidSimRequest) {
+			defer func() {
+				<-tickets
+				wg.Done()
+			}()
+			results[i] = o.SingleRaidSimRunner(request, nil, false, signals)
+		}(i, request)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if result.Error != nil {
+			return result.Error
+		}
+		candidates[i].Dps = result.RaidMetrics.Parties[0].Players[0].Dps
+	}
+	return nil
+}
+
+// Sims the weights of the stats gear can have, for a request reduced to the single player.
+func gearStatWeights(signals simsignals.Signals, baseSettings *proto.RaidSimRequest, player *proto.Player) (*proto.UnitStats, *proto.ErrorOutcome) {
+	weightsResult := runStatWeights(&proto.StatWeightsRequest{
+		Player:          goproto.Clone(player).(*proto.Player),
+		RaidBuffs:       baseSettings.Raid.Buffs,
+		PartyBuffs:      baseSettings.Raid.Parties[0].Buffs,
+		Debuffs:         baseSettings.Raid.Debuffs,
+		Encounter:       baseSettings.Encounter,
+		SimOptions:      goproto.Clone(baseSettings.SimOptions).(*proto.SimOptions),
+		Tanks:           baseSettings.Raid.Tanks,
+		StatsToWeigh:    gearOptimizerWeightedStats,
+		EpReferenceStat: gearOptimizerWeightedStats[0],
+	}, nil, signals, LocalRaidSimFunc())
+	if weightsResult.Error != nil {
+		return nil, weightsResult.Error
+	}
+	return weightsResult.Dps.Weights, nil
+}
+
+// The given caps of the player, plus its computed hit and expertise caps if autoStatCaps is set,
+// for a request reduced to the single player.
+func gearCaps(baseSettings *proto.RaidSimRequest, player *proto.Player, requestCaps []*proto.StatCap, autoStatCaps bool) ([]gearCap, error) {
+	statCaps := slices.Clone(requestCaps)
+	if autoStatCaps {
+		computedCaps, err := computeStatCaps(&proto.StatCapsRequest{
+			Player:     player,
+			RaidBuffs:  baseSettings.Raid.Buffs,
+			PartyBuffs: baseSettings.Raid.Parties[0].Buffs,
+			Debuffs:    baseSettings.Raid.Debuffs,
+			Encounter:  baseSettings.Encounter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, statCap := range computedCaps {
+			if !slices.ContainsFunc(statCaps, func(c *proto.StatCap) bool { return c.Stat == statCap.Stat }) {
+				statCaps = append(statCaps, statCap)
+			}
+		}
+	}
+	if len(statCaps) == 0 {
+		return nil, nil
+	}
+
+	// Caps apply to the total stat, so find how much of each capped total comes from outside the gear.
+	statsResult := ComputeStats(&proto.ComputeStatsRequest{Raid: baseSettings.Raid, Encounter: baseSettings.Encounter})
+	if statsResult.ErrorResult != "" {
+		return nil, errors.New(statsResult.ErrorResult)
+	}
+	playerStats := statsResult.RaidStats.Parties[0].Players[0]
+	finalStats := stats.FromProtoArray(playerStats.FinalStats.Stats)
+	gearStats := stats.FromProtoArray(playerStats.GearStats.Stats)
+	caps := make([]gearCap, 0, len(statCaps))
+	for _, statCap := range statCaps {
+		stat := stats.Stat(statCap.Stat)
+		// Computed caps know the total, which may include more than the stat itself (e.g. hit chance from talents).
+		total := Ternary(statCap.Kind != proto.StatCapKind_StatCapKindUnknown, statCap.Current, finalStats[stat])
+		offset := total - gearStats[stat]
+		for _, contribution := range statCap.Contributions {
+			offset -= contribution.Coefficient * gearStats[stats.Stat(contribution.Stat)]
+		}
+		caps = append(caps, gearCap{StatCap: statCap, offset: offset})
+	}
+	return caps, nil
+}
+
+// A stat cap, with the part of the capped total which does not come from gear.
+type gearCap struct {
+	*proto.StatCap
+	offset float64
+}
+
+// A physical item which can be equipped, either currently equipped or a candidate.
+type gearPiece struct {
+	spec  *proto.ItemSpec
+	item  Item
+	slots []proto.ItemSlot
+}
+
+// The item in one slot of a gear set, along with its reforge, gems, enchant and tinker.
+type gearSlot struct {
+	piece   int // Index into gearSearch.pieces, or -1 if the slot is empty.
+	reforge int32
+	enchant int32
+	tinker  int32
+	gems    []int32
+}
+
+type gearSet [NumItemSlots]gearSlot
+
+func (set *gearSet) key() string {
+	var sb strings.Builder
+	for _, slot := range set {
+		fmt.Fprintf(&sb, "%d:%d:%d:%d:%v;", slot.piece, slot.reforge, slot.enchant, slot.tinker, slot.gems)
+	}
+	return sb.String()
+}
+
+// Gear sets are ranked by unmet meta gem requirements first, then by EP.
+type gearScore struct {
+	missingMetaGems int
+	metaGemActive   bool
+	ep              float64
+}
+
+func (score gearScore) betterThan(other gearScore) bool {
+	if score.missingMetaGems != other.missingMetaGems {
+		return score.missingMetaGems < other.missingMetaGems
+	}
+	return score.ep > other.ep
+}
+
+type scoredGearSet struct {
+	set   gearSet
+	score gearScore
+}
+
+// gearSearch finds the gear sets with the highest EP by local search: starting from the
+// equipped gear, it repeatedly applies the best single change (an item, reforge, gem or enchant)
+// to each slot until no change improves the score. Caps and the meta gem requirement couple the
+// slots together, which is why slots can't simply be optimized independently.
+type gearSearch struct {
+	player          *proto.Player
+	isFuryWarrior   bool
+	weights         stats.Stats
+	caps            []gearCap
+	metaRequirement *proto.MetaGemRequirement
+
+	pieces           []gearPiece
+	gems             []Gem
+	enchants         []Enchant
+	optimizeReforges bool
+
+	initial gearSet
+}
+
+func newGearSearch(player *proto.Player, request *proto.GearOptimizerRequest, weights stats.Stats, caps []gearCap) (*gearSearch, error) {
+	gs := &gearSearch{
+		player:           player,
+		isFuryWarrior:    player.GetFuryWarrior() != nil,
+		weights:          weights,
+		caps:             caps,
+		metaRequirement:  request.MetaGemRequirement,
+		optimizeReforges: request.OptimizeReforges,
+	}
+
+	addPiece := func(spec *proto.ItemSpec) (int, error) {
+		item, ok := ItemsByID[spec.Id]
+		if !ok {
+			return -1, fmt.Errorf("unknown item with id %d", spec.Id)
+		}
+		gs.pieces = append(gs.pieces, gearPiece{
+			spec:  spec,
+			item:  item,
+			slots: eligibleSlotsForItem(&item, gs.isFuryWarrior),
+		})
+		return len(gs.pieces) - 1, nil
+	}
+
+	gemIDs := slices.Clone(request.Gems)
+	for slot := range gs.initial {
+		gs.initial[slot].piece = -1
+	}
+	for slot, spec := range player.GetEquipment().GetItems() {
+		if spec.Id == 0 || slot >= int(NumItemSlots) {
+			continue
+		}
+		piece, err := addPiece(spec)
+		if err != nil {
+			return nil, err
+		}
+		gs.initial[slot] = gearSlot{
+			piece:   piece,
+			reforge: spec.Reforging,
+			enchant: spec.Enchant,
+			tinker:  spec.Tinker,
+			gems:    gs.socketGems(piece, spec.Gems),
+		}
+		gemIDs = append(gemIDs, spec.Gems...)
+	}
+	for _, spec := range request.CandidateItems {
+		if _, err := addPiece(spec); err != nil {
+			return nil, err
+		}
+		gemIDs = append(gemIDs, spec.Gems...)
+	}
+
+	slices.Sort(gemIDs)
+	for _, id := range slices.Compact(gemIDs) {
+		if id == 0 {
+			continue
+		}
+		gem, ok := GemsByID[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown gem with id %d", id)
+		}
+		gs.gems = append(gs.gems, gem)
+	}
+	for _, id := range request.Enchants {
+		enchant, ok := EnchantsByEffectID[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown enchant with id %d", id)
+		}
+		gs.enchants = append(gs.enchants, enchant)
+	}
+
+	return gs, nil
+}
+
+// Returns the gems for a piece padded to its number of sockets, including extra sockets.
+func (gs *gearSearch) socketGems(piece int, gems []int32) []int32 {
+	item := &gs.pieces[piece].item
+	numSockets := len(item.GemSockets)
+	if item.hasExtraSocket(playerHasProfession(gs.player, proto.Profession_Blacksmithing)) {
+		numSockets++
+	}
+	numSockets = max(numSockets, len(gems))
+	padded := make([]int32, numSockets)
+	copy(padded, gems)
+	return padded
+}
+
+func (gs *gearSearch) socketColor(piece int, socketIdx int) proto.GemColor {
+	if sockets := gs.pieces[piece].item.GemSockets; socketIdx < len(sockets) {
+		return sockets[socketIdx]
+	}
+	// Extra sockets, e.g. from belt buckles, accept any gem.
+	return proto.GemColor_GemColorPrismatic
+}
+
+func (gs *gearSearch) itemSpec(slot gearSlot) *proto.ItemSpec {
+	spec := goproto.Clone(gs.pieces[slot.piece].spec).(*proto.ItemSpec)
+	spec.Reforging = slot.reforge
+	spec.Enchant = slot.enchant
+	spec.Tinker = slot.tinker
+	spec.Gems = slices.Clone(slot.gems)
+	return spec
+}
+
+func (gs *gearSearch) equipmentSpec(set *gearSet) *proto.EquipmentSpec {
+	equipment := &proto.EquipmentSpec{Items: make([]*proto.ItemSpec, NumItemSlots)}
+	for i, slot := range set {
+		if slot.piece < 0 {
+			equipment.Items[i] = &proto.ItemSpec{}
+		} else {
+			equipment.Items[i] = gs.itemSpec(slot)
+		}
+	}
+	return equipment
+}
+
+func (gs *gearSearch) slotStats(slot gearSlot) stats.Stats {
+	if slot.piece < 0 {
+		return stats.Stats{}
+	}
+	spec := gs.itemSpec(slot)
+	item := NewItem(ItemSpec{
+		ID:            spec.Id,
+		RandomSuffix:  spec.RandomSuffix,
+		Enchant:       spec.Enchant,
+		Tinker:        spec.Tinker,
+		Gems:          spec.Gems,
+		Reforging:     spec.Reforging,
+		UpgradeStep:   spec.UpgradeStep,
+		ChallengeMode: spec.ChallengeMode,
+	})
+	return ItemEquipmentBaseStats(item).Add(ItemEquipmentGemAndEnchantStats(item))
+}
+
+func (gs *gearSearch) score(set *gearSet) gearScore {
+	var gearStats stats.Stats
+	var numRed, numYellow, numBlue int32
+	hasMetaGem := false
+	for _, slot := range set {
+		gearStats = gearStats.Add(gs.slotStats(slot))
+		for _, id := range slot.gems {
+			gem, ok := GemsByID[id]
+			if !ok {
+				continue
+			}
+			if gem.Color == proto.GemColor_GemColorMeta {
+				hasMetaGem = true
+				continue
+			}
+			if ColorIntersects(proto.GemColor_GemColorRed, gem.Color) {
+				numRed++
+			}
+			if ColorIntersects(proto.GemColor_GemColorYellow, gem.Color) {
+				numYellow++
+			}
+			if ColorIntersects(proto.GemColor_GemColorBlue, gem.Color) {
+				numBlue++
+			}
+		}
+	}
+
+	score := gearScore{}
+	if hasMetaGem {
+		req := gs.metaRequirement
+		score.missingMetaGems = int(max(0, req.GetMinRed()-numRed) + max(0, req.GetMinYellow()-numYellow) + max(0, req.GetMinBlue()-numBlue))
+		score.metaGemActive = score.missingMetaGems == 0
+	}
+
+	weights := gs.weights
+	for _, statCap := range gs.caps {
+		stat := stats.Stat(statCap.Stat)
+		weight := gs.weights[stat]
+		total := statCap.offset + gearStats[stat]
+		weights[stat] = 0
+		for _, contribution := range statCap.Contributions {
+			contributingStat := stats.Stat(contribution.Stat)
+			total += contribution.Coefficient * gearStats[contributingStat]
+			// The weight of a contributing stat includes its value towards the cap, which is counted below instead.
+			weights[contributingStat] -= contribution.Coefficient * weight
+		}
+
+		// EP of the gear's contribution to a capped stat, given what the character already has.
+		capped := func(total float64) float64 {
+			return weight*min(total, statCap.Cap) + statCap.WeightAboveCap*max(0, total-statCap.Cap)
+		}
+		score.ep += capped(total) - capped(statCap.offset)
+	}
+	score.ep += statsEP(weights, gearStats)
+	return score
+}
+
+func statsEP(weights stats.Stats, values stats.Stats) float64 {
+	ep := 0.0
+	for stat, value := range values {
+		ep += weights[stat] * value
+	}
+	return ep
+}
+
+func (gs *gearSearch) isValid(set *gearSet) bool {
+	used := make(map[int]bool, NumItemSlots)
+	for _, slot := range set {
+		if slot.piece < 0 {
+			continue
+		}
+		if used[slot.piece] {
+			return false
+		}
+		used[slot.piece] = true
+	}
+	return isValidEquipment(gs.equipmentSpec(set), gs.isFuryWarrior)
+}
+
+// All gear sets which differ from set only in the given slot.
+func (gs *gearSearch) slotMoves(set *gearSet, itemSlot proto.ItemSlot) []gearSet {
+	var moves []gearSet
+	current := set[itemSlot]
+	addMove := func(slot gearSlot) {
+		move := *set
+		move[itemSlot] = slot
+		// Equipping a two-hander means unequipping the off hand.
+		if itemSlot == proto.ItemSlot_ItemSlotMainHand && !gs.isFuryWarrior && gs.pieces[slot.piece].item.HandType == proto.HandType_HandTypeTwoHand {
+			move[proto.ItemSlot_ItemSlotOffHand] = gearSlot{piece: -1}
+		}
+		if gs.isValid(&move) {
+			moves = append(moves, move)
+		}
+	}
+
+	for piece := range gs.pieces {
+		if piece == current.piece || !slices.Contains(gs.pieces[piece].slots, itemSlot) {
+			continue
+		}
+		spec := gs.pieces[piece].spec
+		slot := gearSlot{
+			piece:   piece,
+			reforge: spec.Reforging,
+			enchant: spec.Enchant,
+			tinker:  spec.Tinker,
+			gems:    gs.socketGems(piece, spec.Gems),
+		}
+		// Keep the slot's enchant and tinker when swapping in an item without them, if it can take them.
+		if slot.enchant == 0 && current.piece >= 0 && gs.canUseEnchant(current.enchant, piece) {
+			slot.enchant = current.enchant
+		}
+		if slot.tinker == 0 && current.piece >= 0 && gs.canUseEnchant(current.tinker, piece) {
+			slot.tinker = current.tinker
+		}
+		addMove(slot)
+	}
+
+	if current.piece < 0 {
+		return moves
+	}
+	item := gs.pieces[current.piece].item
+
+	if gs.optimizeReforges {
+		baseItem := gs.itemSpec(current)
+		unreforged := NewItem(ItemSpec{ID: baseItem.Id, RandomSuffix: baseItem.RandomSuffix, UpgradeStep: baseItem.UpgradeStep, ChallengeMode: baseItem.ChallengeMode})
+		for _, id := range sortedReforgeIDs() {
+			if id == current.reforge || !validateReforging(&unreforged, ReforgeStatsByID[id]) {
+				continue
+			}
+			slot := current
+			slot.reforge = id
+			addMove(slot)
+		}
+		if current.reforge != 0 {
+			slot := current
+			slot.reforge = 0
+			addMove(slot)
+		}
+	}
+
+	// Single gem changes, plus one move which matches every socket to get the socket bonus,
+	// since that usually can't be reached one gem at a time.
+	matched := current
+	matched.gems = slices.Clone(current.gems)
+	for socketIdx := range current.gems {
+		color := gs.socketColor(current.piece, socketIdx)
+		bestMatchEP := math.Inf(-1)
+		for _, gem := range gs.gems {
+			if !gemFitsSocket(gem, color) {
+				continue
+			}
+			if gem.ID != current.gems[socketIdx] {
+				slot := current
+				slot.gems = slices.Clone(current.gems)
+				slot.gems[socketIdx] = gem.ID
+				addMove(slot)
+			}
+			if ep := statsEP(gs.weights, gem.Stats); ColorIntersects(color, gem.Color) && ep > bestMatchEP {
+				bestMatchEP = ep
+				matched.gems[socketIdx] = gem.ID
+			}
+		}
+	}
+	if !slices.Equal(matched.gems, current.gems) {
+		addMove(matched)
+	}
+
+	for _, enchant := range gs.enchants {
+		if enchant.EffectID != current.enchant && enchantFitsSlot(enchant, item, itemSlot) && canUseEnchant(&enchant, &item, gs.player) {
+			slot := current
+			slot.enchant = enchant.EffectID
+			addMove(slot)
+		}
+	}
+
+	return moves
+}
+
+// Returns the best distinct gear sets found, best first.
+func (gs *gearSearch) run(numResults int) []gearSet {
+	var best []scoredGearSet
+	seen := make(map[string]bool)
+	record := func(set gearSet, score gearScore) {
+		key := set.key()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		idx := sort.Search(len(best), func(i int) bool { return score.betterThan(best[i].score) })
+		if idx >= numResults {
+			return
+		}
+		best = slices.Insert(best, idx, scoredGearSet{set: set, score: score})
+		if len(best) > numResults {
+			best = best[:numResults]
+		}
+	}
+
+	current := gs.initial
+	currentScore := gs.score(&current)
+	record(current, currentScore)
+	for improved := true; improved; {
+		improved = false
+		for itemSlot := range NumItemSlots {
+			for _, move := range gs.slotMoves(&current, proto.ItemSlot(itemSlot)) {
+				score := gs.score(&move)
+				record(move, score)
+				if score.betterThan(currentScore) {
+					current, currentScore = move, score
+					improved = true
+				}
+			}
+		}
+	}
+
+	return MapSlice(best, func(s scoredGearSet) gearSet { return s.set })
+}
+
+func (gs *gearSearch) result(set gearSet) *proto.GearOptimizerCandidate {
+	score := gs.score(&set)
+	return &proto.GearOptimizerCandidate{
+		Equipment:     gs.equipmentSpec(&set),
+		Ep:            score.ep,
+		MetaGemActive: score.metaGemActive,
+	}
+}
+
+func sortedReforgeIDs() []int32 {
+	ids := make([]int32, 0, len(ReforgeStatsByID))
+	for id := range ReforgeStatsByID {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Whether a gem can be socketed into a socket of the given color, regardless of socket bonuses.
+func gemFitsSocket(gem Gem, socketColor proto.GemColor) bool {
+	switch socketColor {
+	case proto.GemColor_GemColorMeta, proto.GemColor_GemColorCogwheel, proto.GemColor_GemColorShaTouched:
+		return gem.Color == socketColor
+	}
+	switch gem.Color {
+	case proto.GemColor_GemColorMeta, proto.GemColor_GemColorCogwheel, proto.GemColor_GemColorShaTouched:
+		return false
+	}
+	return true
+}
+
+func (gs *gearSearch) canUseEnchant(effectID int32, piece int) bool {
+	enchant, ok := EnchantsByEffectID[effectID]
+	return ok && canUseEnchant(&enchant, &gs.pieces[piece].item, gs.player)
+}
+
+func enchantFitsSlot(enchant Enchant, item Item, slot proto.ItemSlot) bool {
+	switch enchant.Type {
+	case proto.ItemType_ItemTypeUnknown:
+		// Some shield enchants parse as ItemTypeUnknown, see Equipment.EquipEnchant.
+		return slot == proto.ItemSlot_ItemSlotOffHand
+	case proto.ItemType_ItemTypeWeapon:
+		return item.Type == proto.ItemType_ItemTypeWeapon && (slot == proto.ItemSlot_ItemSlotMainHand || slot == proto.ItemSlot_ItemSlotOffHand)
+	}
+	return ItemTypeToSlot(enchant.Type) == slot
+}
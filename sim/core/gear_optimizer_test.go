@@ -0,0 +1,254 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+const (
+	optimizerHelm      = 990001
+	optimizerChest     = 990002
+	optimizerSword     = 990003
+	optimizerRedGem    = 990011
+	optimizerYellowGem = 990012
+	optimizerBlueGem   = 990013
+	optimizerMetaGem   = 990014
+	optimizerEnchant   = 990021
+	optimizerCritToHit = 990031
+	optimizerCritToHst = 990032
+)
+
+func addOptimizerTestDatabase() {
+	scaling := func(statValues map[stats.Stat]float64) map[int32]*proto.ScalingItemProperties {
+		protoStats := make(map[int32]float64)
+		for stat, value := range statValues {
+			protoStats[int32(stat)] = value
+		}
+		return map[int32]*proto.ScalingItemProperties{int32(proto.ItemLevelState_Base): {Stats: protoStats}}
+	}
+	gemStats := func(stat stats.Stat, value float64) []float64 {
+		var s stats.Stats
+		s[stat] = value
+		return s.ToProtoArray()
+	}
+
+	var socketBonus stats.Stats
+	socketBonus[stats.CritRating] = 30
+
+	addToDatabase(&proto.SimDatabase{
+		Items: []*proto.SimItem{
+			{
+				Id:             optimizerHelm,
+				Type:           proto.ItemType_ItemTypeHead,
+				GemSockets:     []proto.GemColor{proto.GemColor_GemColorMeta, proto.GemColor_GemColorRed},
+				ScalingOptions: scaling(map[stats.Stat]float64{stats.Stamina: 100}),
+			},
+			{
+				Id:             optimizerChest,
+				Type:           proto.ItemType_ItemTypeChest,
+				GemSockets:     []proto.GemColor{proto.GemColor_GemColorRed, proto.GemColor_GemColorBlue},
+				SocketBonus:    socketBonus.ToProtoArray(),
+				ScalingOptions: scaling(map[stats.Stat]float64{stats.CritRating: 1000}),
+			},
+			{
+				Id:             optimizerSword,
+				Type:           proto.ItemType_ItemTypeWeapon,
+				HandType:       proto.HandType_HandTypeMainHand,
+				ScalingOptions: scaling(map[stats.Stat]float64{stats.Stamina: 10}),
+			},
+		},
+		Gems: []*proto.SimGem{
+			{Id: optimizerRedGem, Color: proto.GemColor_GemColorRed, Stats: gemStats(stats.CritRating, 100)},
+			{Id: optimizerYellowGem, Color: proto.GemColor_GemColorYellow, Stats: gemStats(stats.HasteRating, 100)},
+			{Id: optimizerBlueGem, Color: proto.GemColor_GemColorBlue, Stats: gemStats(stats.MasteryRating, 100)},
+			{Id: optimizerMetaGem, Color: proto.GemColor_GemColorMeta, Stats: gemStats(stats.Stamina, 10)},
+		},
+		Enchants: []*proto.SimEnchant{
+			{EffectId: optimizerEnchant, Type: proto.ItemType_ItemTypeChest, Stats: gemStats(stats.HasteRating, 50)},
+		},
+		ReforgeStats: []*proto.ReforgeStat{
+			{Id: optimizerCritToHit, FromStat: proto.Stat_StatCritRating, ToStat: proto.Stat_StatHitRating, Multiplier: 0.4},
+			{Id: optimizerCritToHst, FromStat: proto.Stat_StatCritRating, ToStat: proto.Stat_StatHasteRating, Multiplier: 0.4},
+		},
+	})
+}
+
+func newOptimizerTestPlayer() *proto.Player {
+	equipment := &proto.EquipmentSpec{Items: make([]*proto.ItemSpec, NumItemSlots)}
+	for i := range equipment.Items {
+		equipment.Items[i] = &proto.ItemSpec{}
+	}
+	equipment.Items[proto.ItemSlot_ItemSlotHead] = &proto.ItemSpec{Id: optimizerHelm, Gems: []int32{optimizerMetaGem, 0}}
+	equipment.Items[proto.ItemSlot_ItemSlotChest] = &proto.ItemSpec{Id: optimizerChest}
+	equipment.Items[proto.ItemSlot_ItemSlotMainHand] = &proto.ItemSpec{Id: optimizerSword}
+	return &proto.Player{Name: "Player", Equipment: equipment}
+}
+
+func newOptimizerTestWeights(hit, crit, haste, mastery float64) stats.Stats {
+	var weights stats.Stats
+	weights[stats.HitRating] = hit
+	weights[stats.CritRating] = crit
+	weights[stats.HasteRating] = haste
+	weights[stats.MasteryRating] = mastery
+	return weights
+}
+
+func TestGearSearch(t *testing.T) {
+	addOptimizerTestDatabase()
+
+	allGems := []int32{optimizerRedGem, optimizerYellowGem, optimizerBlueGem}
+
+	for _, tc := range []struct {
+		comment   string
+		request   *proto.GearOptimizerRequest
+		weights   stats.Stats
+		caps      []gearCap
+		wantHelm  []int32
+		wantChest *proto.ItemSpec
+	}{
+		{
+			comment:   "best gem in every socket when the socket bonus is worth less",
+			request:   &proto.GearOptimizerRequest{Gems: allGems},
+			weights:   newOptimizerTestWeights(0, 0.1, 1, 0.1),
+			wantHelm:  []int32{optimizerMetaGem, optimizerYellowGem},
+			wantChest: &proto.ItemSpec{Id: optimizerChest, Gems: []int32{optimizerYellowGem, optimizerYellowGem}},
+		},
+		{
+			comment:   "matching gems when the socket bonus is worth more",
+			request:   &proto.GearOptimizerRequest{Gems: allGems},
+			weights:   newOptimizerTestWeights(0, 1, 0.95, 0.95),
+			wantHelm:  []int32{optimizerMetaGem, optimizerRedGem},
+			wantChest: &proto.ItemSpec{Id: optimizerChest, Gems: []int32{optimizerRedGem, optimizerBlueGem}},
+		},
+		{
+			comment: "meta gem requirement takes priority over EP",
+			request: &proto.GearOptimizerRequest{
+				Gems:               allGems,
+				MetaGemRequirement: &proto.MetaGemRequirement{MinBlue: 2},
+			},
+			weights:   newOptimizerTestWeights(0, 0, 1, 0.1),
+			wantHelm:  []int32{optimizerMetaGem, optimizerBlueGem},
+			wantChest: &proto.ItemSpec{Id: optimizerChest, Gems: []int32{optimizerYellowGem, optimizerBlueGem}},
+		},
+		{
+			comment:   "reforges and enchants for the best stat",
+			request:   &proto.GearOptimizerRequest{OptimizeReforges: true, Enchants: []int32{optimizerEnchant}},
+			weights:   newOptimizerTestWeights(2, 0.5, 0.9, 0),
+			wantHelm:  []int32{optimizerMetaGem, 0},
+			wantChest: &proto.ItemSpec{Id: optimizerChest, Reforging: optimizerCritToHit, Enchant: optimizerEnchant, Gems: []int32{0, 0}},
+		},
+		{
+			comment:   "reforges away from a capped stat",
+			request:   &proto.GearOptimizerRequest{OptimizeReforges: true},
+			weights:   newOptimizerTestWeights(2, 0.5, 0.9, 0),
+			caps:      []gearCap{{StatCap: &proto.StatCap{Stat: proto.Stat_StatHitRating, Cap: 150}, offset: 50}},
+			wantHelm:  []int32{optimizerMetaGem, 0},
+			wantChest: &proto.ItemSpec{Id: optimizerChest, Reforging: optimizerCritToHst, Gems: []int32{0, 0}},
+		},
+		{
+			// The chest's crit alone gets 100 of the way to the cap, so reforging to hit would mostly go to waste.
+			comment: "contributing stats count towards the cap",
+			request: &proto.GearOptimizerRequest{OptimizeReforges: true},
+			weights: newOptimizerTestWeights(2, 0.5, 0.4, 0),
+			caps: []gearCap{{StatCap: &proto.StatCap{
+				Stat:          proto.Stat_StatHitRating,
+				Cap:           150,
+				Contributions: []*proto.StatCapContribution{{Stat: proto.Stat_StatCritRating, Coefficient: 0.1}},
+			}}},
+			wantHelm:  []int32{optimizerMetaGem, 0},
+			wantChest: &proto.ItemSpec{Id: optimizerChest, Gems: []int32{0, 0}},
+		},
+	} {
+		t.Run(tc.comment, func(t *testing.T) {
+			search, err := newGearSearch(newOptimizerTestPlayer(), tc.request, tc.weights, tc.caps)
+			if err != nil {
+				t.Fatalf("newGearSearch() returned error: %v", err)
+			}
+			best := search.run(3)
+			if len(best) == 0 {
+				t.Fatalf("run() returned no gear sets")
+			}
+
+			equipment := search.equipmentSpec(&best[0])
+			helm := equipment.Items[proto.ItemSlot_ItemSlotHead]
+			if !slices.Equal(helm.Gems, tc.wantHelm) {
+				t.Errorf("helm gems = %v, want %v", helm.Gems, tc.wantHelm)
+			}
+			// Gems of equal EP may end up in either socket.
+			chest := equipment.Items[proto.ItemSlot_ItemSlotChest]
+			if chest.Reforging != tc.wantChest.Reforging || chest.Enchant != tc.wantChest.Enchant || !slices.Equal(slices.Sorted(slices.Values(chest.Gems)), slices.Sorted(slices.Values(tc.wantChest.Gems))) {
+				t.Errorf("chest = %v, want %v", chest, tc.wantChest)
+			}
+
+			for i := 1; i < len(best); i++ {
+				if search.score(&best[i]).betterThan(search.score(&best[i-1])) {
+					t.Errorf("gear set %d scores better than gear set %d", i, i-1)
+				}
+			}
+		})
+	}
+}
+
+func TestGearOptimizer(t *testing.T) {
+	addOptimizerTestDatabase()
+
+	// DPS only depends on haste, so the sims agree with the EP ranking.
+	fakeRunSim := func(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, skipPresim bool, signals simsignals.Signals) *proto.RaidSimResult {
+		equipment := ProtoToEquipment(rsr.Raid.Parties[0].Players[0].Equipment)
+		dps := &proto.DistributionMetrics{Avg: equipment.Stats(proto.Spec_SpecUnknown)[stats.HasteRating]}
+		return &proto.RaidSimResult{
+			RaidMetrics: &proto.RaidMetrics{
+				Dps: dps,
+				Parties: []*proto.PartyMetrics{{
+					Dps:     dps,
+					Players: []*proto.UnitMetrics{{Dps: dps}},
+				}},
+			},
+		}
+	}
+
+	weights := newOptimizerTestWeights(0, 0, 1, 0)
+	optimizer := &gearOptimizer{
+		SingleRaidSimRunner: fakeRunSim,
+		Request: &proto.GearOptimizerRequest{
+			BaseSettings: &proto.RaidSimRequest{
+				Raid: &proto.Raid{
+					Parties: []*proto.Party{{Players: []*proto.Player{newOptimizerTestPlayer()}}},
+				},
+				SimOptions: &proto.SimOptions{Iterations: 10},
+			},
+			Gems:             []int32{optimizerRedGem, optimizerYellowGem},
+			OptimizeReforges: true,
+			StatWeights:      &proto.UnitStats{Stats: weights.ToProtoArray()},
+			NumFinalists:     3,
+		},
+	}
+
+	result := optimizer.Run(simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Run() returned error: %s", result.Error.Message)
+	}
+	if len(result.Candidates) != 3 {
+		t.Fatalf("got %d candidates, want 3", len(result.Candidates))
+	}
+
+	// Yellow gems in all 3 non-meta sockets, and crit reforged to haste.
+	if got, want := result.Candidates[0].Dps.Avg, 3*100+400.0; got != want {
+		t.Errorf("best candidate DPS = %f, want %f", got, want)
+	}
+	if got, want := result.Candidates[0].Ep, 3*100+400.0; got != want {
+		t.Errorf("best candidate EP = %f, want %f", got, want)
+	}
+	for i := 1; i < len(result.Candidates); i++ {
+		if result.Candidates[i].Dps.Avg > result.Candidates[i-1].Dps.Avg {
+			t.Errorf("candidates are not sorted by DPS")
+		}
+	}
+	if result.EquippedGear.Dps.Avg != 0 {
+		t.Errorf("equipped gear DPS = %f, want 0", result.EquippedGear.Dps.Avg)
+	}
+}
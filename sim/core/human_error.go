@@ -0,0 +1,147 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// A log-normal delay in milliseconds, parameterized by the mean and standard deviation of the
+// underlying normal distribution.
+type humanLatency struct {
+	mean  float64
+	mu    float64
+	sigma float64
+}
+
+func newHumanLatency(config *proto.HumanLatency, scale float64) humanLatency {
+	mean := max(0, config.GetMeanMs()) * scale
+	stddev := max(0, config.GetStddevMs()) * scale
+	if mean == 0 {
+		return humanLatency{}
+	}
+
+	variance := math.Log1p((stddev * stddev) / (mean * mean))
+	return humanLatency{
+		mean:  mean,
+		mu:    math.Log(mean) - variance/2,
+		sigma: math.Sqrt(variance),
+	}
+}
+
+func (hl humanLatency) draw(sim *Simulation, label string) time.Duration {
+	if hl.mean == 0 {
+		return 0
+	}
+
+	// Box-Muller transform, using 1 - u so the log never sees 0.
+	u1 := 1 - sim.RandomFloat(label)
+	u2 := sim.RandomFloat(label)
+	normal := math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+	return time.Duration(math.Round(math.Exp(hl.mu+hl.sigma*normal)*1000)) * time.Microsecond
+}
+
+// A delay drawn for an event that happened at a given time, so repeated checks of the same
+// event see the same delay.
+type humanDelay struct {
+	at    time.Duration
+	delay time.Duration
+}
+
+type humanErrorModel struct {
+	gcdLatency      humanLatency
+	missedGCDChance float64
+	cooldownDelay   humanLatency
+	procReaction    humanLatency
+
+	cooldownDelays map[*Spell]humanDelay
+	procDelays     map[*Aura]humanDelay
+}
+
+// Returns nil for a perfect player, so callers only need a nil check.
+func newHumanErrorModel(config *proto.HumanErrorModel) *humanErrorModel {
+	if config == nil || config.Skill >= 100 {
+		return nil
+	}
+
+	scale := 1 - max(0, config.Skill)/100
+	return &humanErrorModel{
+		gcdLatency:      newHumanLatency(config.GcdLatency, scale),
+		missedGCDChance: max(0, config.MissedGcdChance) / 100 * scale,
+		cooldownDelay:   newHumanLatency(config.CooldownDelay, scale),
+		procReaction:    newHumanLatency(config.ProcReaction, scale),
+
+		cooldownDelays: make(map[*Spell]humanDelay),
+		procDelays:     make(map[*Aura]humanDelay),
+	}
+}
+
+func (hem *humanErrorModel) reset() {
+	clear(hem.cooldownDelays)
+	clear(hem.procDelays)
+}
+
+// Delays the next rotation action after a cast which triggered the GCD, by the input latency and
+// sometimes by a whole missed GCD. The prepull is played as planned.
+func (unit *Unit) delayNextGCDAction(sim *Simulation) {
+	hem := unit.humanError
+	if hem == nil || unit.rotationAction == nil || sim.CurrentTime < 0 {
+		return
+	}
+
+	delay := hem.gcdLatency.draw(sim, "Human Error GCD Latency")
+	if sim.Proc(hem.missedGCDChance, "Human Error Missed GCD") {
+		delay += unit.SpellGCD()
+		if sim.Log != nil {
+			unit.Log(sim, "Missed a GCD.")
+		}
+	}
+
+	if delay > 0 {
+		unit.SetRotationTimer(sim, unit.NextRotationActionAt()+delay)
+	}
+}
+
+// Whether the player has noticed that a major cooldown is ready. Cooldowns which are ready from
+// the pull are planned for, so they are always noticed.
+func (unit *Unit) hasNoticedCooldown(sim *Simulation, spell *Spell) bool {
+	hem := unit.humanError
+	if hem == nil || !spell.Flags.Matches(SpellFlagMCD) {
+		return true
+	}
+
+	readyAt := spell.ReadyAt()
+	if readyAt <= 0 {
+		return true
+	}
+
+	cooldownDelay, ok := hem.cooldownDelays[spell]
+	if !ok || cooldownDelay.at != readyAt {
+		cooldownDelay = humanDelay{
+			at:    readyAt,
+			delay: hem.cooldownDelay.draw(sim, "Human Error Cooldown Delay"),
+		}
+		hem.cooldownDelays[spell] = cooldownDelay
+	}
+	return sim.CurrentTime >= readyAt+cooldownDelay.delay
+}
+
+// Time it takes the player to react to the aura changing at changedAt, which varies from proc to
+// proc for an imperfect player.
+func (unit *Unit) reactionTimeTo(sim *Simulation, aura *Aura, changedAt time.Duration) time.Duration {
+	hem := unit.humanError
+	if hem == nil {
+		return unit.ReactionTime
+	}
+
+	procDelay, ok := hem.procDelays[aura]
+	if !ok || procDelay.at != changedAt {
+		procDelay = humanDelay{
+			at:    changedAt,
+			delay: hem.procReaction.draw(sim, "Human Error Proc Reaction"),
+		}
+		hem.procDelays[aura] = procDelay
+	}
+	return unit.ReactionTime + procDelay.delay
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestHumanErrorPerfectPlayer(t *testing.T) {
+	if newHumanErrorModel(nil) != nil {
+		t.Fatalf("Expected no human error model without a config")
+	}
+	if newHumanErrorModel(&proto.HumanErrorModel{Skill: 100, MissedGcdChance: 10}) != nil {
+		t.Fatalf("Expected no human error model at 100 skill")
+	}
+}
+
+func TestHumanLatencyDistribution(t *testing.T) {
+	sim := setupTimelineSim(&proto.EncounterTimeline{})
+	latency := &proto.HumanLatency{MeanMs: 250, StddevMs: 100}
+
+	for _, skill := range []float64{0, 50} {
+		hem := newHumanErrorModel(&proto.HumanErrorModel{Skill: skill, GcdLatency: latency})
+		expectedMean := 250 * (1 - skill/100)
+		expectedStddev := 100 * (1 - skill/100)
+
+		const numDraws = 20000
+		sum, sumSquares := 0.0, 0.0
+		for i := 0; i < numDraws; i++ {
+			ms := float64(hem.gcdLatency.draw(sim, "Human Error GCD Latency")) / float64(time.Millisecond)
+			if ms <= 0 {
+				t.Fatalf("Expected only positive latencies, got %0.2fms", ms)
+			}
+			sum += ms
+			sumSquares += ms * ms
+		}
+		mean := sum / numDraws
+		stddev := math.Sqrt(sumSquares/numDraws - mean*mean)
+
+		if math.Abs(mean-expectedMean) > expectedMean*0.03 {
+			t.Fatalf("Expected a mean latency of %0.0fms at %0.0f skill, got %0.2fms", expectedMean, skill, mean)
+		}
+		if math.Abs(stddev-expectedStddev) > expectedStddev*0.1 {
+			t.Fatalf("Expected a latency deviation of %0.0fms at %0.0f skill, got %0.2fms", expectedStddev, skill, stddev)
+		}
+	}
+}
+
+func TestHumanErrorMissedGCD(t *testing.T) {
+	sim := setupTimelineSim(&proto.EncounterTimeline{})
+	player := sim.Raid.AllPlayerUnits[0]
+	player.humanError = newHumanErrorModel(&proto.HumanErrorModel{
+		GcdLatency:      &proto.HumanLatency{MeanMs: 200},
+		MissedGcdChance: 100,
+	})
+	stepTimelineSimUntil(sim, time.Second)
+
+	gcdReadyAt := sim.CurrentTime + time.Millisecond*1500
+	player.SetGCDTimer(sim, gcdReadyAt)
+	player.delayNextGCDAction(sim)
+
+	if player.NextGCDAt() != gcdReadyAt {
+		t.Fatalf("Expected the GCD to be ready at %s, got %s", gcdReadyAt, player.NextGCDAt())
+	}
+	if expected := gcdReadyAt + time.Millisecond*200 + player.SpellGCD(); player.NextRotationActionAt() != expected {
+		t.Fatalf("Expected the next action at %s, got %s", expected, player.NextRotationActionAt())
+	}
+}
+
+func TestHumanErrorCooldownDelay(t *testing.T) {
+	sim := setupTimelineSim(&proto.EncounterTimeline{})
+	player := sim.Raid.AllPlayerUnits[0]
+	spell := &Spell{
+		Flags: SpellFlagMCD,
+		CD:    Cooldown{Timer: player.NewTimer(), Duration: time.Minute},
+	}
+	stepTimelineSimUntil(sim, time.Second*2)
+
+	if !player.hasNoticedCooldown(sim, spell) {
+		t.Fatalf("Expected cooldowns to be noticed by a perfect player")
+	}
+
+	player.humanError = newHumanErrorModel(&proto.HumanErrorModel{
+		CooldownDelay: &proto.HumanLatency{MeanMs: 500},
+	})
+	if !player.hasNoticedCooldown(sim, spell) {
+		t.Fatalf("Expected cooldowns ready from the pull to be noticed")
+	}
+
+	spell.CD.Set(sim.CurrentTime - time.Millisecond*300)
+	if player.hasNoticedCooldown(sim, spell) {
+		t.Fatalf("Expected the cooldown to go unnoticed 300ms after it came up")
+	}
+
+	spell.CD.Set(sim.CurrentTime - time.Millisecond*500)
+	if !player.hasNoticedCooldown(sim, spell) {
+		t.Fatalf("Expected the cooldown to be noticed 500ms after it came up")
+	}
+}
+
+func TestHumanErrorProcReaction(t *testing.T) {
+	sim := setupTimelineSim(&proto.EncounterTimeline{})
+	player := sim.Raid.AllPlayerUnits[0]
+	aura := &Aura{}
+
+	if reactionTime := player.reactionTimeTo(sim, aura, 0); reactionTime != player.ReactionTime {
+		t.Fatalf("Expected a perfect player to react in %s, got %s", player.ReactionTime, reactionTime)
+	}
+
+	player.humanError = newHumanErrorModel(&proto.HumanErrorModel{
+		ProcReaction: &proto.HumanLatency{MeanMs: 300, StddevMs: 150},
+	})
+	first := player.reactionTimeTo(sim, aura, time.Second)
+	if first <= player.ReactionTime {
+		t.Fatalf("Expected a slower reaction than %s, got %s", player.ReactionTime, first)
+	}
+	if again := player.reactionTimeTo(sim, aura, time.Second); again != first {
+		t.Fatalf("Expected the same reaction to the same proc, got %s and %s", first, again)
+	}
+	if next := player.reactionTimeTo(sim, aura, time.Second*2); next == first {
+		t.Fatalf("Expected a new reaction time for the next proc, got %s again", next)
+	}
+}
@@ -0,0 +1,299 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// How long the player idles when an environment is told to wait.
+const InteractiveWaitTime = time.Millisecond * 100
+
+// A resource which is part of an interactive environment's observations.
+type InteractiveResource struct {
+	Name  string
+	Value func(sim *Simulation) float64
+}
+
+// Agents with resources the core doesn't know about, like eclipse energy, implement this to add
+// them to the observations of interactive environments.
+type InteractiveResourceProvider interface {
+	InteractiveResources() []InteractiveResource
+}
+
+// InteractiveEnv runs a sim in interactive mode with a single player's decisions made from
+// outside of the sim, one step at a time, e.g. by a learned rotation. Each episode is one
+// iteration, and rewards are the damage done by the player and its pets.
+type InteractiveEnv struct {
+	sim       *Simulation
+	character *Character
+	target    *Unit
+
+	actions     []*Spell
+	resources   []InteractiveResource
+	auras       []*Aura
+	targetAuras []*Aura
+	schema      *proto.InteractiveEnvSchema
+
+	observation []float64
+	damage      float64
+	done        bool
+}
+
+// Creates an environment controlling the player at playerIndex, counting across all parties.
+// The player's priority list is dropped so the environment is the only thing deciding what to
+// cast, but its prepull actions are kept. Invalid settings which the sim panics on are returned as
+// errors.
+func NewInteractiveEnv(request *proto.RaidSimRequest, playerIndex int) (env *InteractiveEnv, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			env, err = nil, fmt.Errorf("invalid settings: %v", r)
+		}
+	}()
+
+	request = googleProto.Clone(request).(*proto.RaidSimRequest)
+	if request.Raid == nil || request.Encounter == nil {
+		return nil, fmt.Errorf("request needs a raid and an encounter")
+	}
+	if request.SimOptions == nil {
+		request.SimOptions = &proto.SimOptions{}
+	}
+	request.SimOptions.Interactive = true
+
+	var player *proto.Player
+	partyIndex, indexInParty := 0, playerIndex
+	for ; partyIndex < len(request.Raid.Parties); partyIndex++ {
+		players := request.Raid.Parties[partyIndex].Players
+		if indexInParty < len(players) {
+			player = players[indexInParty]
+			break
+		}
+		indexInParty -= len(players)
+	}
+	if playerIndex < 0 || player == nil {
+		return nil, fmt.Errorf("no player at index %d", playerIndex)
+	}
+	player.Rotation = &proto.APLRotation{
+		Type:           proto.APLRotation_TypeAPL,
+		PrepullActions: player.Rotation.GetPrepullActions(),
+	}
+
+	sim := NewSim(request, simsignals.CreateSignals())
+	agent := sim.Raid.Parties[partyIndex].Players[indexInParty]
+	env = &InteractiveEnv{
+		sim:       sim,
+		character: agent.GetCharacter(),
+	}
+	env.target = env.character.CurrentTarget
+	if env.target == nil {
+		return nil, fmt.Errorf("encounter has no targets")
+	}
+
+	env.actions = FilterSlice(env.character.Spellbook, func(spell *Spell) bool {
+		return spell.Flags.Matches(SpellFlagAPL) && !spell.Flags.Matches(SpellFlagPrepullOnly)
+	})
+	env.resources = env.character.interactiveResources()
+	if provider, ok := agent.(InteractiveResourceProvider); ok {
+		env.resources = append(env.resources, provider.InteractiveResources()...)
+	}
+	hasActionID := func(aura *Aura) bool {
+		return !aura.ActionID.IsEmptyAction()
+	}
+	env.auras = FilterSlice(env.character.auras, hasActionID)
+	env.targetAuras = FilterSlice(env.target.auras, hasActionID)
+
+	env.schema = &proto.InteractiveEnvSchema{
+		Actions: MapSlice(env.actions, func(spell *Spell) *proto.ActionID {
+			return spell.ActionID.ToProto()
+		}),
+		Resources: MapSlice(env.resources, func(resource InteractiveResource) string {
+			return resource.Name
+		}),
+		Auras: MapSlice(env.auras, func(aura *Aura) *proto.ActionID {
+			return aura.ActionID.ToProto()
+		}),
+		TargetAuras: MapSlice(env.targetAuras, func(aura *Aura) *proto.ActionID {
+			return aura.ActionID.ToProto()
+		}),
+		ObservationSize: int32(1 + len(env.resources) + 2*len(env.actions) + 2*(len(env.auras)+len(env.targetAuras))),
+	}
+	env.observation = make([]float64, env.schema.ObservationSize)
+	env.done = true
+	return env, nil
+}
+
+// The resources of the core resource bars the character has.
+func (character *Character) interactiveResources() []InteractiveResource {
+	var resources []InteractiveResource
+	add := func(name string, value func(sim *Simulation) float64) {
+		resources = append(resources, InteractiveResource{Name: name, Value: value})
+	}
+
+	if character.HasManaBar() {
+		add("Mana", func(_ *Simulation) float64 { return character.CurrentMana() })
+	}
+	if character.HasRageBar() {
+		add("Rage", func(_ *Simulation) float64 { return character.CurrentRage() })
+	}
+	if character.HasEnergyBar() {
+		add("Energy", func(_ *Simulation) float64 { return character.CurrentEnergy() })
+		// Monks keep their chi in the combo points of their energy bar.
+		comboPoints := Ternary(character.Class == proto.Class_ClassMonk, "Chi", "ComboPoints")
+		add(comboPoints, func(_ *Simulation) float64 { return float64(character.ComboPoints()) })
+	}
+	if character.HasFocusBar() {
+		add("Focus", func(_ *Simulation) float64 { return character.CurrentFocus() })
+	}
+	if character.HasRunicPowerBar() {
+		add("RunicPower", func(_ *Simulation) float64 { return character.CurrentRunicPower() })
+		add("BloodRunes", func(_ *Simulation) float64 { return float64(character.CurrentBloodRunes()) })
+		add("FrostRunes", func(_ *Simulation) float64 { return float64(character.CurrentFrostRunes()) })
+		add("UnholyRunes", func(_ *Simulation) float64 { return float64(character.CurrentUnholyRunes()) })
+		add("DeathRunes", func(_ *Simulation) float64 { return float64(character.CurrentDeathRunes()) })
+	}
+	if bar := character.GetSecondaryResourceBar(); bar != nil {
+		name := "SecondaryResource"
+		if defaultBar, ok := bar.(*DefaultSecondaryResourceBarImpl); ok {
+			name = strings.TrimPrefix(defaultBar.config.Type.String(), "SecondaryResourceType")
+		}
+		add(name, func(_ *Simulation) float64 { return float64(bar.Value()) })
+	}
+	return resources
+}
+
+func (env *InteractiveEnv) Schema() *proto.InteractiveEnvSchema {
+	return env.schema
+}
+
+func (env *InteractiveEnv) Sim() *Simulation {
+	return env.sim
+}
+
+// Starts a new episode and returns its first observation. Observations are only valid until the
+// next call to Reset or Step.
+func (env *InteractiveEnv) Reset(seed int64) []float64 {
+	if !env.done {
+		// Finish the abandoned episode so its pending actions are cleaned up. It still counts
+		// towards Metrics.
+		env.sim.Cleanup()
+	}
+
+	sim := env.sim
+	sim.Reseed(seed)
+	sim.isInPrepull = true
+	sim.reset()
+	sim.PrePull()
+	sim.isInPrepull = false
+	sim.NeedsInput = false
+
+	// Damage done before the first decision, e.g. by prepull actions, is part of the first reward.
+	env.damage = 0
+	env.done = false
+	env.advance()
+	return env.observe()
+}
+
+// Takes an action, then runs the sim until the player needs to make its next decision or the
+// fight ends. Action 0 and actions which can't be cast right now wait for InteractiveWaitTime,
+// any other action i casts Schema().Actions[i-1]. The reward is the damage done in between.
+func (env *InteractiveEnv) Step(action int) (observation []float64, reward float64, done bool) {
+	if env.done {
+		return env.observe(), 0, true
+	}
+
+	sim := env.sim
+	if !env.cast(action) {
+		env.character.WaitUntil(sim, sim.CurrentTime+InteractiveWaitTime)
+		sim.NeedsInput = false
+	}
+	env.advance()
+
+	damage := env.damageDone()
+	reward = damage - env.damage
+	env.damage = damage
+	return env.observe(), reward, env.done
+}
+
+// Whether the action was cast. Off-GCD casts leave the player ready for another decision.
+func (env *InteractiveEnv) cast(action int) bool {
+	if action < 1 || action > len(env.actions) {
+		return false
+	}
+	spell := env.actions[action-1]
+	if !spell.CanCast(env.sim, env.target) || !spell.Cast(env.sim, env.target) {
+		return false
+	}
+	if spell.CurCast.GCD > 0 {
+		env.sim.NeedsInput = false
+	}
+	return true
+}
+
+func (env *InteractiveEnv) advance() {
+	for !env.sim.NeedsInput {
+		if env.sim.Step() {
+			env.sim.Cleanup()
+			env.done = true
+			return
+		}
+	}
+}
+
+// Damage done to opponents this episode by the player and its pets. Unit metrics only include it
+// once the episode is over, so this adds up the spell metrics instead.
+func (env *InteractiveEnv) damageDone() float64 {
+	damage := 0.0
+	addDamage := func(unit *Unit) {
+		for _, spell := range unit.Spellbook {
+			if spell.Flags.Matches(SpellFlagNoMetrics) {
+				continue
+			}
+			for _, spellMetrics := range spell.splitSpellMetrics {
+				for i, spellTargetMetrics := range spellMetrics {
+					if unit.IsOpponent(unit.AttackTables[i].Defender) {
+						damage += spellTargetMetrics.TotalDamage
+					}
+				}
+			}
+		}
+	}
+
+	addDamage(&env.character.Unit)
+	for _, pet := range env.character.Pets {
+		addDamage(&pet.Unit)
+	}
+	return damage
+}
+
+func (env *InteractiveEnv) observe() []float64 {
+	sim := env.sim
+	remaining := max(sim.GetRemainingDuration(), 0)
+	obs := env.observation[:0]
+
+	obs = append(obs, remaining.Seconds())
+	for _, resource := range env.resources {
+		obs = append(obs, resource.Value(sim))
+	}
+	for _, spell := range env.actions {
+		obs = append(obs, spell.TimeToReady(sim).Seconds(), TernaryFloat64(!env.done && spell.CanCast(sim, env.target), 1, 0))
+	}
+	for _, auras := range [][]*Aura{env.auras, env.targetAuras} {
+		for _, aura := range auras {
+			if aura.IsActive() {
+				obs = append(obs, min(aura.RemainingDuration(sim), remaining).Seconds(), float64(aura.GetStacks()))
+			} else {
+				obs = append(obs, 0, 0)
+			}
+		}
+	}
+	return obs
+}
+
+// Metrics of the controlled player, aggregated over all finished episodes.
+func (env *InteractiveEnv) Metrics() *proto.UnitMetrics {
+	return env.character.GetMetricsProto()
+}
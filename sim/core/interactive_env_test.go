@@ -0,0 +1,161 @@
+package core
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func init() {
+	RegisterAgentFactory(
+		proto.Player_ArcaneMage{},
+		proto.Spec_SpecArcaneMage,
+		NewFakeInteractiveAgent,
+		func(player *proto.Player, spec interface{}) {
+			playerSpec, ok := spec.(*proto.Player_ArcaneMage)
+			if !ok {
+				panic("Invalid spec value for Arcane Mage!")
+			}
+			player.Spec = playerSpec
+		},
+	)
+}
+
+type FakeInteractiveAgent struct {
+	FakeAgent
+}
+
+func (fa *FakeInteractiveAgent) InteractiveResources() []InteractiveResource {
+	return []InteractiveResource{{Name: "Fake", Value: func(sim *Simulation) float64 { return sim.CurrentTime.Seconds() }}}
+}
+
+func NewFakeInteractiveAgent(char *Character, _ *proto.Player) Agent {
+	fa := &FakeInteractiveAgent{FakeAgent{Character: *char}}
+	fa.Init = func() {
+		fa.Spell = fa.RegisterSpell(SpellConfig{
+			ActionID:    ActionID{SpellID: 43},
+			SpellSchool: SpellSchoolShadow,
+			ProcMask:    ProcMaskSpellDamage,
+			Flags:       SpellFlagAPL,
+			Cast: CastConfig{
+				DefaultCast: Cast{GCD: GCDDefault},
+			},
+
+			DamageMultiplier: 1,
+			ThreatMultiplier: 1,
+
+			ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+				spell.CalcAndDealDamage(sim, target, 100, spell.OutcomeAlwaysHit)
+			},
+		})
+	}
+	return fa
+}
+
+func newTestInteractiveEnv(t *testing.T) *InteractiveEnv {
+	env, err := NewInteractiveEnv(&proto.RaidSimRequest{
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Name:      "Agent",
+			Race:      proto.Race_RaceTroll,
+			Class:     proto.Class_ClassMage,
+			Spec:      &proto.Player_ArcaneMage{},
+			Equipment: &proto.EquipmentSpec{},
+			Buffs:     &proto.IndividualBuffs{},
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 10,
+			Targets:  []*proto.Target{{Level: 93}},
+		},
+	}, 0)
+	if err != nil {
+		t.Fatalf("NewInteractiveEnv() returned error: %s", err)
+	}
+	return env
+}
+
+func TestInteractiveEnv(t *testing.T) {
+	env := newTestInteractiveEnv(t)
+	schema := env.Schema()
+	fakeAction := 1 + slices.IndexFunc(schema.Actions, func(id *proto.ActionID) bool {
+		return id.GetSpellId() == 43
+	})
+	if fakeAction == 0 {
+		t.Fatalf("schema actions %v don't include the fake spell", schema.Actions)
+	}
+	if schema.Resources[len(schema.Resources)-1] != "Fake" {
+		t.Errorf("schema resources %v don't end with the agent's resource", schema.Resources)
+	}
+
+	obs := env.Reset(1)
+	if len(obs) != int(schema.ObservationSize) {
+		t.Fatalf("observation has %d values, want %d", len(obs), schema.ObservationSize)
+	}
+	if obs[0] != 10 {
+		t.Errorf("remaining duration = %f, want 10", obs[0])
+	}
+	if castable := obs[1+len(schema.Resources)+2*(fakeAction-1)+1]; castable != 1 {
+		t.Errorf("fake spell castable = %f, want 1", castable)
+	}
+
+	obs, reward, done := env.Step(0)
+	if done || reward != 0 || math.Abs(obs[0]-(10-InteractiveWaitTime.Seconds())) > 1e-9 {
+		t.Errorf("Step(0) = (remaining %f, %f, %t), want to wait for %s", obs[0], reward, done, InteractiveWaitTime)
+	}
+
+	var totalReward float64
+	var casts int
+	for !done {
+		obs, reward, done = env.Step(fakeAction)
+		totalReward += reward
+		if reward > 0 {
+			casts++
+		}
+	}
+	// One cast per GCD from 0.1s until the end of the fight.
+	if wantCasts := int((10*time.Second-InteractiveWaitTime)/GCDDefault) + 1; casts != wantCasts {
+		t.Errorf("dealt damage in %d steps, want %d", casts, wantCasts)
+	}
+	if obs[0] != 0 {
+		t.Errorf("remaining duration after the episode = %f, want 0", obs[0])
+	}
+	if dps := env.Metrics().Dps.Avg; math.Abs(dps*10-totalReward) > 1e-6 {
+		t.Errorf("total reward = %f, want the episode's damage %f", totalReward, dps*10)
+	}
+
+	// Episodes with the same seed play out the same, also across environments.
+	other := newTestInteractiveEnv(t)
+	for _, e := range []*InteractiveEnv{env, other} {
+		e.Reset(2)
+	}
+	for done := false; !done; {
+		_, envReward, envDone := env.Step(fakeAction)
+		_, otherReward, otherDone := other.Step(fakeAction)
+		if envReward != otherReward || envDone != otherDone {
+			t.Fatalf("environments diverged: (%f, %t) vs (%f, %t)", envReward, envDone, otherReward, otherDone)
+		}
+		done = envDone
+	}
+}
+
+func TestInteractiveEnvInvalidSettings(t *testing.T) {
+	// No spec, which the sim panics on.
+	_, err := NewInteractiveEnv(&proto.RaidSimRequest{
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Name:      "Agent",
+			Race:      proto.Race_RaceTroll,
+			Class:     proto.Class_ClassMage,
+			Equipment: &proto.EquipmentSpec{},
+			Buffs:     &proto.IndividualBuffs{},
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 10,
+			Targets:  []*proto.Target{{Level: 93}},
+		},
+	}, 0)
+	if err == nil {
+		t.Errorf("NewInteractiveEnv() with invalid settings returned no error")
+	}
+}
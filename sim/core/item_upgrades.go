@@ -0,0 +1,139 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"runtime/debug"
+	"slices"
+	"sort"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// itemUpgradeRunner sims each equipped item at its upgrade steps, using the bulk sim combo runner.
+type itemUpgradeRunner struct {
+	// Function used to run the sim of each upgrade.
+	SingleRaidSimRunner raidSimRunner
+	Request             *proto.ItemUpgradeRequest
+}
+
+func runItemUpgrades(signals simsignals.Signals, request *proto.ItemUpgradeRequest, progress chan *proto.ProgressMetrics) *proto.ItemUpgradeResult {
+	runner := &itemUpgradeRunner{
+		SingleRaidSimRunner: runSim,
+		Request:             request,
+	}
+	return runner.Run(signals, progress)
+}
+
+func (u *itemUpgradeRunner) Run(signals simsignals.Signals, progress chan *proto.ProgressMetrics) (result *proto.ItemUpgradeResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.ItemUpgradeResult{
+				Error: &proto.ErrorOutcome{
+					Message: fmt.Sprintf("%v\nStack Trace:\n%s", err, string(debug.Stack())),
+				},
+			}
+		}
+	}()
+
+	baseSettings, player, err := singlePlayerBaseSettings(u.Request.GetBaseSettings())
+	if err != nil {
+		return &proto.ItemUpgradeResult{
+			Error: &proto.ErrorOutcome{Message: "item upgrades: " + err.Error()},
+		}
+	}
+
+	// The equipped set is the combo without any replacements.
+	upgrades := map[*equipmentSubstitution]*proto.ItemUpgrade{}
+	combos := []singleBulkSim{}
+	isFuryWarrior := player.GetFuryWarrior() != nil
+	addCombo := func(substitution *equipmentSubstitution, upgrade *proto.ItemUpgrade) {
+		request, changeLog := createNewRequestWithSubstitution(baseSettings, substitution, false, isFuryWarrior)
+		combos = append(combos, singleBulkSim{req: request, cl: changeLog, eq: substitution})
+		if upgrade != nil {
+			upgrades[substitution] = upgrade
+		}
+	}
+	addCombo(&equipmentSubstitution{}, nil)
+
+	var challengeModeSet []*itemWithSlot
+	for i, spec := range player.GetEquipment().GetItems() {
+		item, ok := ItemsByID[spec.GetId()]
+		if !ok || item.ScalingOptions[int32(proto.ItemLevelState_Base)] == nil {
+			continue
+		}
+		slot := proto.ItemSlot(i)
+
+		if u.Request.ChallengeMode && !spec.ChallengeMode {
+			challengeModeItem := goproto.Clone(spec).(*proto.ItemSpec)
+			challengeModeItem.ChallengeMode = true
+			challengeModeSet = append(challengeModeSet, &itemWithSlot{Item: challengeModeItem, Slot: slot, Index: len(challengeModeSet)})
+		}
+
+		// Upgrades don't apply in Challenge Mode.
+		if spec.ChallengeMode {
+			continue
+		}
+		for _, state := range item.upgradeStatesAfter(spec.UpgradeStep, u.Request.AllSteps) {
+			upgradedItem := goproto.Clone(spec).(*proto.ItemSpec)
+			upgradedItem.UpgradeStep = state
+			addCombo(&equipmentSubstitution{Items: []*itemWithSlot{{Item: upgradedItem, Slot: slot}}}, &proto.ItemUpgrade{
+				Item:      &proto.ItemSpecWithSlot{Item: upgradedItem, Slot: slot},
+				FromState: spec.UpgradeStep,
+				ToState:   state,
+			})
+		}
+	}
+	if len(challengeModeSet) > 0 {
+		addCombo(&equipmentSubstitution{Items: challengeModeSet}, &proto.ItemUpgrade{
+			ToState: proto.ItemLevelState_ChallengeMode,
+		})
+	}
+
+	iterations := u.Request.IterationsPerUpgrade
+	if iterations <= 0 {
+		iterations = defaultIterationsPerCombo
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	bulk := &bulkSimRunner{SingleRaidSimRunner: u.SingleRaidSimRunner}
+	rankedResults, baseResult, errorOutcome := bulk.getRankedResults(signals, bulkSimsToChan(combos, done), int32(len(combos)), iterations, progress)
+	if errorOutcome != nil {
+		return &proto.ItemUpgradeResult{Error: errorOutcome}
+	}
+
+	equippedDps, _ := baseResult.playerDps(defaultBulkConfidenceLevel)
+	result = &proto.ItemUpgradeResult{
+		EquippedDps: equippedDps,
+	}
+	for _, r := range rankedResults {
+		upgrade, ok := upgrades[r.Substitution]
+		if !ok {
+			continue
+		}
+		upgrade.Dps, upgrade.DpsInterval = r.playerDps(defaultBulkConfidenceLevel)
+		upgrade.DpsGain = upgrade.Dps.Avg - equippedDps.Avg
+		result.Upgrades = append(result.Upgrades, upgrade)
+	}
+	sort.SliceStable(result.Upgrades, func(i, j int) bool {
+		return result.Upgrades[i].DpsGain > result.Upgrades[j].DpsGain
+	})
+	return result
+}
+
+// The upgrade steps the item has stats for after the given one, only the next one unless all is set.
+func (item *Item) upgradeStatesAfter(current proto.ItemLevelState, all bool) []proto.ItemLevelState {
+	var states []proto.ItemLevelState
+	for _, state := range slices.Sorted(maps.Keys(item.ScalingOptions)) {
+		if state > int32(current) {
+			states = append(states, proto.ItemLevelState(state))
+		}
+	}
+	if !all && len(states) > 1 {
+		states = states[:1]
+	}
+	return states
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+const (
+	upgradeTestHelm    = 990101
+	upgradeTestTrinket = 990102
+)
+
+func TestItemUpgrades(t *testing.T) {
+	steps := func(numSteps int) map[int32]*proto.ScalingItemProperties {
+		options := map[int32]*proto.ScalingItemProperties{
+			int32(proto.ItemLevelState_ChallengeMode): {Ilvl: 463},
+		}
+		for step := 0; step <= numSteps; step++ {
+			options[int32(step)] = &proto.ScalingItemProperties{Ilvl: int32(496 + 4*step)}
+		}
+		return options
+	}
+	addToDatabase(&proto.SimDatabase{
+		Items: []*proto.SimItem{
+			{Id: upgradeTestHelm, Type: proto.ItemType_ItemTypeHead, ScalingOptions: steps(2)},
+			{Id: upgradeTestTrinket, Type: proto.ItemType_ItemTypeTrinket, ScalingOptions: steps(2)},
+		},
+	})
+
+	// Each upgrade step is worth 9 DPS on the helm and 20 DPS on the trinket, and Challenge Mode
+	// loses 50 DPS per item on top of its upgrades.
+	fakeRunSim := newFakeBulkRunSim(func(player *proto.Player) float64 {
+		dps := 1000.0
+		for _, item := range player.Equipment.Items {
+			weight := map[int32]float64{upgradeTestHelm: 9, upgradeTestTrinket: 20}[item.Id]
+			if item.ChallengeMode {
+				dps -= 50
+			} else {
+				dps += weight * float64(item.UpgradeStep)
+			}
+		}
+		return dps
+	}, 0, nil)
+
+	equipment := createEquipmentFromItems(
+		&itemWithSlot{Item: &proto.ItemSpec{Id: upgradeTestHelm}, Slot: proto.ItemSlot_ItemSlotHead},
+		&itemWithSlot{Item: &proto.ItemSpec{Id: upgradeTestTrinket, UpgradeStep: proto.ItemLevelState_UpgradeStepOne}, Slot: proto.ItemSlot_ItemSlotTrinket1},
+	)
+	runUpgrades := func(allSteps bool) *proto.ItemUpgradeResult {
+		runner := &itemUpgradeRunner{
+			SingleRaidSimRunner: fakeRunSim,
+			Request: &proto.ItemUpgradeRequest{
+				BaseSettings: &proto.RaidSimRequest{
+					Raid: SinglePlayerRaidProto(&proto.Player{
+						Name:      "Upgrader",
+						Class:     proto.Class_ClassShaman,
+						Spec:      &proto.Player_ElementalShaman{},
+						Equipment: equipment,
+					}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+					SimOptions: &proto.SimOptions{},
+				},
+				AllSteps:      allSteps,
+				ChallengeMode: true,
+			},
+		}
+		result := runner.Run(simsignals.CreateSignals(), nil)
+		if result.Error != nil {
+			t.Fatalf("Run() returned error: %s", result.Error.Message)
+		}
+		return result
+	}
+
+	type upgrade struct {
+		id       int32
+		from, to proto.ItemLevelState
+		gain     float64
+	}
+	for _, tc := range []struct {
+		allSteps bool
+		want     []upgrade
+	}{
+		{
+			allSteps: false,
+			want: []upgrade{
+				{upgradeTestTrinket, proto.ItemLevelState_UpgradeStepOne, proto.ItemLevelState_UpgradeStepTwo, 20},
+				{upgradeTestHelm, proto.ItemLevelState_Base, proto.ItemLevelState_UpgradeStepOne, 9},
+				// The whole set in Challenge Mode, which also drops the trinket's upgrade.
+				{0, proto.ItemLevelState_Base, proto.ItemLevelState_ChallengeMode, -120},
+			},
+		},
+		{
+			allSteps: true,
+			want: []upgrade{
+				{upgradeTestTrinket, proto.ItemLevelState_UpgradeStepOne, proto.ItemLevelState_UpgradeStepTwo, 20},
+				{upgradeTestHelm, proto.ItemLevelState_Base, proto.ItemLevelState_UpgradeStepTwo, 18},
+				{upgradeTestHelm, proto.ItemLevelState_Base, proto.ItemLevelState_UpgradeStepOne, 9},
+				{0, proto.ItemLevelState_Base, proto.ItemLevelState_ChallengeMode, -120},
+			},
+		},
+	} {
+		result := runUpgrades(tc.allSteps)
+		if result.EquippedDps.Avg != 1020 {
+			t.Errorf("allSteps=%t: equipped DPS = %f, want 1020", tc.allSteps, result.EquippedDps.Avg)
+		}
+		if len(result.Upgrades) != len(tc.want) {
+			t.Fatalf("allSteps=%t: got %d upgrades, want %d", tc.allSteps, len(result.Upgrades), len(tc.want))
+		}
+		for i, want := range tc.want {
+			got := result.Upgrades[i]
+			if got.Item.GetItem().GetId() != want.id || got.FromState != want.from || got.ToState != want.to || got.DpsGain != want.gain {
+				t.Errorf("allSteps=%t: upgrade #%d = (%d, %s -> %s, %f), want %v", tc.allSteps, i+1, got.Item.GetItem().GetId(), got.FromState, got.ToState, got.DpsGain, want)
+			}
+		}
+	}
+}
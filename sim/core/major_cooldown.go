@@ -126,7 +126,7 @@ func (mcd *MajorCooldown) tryActivateInternal(sim *Simulation, character *Charac
 }
 
 func (mcd *MajorCooldown) shouldActivateHelper(sim *Simulation, character *Character) bool {
-	if !mcd.Spell.CanCast(sim, character.CurrentTarget) {
+	if !mcd.Spell.CanCast(sim, character.CurrentTarget) || !character.hasNoticedCooldown(sim, mcd.Spell) {
 		return false
 	}
 
@@ -262,7 +262,7 @@ func (pet *Pet) Enable(sim *Simulation, petAgent PetAgent) {
 	if sim.Log != nil {
 		pet.Log(sim, "Pet stats: %s", pet.GetStats().FlatString())
 		pet.Log(sim, "Pet inherited stats: %s", pet.ApplyStatDependencies(pet.inheritedStats).FlatString())
-		pet.Log(sim, "Pet summoned")
+		pet.LogEvent(sim, &proto.CombatLogEvent{Type: proto.CombatLogEventType_CombatLogEventPetSummoned}, "Pet summoned")
 	}
 
 	sim.addTracker(&pet.auraTracker)
@@ -414,7 +414,7 @@ func (pet *Pet) Disable(sim *Simulation) {
 	sim.removeTracker(&pet.auraTracker)
 
 	if sim.Log != nil {
-		pet.Log(sim, "Pet dismissed")
+		pet.LogEvent(sim, &proto.CombatLogEvent{Type: proto.CombatLogEventType_CombatLogEventPetDismissed}, "Pet dismissed")
 		pet.Log(sim, pet.GetStats().FlatString())
 	}
 }
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Vector2 is a point on the ground, in yards.
+type Vector2 struct {
+	X float64
+	Y float64
+}
+
+func Vector2FromProto(v *proto.Vector2) Vector2 {
+	return Vector2{X: v.GetX(), Y: v.GetY()}
+}
+
+func (v Vector2) Add(other Vector2) Vector2 {
+	return Vector2{X: v.X + other.X, Y: v.Y + other.Y}
+}
+
+func (v Vector2) Sub(other Vector2) Vector2 {
+	return Vector2{X: v.X - other.X, Y: v.Y - other.Y}
+}
+
+func (v Vector2) Scale(factor float64) Vector2 {
+	return Vector2{X: v.X * factor, Y: v.Y * factor}
+}
+
+func (v Vector2) Length() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+func (v Vector2) DistanceTo(other Vector2) float64 {
+	return other.Sub(v).Length()
+}
+
+// A target walking along a path, one straight segment at a time.
+type pathMovement struct {
+	PendingAction
+	from      Vector2
+	to        Vector2
+	startTime time.Duration
+	remaining []Vector2
+}
+
+func (pm *pathMovement) positionAt(sim *Simulation) Vector2 {
+	duration := pm.NextActionAt - pm.startTime
+	if duration <= 0 {
+		return pm.to
+	}
+	progress := min(float64(sim.CurrentTime-pm.startTime)/float64(duration), 1)
+	return pm.from.Add(pm.to.Sub(pm.from).Scale(progress))
+}
+
+// GetPosition returns where the unit currently stands. Enemies stand where the encounter placed
+// them, while players and pets stand DistanceFromTarget away from their current target, in front
+// of it if InFrontOfTarget is set and behind it otherwise.
+func (unit *Unit) GetPosition(sim *Simulation) Vector2 {
+	if unit.Type != EnemyUnit && unit.CurrentTarget != nil && unit.CurrentTarget.Type == EnemyUnit {
+		distance := unit.DistanceFromTarget
+		if unit.Moving && unit.movementAction != nil {
+			distance = unit.movementAction.GetCurrentPosition(sim)
+		}
+		return unit.CurrentTarget.GetPosition(sim).Add(Vector2{X: TernaryFloat64(unit.PseudoStats.InFrontOfTarget, distance, -distance)})
+	}
+
+	if unit.pathMovement != nil {
+		return unit.pathMovement.positionAt(sim)
+	}
+	return unit.position
+}
+
+// DistanceTo returns the distance between the two units, in yards.
+func (unit *Unit) DistanceTo(sim *Simulation, other *Unit) float64 {
+	return unit.GetPosition(sim).DistanceTo(other.GetPosition(sim))
+}
+
+// SetPosition places the unit, stopping its current path. Players and pets always stand relative
+// to their target, so this is meant for enemies.
+func (unit *Unit) SetPosition(sim *Simulation, position Vector2) {
+	unit.stopPath(sim)
+	unit.position = position
+}
+
+// MoveAlongPath makes the unit walk to each point in order at its movement speed, starting from
+// wherever it is now. Players and pets attacking the unit move with it.
+func (unit *Unit) MoveAlongPath(sim *Simulation, path []Vector2) {
+	unit.stopPath(sim)
+	unit.walkTo(sim, path)
+}
+
+func (unit *Unit) walkTo(sim *Simulation, path []Vector2) {
+	if len(path) == 0 {
+		return
+	}
+
+	pm := &pathMovement{
+		from:      unit.position,
+		to:        path[0],
+		startTime: sim.CurrentTime,
+		remaining: path[1:],
+	}
+	pm.NextActionAt = sim.CurrentTime + DurationFromSeconds(pm.from.DistanceTo(pm.to)/unit.GetMovementSpeed())
+	pm.OnAction = func(sim *Simulation) {
+		unit.pathMovement = nil
+		unit.position = pm.to
+		unit.walkTo(sim, pm.remaining)
+	}
+	unit.pathMovement = pm
+	sim.AddPendingAction(&pm.PendingAction)
+}
+
+func (unit *Unit) stopPath(sim *Simulation) {
+	if unit.pathMovement == nil {
+		return
+	}
+	unit.position = unit.pathMovement.positionAt(sim)
+	unit.pathMovement.Cancel(sim)
+	unit.pathMovement = nil
+}
+
+// NumTargetsInRadius returns the number of active targets within radius yards of the point.
+func (encounter *Encounter) NumTargetsInRadius(sim *Simulation, center Vector2, radius float64) int32 {
+	numTargets := int32(0)
+	for _, target := range encounter.ActiveTargetUnits {
+		if target.GetPosition(sim).DistanceTo(center) <= radius {
+			numTargets++
+		}
+	}
+	return numTargets
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func expectPosition(t *testing.T, label string, actual Vector2, expected Vector2) {
+	t.Helper()
+	if actual.DistanceTo(expected) > 1e-6 {
+		t.Fatalf("Expected %s at (%0.2f, %0.2f), got (%0.2f, %0.2f)", label, expected.X, expected.Y, actual.X, actual.Y)
+	}
+}
+
+func TestTargetPath(t *testing.T) {
+	sim := setupTimelineSim(&proto.EncounterTimeline{
+		Phases: []*proto.TimelinePhase{
+			{
+				Events: []*proto.TimelineEvent{
+					{Event: &proto.TimelineEvent_EnableTargets{EnableTargets: &proto.TimelineTargets{TargetIndices: []int32{1}}}},
+					{At: 1, Event: &proto.TimelineEvent_TargetMove{TargetMove: &proto.TimelineTargetMove{
+						TargetIndex: 1,
+						Path:        []*proto.Vector2{{X: 0, Y: 0}, {X: 16, Y: 0}},
+					}}},
+				},
+			},
+		},
+	})
+
+	boss := sim.Encounter.AllTargetUnits[0]
+	add := sim.Encounter.AllTargetUnits[1]
+	player := sim.Raid.AllPlayerUnits[0]
+
+	// The add spawns 40 yards away from the boss.
+	add.StartPosition = Vector2{X: 0, Y: 40}
+	sim.Cleanup()
+	sim.Reset()
+	player.DistanceFromTarget = 20
+
+	stepTimelineSimUntil(sim, time.Millisecond*500)
+	expectPosition(t, "the player", player.GetPosition(sim), Vector2{X: -20, Y: 0})
+	if distance := player.DistanceTo(sim, add); distance < 44.72 || distance > 44.73 {
+		t.Fatalf("Expected the add 44.72 yards from the player, got %0.2f", distance)
+	}
+	if numTargets := sim.Encounter.NumTargetsInRadius(sim, boss.GetPosition(sim), 10); numTargets != 1 {
+		t.Fatalf("Expected 1 target near the boss before the add walks in, got %d", numTargets)
+	}
+
+	// Targets walk 8 yards per second, so the add reaches the boss 5 seconds after it starts walking
+	// at 1 second, and walks on for another 2 seconds.
+	expectedAddPosition := func() Vector2 {
+		walked := 8 * (sim.CurrentTime - time.Second).Seconds()
+		if walked <= 40 {
+			return Vector2{X: 0, Y: 40 - walked}
+		}
+		return Vector2{X: min(walked-40, 16), Y: 0}
+	}
+	stepTimelineSimUntil(sim, time.Millisecond*3500)
+	expectPosition(t, "the add", add.GetPosition(sim), expectedAddPosition())
+
+	// Players attacking the add move with it.
+	player.CurrentTarget = add
+	stepTimelineSimUntil(sim, time.Millisecond*6500)
+	expectPosition(t, "the add", add.GetPosition(sim), expectedAddPosition())
+	expectPosition(t, "the player", player.GetPosition(sim), expectedAddPosition().Sub(Vector2{X: 20}))
+	if numTargets := sim.Encounter.NumTargetsInRadius(sim, boss.GetPosition(sim), 20); numTargets != 2 {
+		t.Fatalf("Expected 2 targets near the boss, got %d", numTargets)
+	}
+
+	stepTimelineSimUntil(sim, time.Second*10)
+	expectPosition(t, "the add", add.GetPosition(sim), Vector2{X: 16, Y: 0})
+
+	// Positions are restored for the next iteration.
+	sim.Cleanup()
+	sim.Reset()
+	expectPosition(t, "the add", add.GetPosition(sim), Vector2{X: 0, Y: 40})
+}
+
+func TestSpellAreaTargets(t *testing.T) {
+	sim := setupTimelineSim(&proto.EncounterTimeline{
+		Phases: []*proto.TimelinePhase{
+			{
+				Events: []*proto.TimelineEvent{
+					{Event: &proto.TimelineEvent_EnableTargets{EnableTargets: &proto.TimelineTargets{TargetIndices: []int32{1}}}},
+				},
+			},
+		},
+	})
+	add := sim.Encounter.AllTargetUnits[1]
+	stepTimelineSimUntil(sim, time.Millisecond*100)
+	add.SetPosition(sim, Vector2{X: 12, Y: 0})
+
+	spell := &Spell{}
+	if numTargets := len(spell.AreaTargets(sim)); numTargets != 2 {
+		t.Fatalf("Expected a spell without a radius to hit 2 targets, got %d", numTargets)
+	}
+
+	spell.Radius = 8
+	if numTargets := len(spell.AreaTargets(sim)); numTargets != 1 {
+		t.Fatalf("Expected an area on the boss to hit 1 target, got %d", numTargets)
+	}
+
+	spell.AreaCenter = Vector2{X: 6, Y: 0}
+	if numTargets := len(spell.AreaTargets(sim)); numTargets != 2 {
+		t.Fatalf("Expected an area between the targets to hit 2 targets, got %d", numTargets)
+	}
+}
+
+func TestPositionAPLValues(t *testing.T) {
+	sim := setupTimelineSim(&proto.EncounterTimeline{
+		Phases: []*proto.TimelinePhase{
+			{
+				Events: []*proto.TimelineEvent{
+					{Event: &proto.TimelineEvent_EnableTargets{EnableTargets: &proto.TimelineTargets{TargetIndices: []int32{1}}}},
+				},
+			},
+		},
+	})
+	add := sim.Encounter.AllTargetUnits[1]
+	player := sim.Raid.AllPlayerUnits[0]
+	player.DistanceFromTarget = 5
+	stepTimelineSimUntil(sim, time.Millisecond*100)
+	add.SetPosition(sim, Vector2{X: 15, Y: 0})
+
+	rot := &APLRotation{unit: player}
+	addDistance := rot.newValueTargetDistance(&proto.APLValueTargetDistance{
+		TargetUnit: &proto.UnitReference{Type: proto.UnitReference_Target, Index: 1},
+	}, nil)
+	if distance := addDistance.GetFloat(sim); distance != 20 {
+		t.Fatalf("Expected the add 20 yards from the player, got %0.2f", distance)
+	}
+
+	targetsNearPlayer := rot.newValueTargetsInRadius(&proto.APLValueTargetsInRadius{Radius: 10}, nil)
+	if numTargets := targetsNearPlayer.GetInt(sim); numTargets != 1 {
+		t.Fatalf("Expected 1 target within 10 yards of the player, got %d", numTargets)
+	}
+
+	targetsNearAdd := rot.newValueTargetsInRadius(&proto.APLValueTargetsInRadius{
+		CenterUnit: &proto.UnitReference{Type: proto.UnitReference_Target, Index: 1},
+		Radius:     15,
+	}, nil)
+	if numTargets := targetsNearAdd.GetInt(sim); numTargets != 2 {
+		t.Fatalf("Expected 2 targets within 15 yards of the add, got %d", numTargets)
+	}
+}
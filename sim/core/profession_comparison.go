@@ -0,0 +1,418 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+const synapseSpringsEffectID = 4898
+
+// Jewelcrafting-only gems a player can have equipped at once.
+const jewelcraftingGemLimit = 2
+
+// professionComparison sims the player with each pair of professions, using the bulk sim combo runner.
+type professionComparison struct {
+	// Function used to run the sim of each pair.
+	SingleRaidSimRunner raidSimRunner
+	Request             *proto.ProfessionComparisonRequest
+}
+
+func runProfessionComparison(signals simsignals.Signals, request *proto.ProfessionComparisonRequest, progress chan *proto.ProgressMetrics) *proto.ProfessionComparisonResult {
+	comparison := &professionComparison{
+		SingleRaidSimRunner: runSim,
+		Request:             request,
+	}
+	return comparison.Run(signals, progress)
+}
+
+func (pc *professionComparison) Run(signals simsignals.Signals, progress chan *proto.ProgressMetrics) (result *proto.ProfessionComparisonResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.ProfessionComparisonResult{
+				Error: &proto.ErrorOutcome{
+					Message: fmt.Sprintf("%v\nStack Trace:\n%s", err, string(debug.Stack())),
+				},
+			}
+		}
+	}()
+
+	baseSettings, player, err := singlePlayerBaseSettings(pc.Request.GetBaseSettings())
+	if err != nil {
+		return &proto.ProfessionComparisonResult{
+			Error: &proto.ErrorOutcome{Message: "profession comparison: " + err.Error()},
+		}
+	}
+
+	professions := pc.Request.Professions
+	if len(professions) == 0 {
+		for value := range proto.Profession_name {
+			if profession := proto.Profession(value); profession != proto.Profession_ProfessionUnknown && profession != proto.Profession_Archeology {
+				professions = append(professions, profession)
+			}
+		}
+		slices.Sort(professions)
+	}
+	if len(professions) < 2 {
+		return &proto.ProfessionComparisonResult{
+			Error: &proto.ErrorOutcome{Message: "profession comparison: need at least 2 professions"},
+		}
+	}
+
+	perks := goproto.Clone(pc.Request.GetPerks()).(*proto.ProfessionPerks)
+	if perks == nil {
+		perks = &proto.ProfessionPerks{}
+	}
+	weights := pc.Request.StatWeights
+	perkWeights := func() (stats.Stats, error) {
+		if weights == nil {
+			var errorOutcome *proto.ErrorOutcome
+			if weights, errorOutcome = gearStatWeights(signals, baseSettings, player); errorOutcome != nil {
+				return stats.Stats{}, errors.New(errorOutcome.Message)
+			}
+		}
+		return stats.FromProtoArray(weights.Stats), nil
+	}
+	if err := defaultProfessionPerks(perks, player, professions, perkWeights); err != nil {
+		return &proto.ProfessionComparisonResult{
+			Error: &proto.ErrorOutcome{Message: "profession comparison: " + err.Error()},
+		}
+	}
+
+	pairs := make(map[*proto.RaidSimRequest]*proto.ProfessionPairResult)
+	var combos []singleBulkSim
+	for i, profession1 := range professions {
+		for _, profession2 := range professions[i+1:] {
+			request := goproto.Clone(baseSettings).(*proto.RaidSimRequest)
+			pairPlayer := request.Raid.Parties[0].Players[0]
+			isCurrent := (player.Profession1 == profession1 && player.Profession2 == profession2) ||
+				(player.Profession1 == profession2 && player.Profession2 == profession1)
+			pairPlayer.Profession1 = profession1
+			pairPlayer.Profession2 = profession2
+			applyProfessionPerks(pairPlayer, perks)
+
+			pairs[request] = &proto.ProfessionPairResult{
+				Profession1: profession1,
+				Profession2: profession2,
+				Equipment:   pairPlayer.Equipment,
+				Current:     isCurrent,
+			}
+			combos = append(combos, singleBulkSim{req: request, cl: &raidSimRequestChangeLog{}, eq: &equipmentSubstitution{}})
+		}
+	}
+
+	iterations := pc.Request.IterationsPerPair
+	if iterations <= 0 {
+		iterations = defaultIterationsPerCombo
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	bulk := &bulkSimRunner{SingleRaidSimRunner: pc.SingleRaidSimRunner}
+	rankedResults, _, errorOutcome := bulk.getRankedResults(signals, bulkSimsToChan(combos, done), int32(len(combos)), iterations, progress)
+	if errorOutcome != nil {
+		return &proto.ProfessionComparisonResult{Error: errorOutcome}
+	}
+
+	result = &proto.ProfessionComparisonResult{}
+	for _, r := range rankedResults {
+		pair := pairs[r.Request]
+		pair.Dps, pair.DpsInterval = r.playerDps(defaultBulkConfidenceLevel)
+		result.Results = append(result.Results, pair)
+	}
+	return result
+}
+
+// Fills in the perks of the compared professions which aren't set, with the equipped ones or else
+// the option in the database with the most EP. Weights are only fetched if an option is needed.
+func defaultProfessionPerks(perks *proto.ProfessionPerks, player *proto.Player, professions []proto.Profession, weights func() (stats.Stats, error)) error {
+	items := player.GetEquipment().GetItems()
+	itemIn := func(slot proto.ItemSlot) *proto.ItemSpec {
+		if int(slot) < len(items) && items[slot].GetId() != 0 {
+			return items[slot]
+		}
+		return nil
+	}
+	compared := func(profession proto.Profession) bool {
+		return slices.Contains(professions, profession)
+	}
+	// The candidate with the most EP, breaking ties by the lowest ID, or 0 if there are none.
+	bestOption := func(candidates map[int32]stats.Stats) (int32, error) {
+		if len(candidates) == 0 {
+			return 0, nil
+		}
+		w, err := weights()
+		if err != nil {
+			return 0, err
+		}
+		bestID, bestEP := int32(0), 0.0
+		for id, values := range candidates {
+			if ep := statsEP(w, values); bestID == 0 || ep > bestEP || (ep == bestEP && id < bestID) {
+				bestID, bestEP = id, ep
+			}
+		}
+		return bestID, nil
+	}
+	bestGem := func(fits func(gem Gem) bool) (int32, error) {
+		candidates := make(map[int32]stats.Stats)
+		for id, gem := range GemsByID {
+			if fits(gem) {
+				candidates[id] = gem.Stats
+			}
+		}
+		return bestOption(candidates)
+	}
+	isNormalGem := func(gem Gem) bool {
+		return gem.RequiredProfession == proto.Profession_ProfessionUnknown && gemFitsSocket(gem, proto.GemColor_GemColorPrismatic)
+	}
+	// The most common equipped gem without a profession requirement, or the best one.
+	normalGem := func() (int32, error) {
+		counts := make(map[int32]int)
+		mostCommon := int32(0)
+		for _, spec := range items {
+			for _, gemID := range spec.GetGems() {
+				if gem, ok := GemsByID[gemID]; ok && isNormalGem(gem) {
+					counts[gemID]++
+					if count := counts[gemID]; count > counts[mostCommon] || (count == counts[mostCommon] && gemID < mostCommon) {
+						mostCommon = gemID
+					}
+				}
+			}
+		}
+		if mostCommon != 0 {
+			return mostCommon, nil
+		}
+		return bestGem(isNormalGem)
+	}
+	missing := func(profession proto.Profession, perk string) error {
+		return fmt.Errorf("no %s for %s found, set one in the perks", perk, profession)
+	}
+
+	for _, enchantPerk := range []struct {
+		profession proto.Profession
+		perk       *int32
+		slots      []proto.ItemSlot
+	}{
+		{proto.Profession_Enchanting, &perks.EnchantingRingEnchant, []proto.ItemSlot{proto.ItemSlot_ItemSlotFinger1, proto.ItemSlot_ItemSlotFinger2}},
+		{proto.Profession_Leatherworking, &perks.LeatherworkingWristEnchant, []proto.ItemSlot{proto.ItemSlot_ItemSlotWrist}},
+		{proto.Profession_Tailoring, &perks.TailoringBackEnchant, []proto.ItemSlot{proto.ItemSlot_ItemSlotBack}},
+		{proto.Profession_Inscription, &perks.InscriptionShoulderEnchant, []proto.ItemSlot{proto.ItemSlot_ItemSlotShoulder}},
+	} {
+		if *enchantPerk.perk != 0 || !compared(enchantPerk.profession) {
+			continue
+		}
+		var item *Item
+		for _, slot := range enchantPerk.slots {
+			spec := itemIn(slot)
+			if spec == nil {
+				continue
+			}
+			if enchant, ok := EnchantsByEffectID[spec.Enchant]; ok && enchant.RequiredProfession == enchantPerk.profession {
+				*enchantPerk.perk = spec.Enchant
+				break
+			}
+			if equipped, ok := ItemsByID[spec.Id]; ok && item == nil {
+				item = &equipped
+			}
+		}
+		// Without an item in the slot, there's nothing to enchant.
+		if *enchantPerk.perk != 0 || item == nil {
+			continue
+		}
+		candidates := make(map[int32]stats.Stats)
+		for id, enchant := range EnchantsByEffectID {
+			if enchant.RequiredProfession == enchantPerk.profession && enchant.appliesToItem(item) {
+				candidates[id] = enchant.Stats
+			}
+		}
+		effectID, err := bestOption(candidates)
+		if err != nil {
+			return err
+		}
+		if effectID == 0 {
+			return missing(enchantPerk.profession, "enchant")
+		}
+		*enchantPerk.perk = effectID
+	}
+
+	if perks.EngineeringTinker == 0 && compared(proto.Profession_Engineering) {
+		perks.EngineeringTinker = synapseSpringsEffectID
+		if spec := itemIn(proto.ItemSlot_ItemSlotHands); spec != nil {
+			if tinker, ok := EnchantsByEffectID[spec.Tinker]; ok && tinker.RequiredProfession == proto.Profession_Engineering {
+				perks.EngineeringTinker = spec.Tinker
+			}
+		}
+	}
+
+	if perks.BlacksmithingGem == 0 && compared(proto.Profession_Blacksmithing) {
+		if playerHasProfession(player, proto.Profession_Blacksmithing) {
+			for _, slot := range []proto.ItemSlot{proto.ItemSlot_ItemSlotWrist, proto.ItemSlot_ItemSlotHands} {
+				spec := itemIn(slot)
+				if spec == nil {
+					continue
+				}
+				if numSockets := len(ItemsByID[spec.Id].GemSockets); len(spec.Gems) > numSockets && spec.Gems[numSockets] != 0 {
+					perks.BlacksmithingGem = spec.Gems[numSockets]
+					break
+				}
+			}
+		}
+		if perks.BlacksmithingGem == 0 {
+			gemID, err := normalGem()
+			if err != nil {
+				return err
+			}
+			if gemID == 0 {
+				return missing(proto.Profession_Blacksmithing, "gem")
+			}
+			perks.BlacksmithingGem = gemID
+		}
+	}
+
+	var equippedJewelcraftingGems []int32
+	for _, spec := range items {
+		for _, gemID := range spec.GetGems() {
+			if gem, ok := GemsByID[gemID]; ok && gem.RequiredProfession == proto.Profession_Jewelcrafting {
+				equippedJewelcraftingGems = append(equippedJewelcraftingGems, gemID)
+			}
+		}
+	}
+	if len(perks.JewelcraftingGems) == 0 && compared(proto.Profession_Jewelcrafting) {
+		perks.JewelcraftingGems = equippedJewelcraftingGems
+	}
+	if len(perks.JewelcraftingGems) == 0 && compared(proto.Profession_Jewelcrafting) {
+		gemID, err := bestGem(func(gem Gem) bool {
+			return gem.RequiredProfession == proto.Profession_Jewelcrafting
+		})
+		if err != nil {
+			return err
+		}
+		if gemID == 0 {
+			return missing(proto.Profession_Jewelcrafting, "gem")
+		}
+		for range jewelcraftingGemLimit {
+			perks.JewelcraftingGems = append(perks.JewelcraftingGems, gemID)
+		}
+	}
+	if perks.JewelcraftingReplacementGem == 0 && (len(equippedJewelcraftingGems) > 0 || compared(proto.Profession_Jewelcrafting)) {
+		gemID, err := normalGem()
+		if err != nil {
+			return err
+		}
+		if gemID == 0 {
+			return missing(proto.Profession_Jewelcrafting, "replacement gem")
+		}
+		perks.JewelcraftingReplacementGem = gemID
+	}
+	return nil
+}
+
+// Changes the player's gear to use the perks of its professions. Equipped perks are removed first,
+// so the gear of each pair only differs in the perks of its professions.
+func applyProfessionPerks(player *proto.Player, perks *proto.ProfessionPerks) {
+	items := player.GetEquipment().GetItems()
+	has := func(profession proto.Profession) bool {
+		return playerHasProfession(player, profession)
+	}
+	itemIn := func(slot proto.ItemSlot) *proto.ItemSpec {
+		if int(slot) < len(items) && items[slot].GetId() != 0 {
+			return items[slot]
+		}
+		return nil
+	}
+
+	for _, spec := range items {
+		item, ok := ItemsByID[spec.GetId()]
+		if !ok {
+			continue
+		}
+		if enchant, ok := EnchantsByEffectID[spec.Enchant]; ok && enchant.RequiredProfession != proto.Profession_ProfessionUnknown {
+			spec.Enchant = 0
+		}
+		if tinker, ok := EnchantsByEffectID[spec.Tinker]; ok && tinker.RequiredProfession != proto.Profession_ProfessionUnknown {
+			spec.Tinker = 0
+		}
+		for i, gemID := range spec.Gems {
+			if gem, ok := GemsByID[gemID]; ok && gem.RequiredProfession != proto.Profession_ProfessionUnknown {
+				spec.Gems[i] = Ternary(gem.RequiredProfession == proto.Profession_Jewelcrafting, perks.JewelcraftingReplacementGem, 0)
+			}
+		}
+		if len(spec.Gems) > len(item.GemSockets) && !item.hasExtraSocket(false) {
+			spec.Gems = spec.Gems[:len(item.GemSockets)]
+		}
+	}
+
+	setEnchant := func(profession proto.Profession, effectID int32, slots ...proto.ItemSlot) {
+		if effectID == 0 || !has(profession) {
+			return
+		}
+		for _, slot := range slots {
+			if spec := itemIn(slot); spec != nil {
+				spec.Enchant = effectID
+			}
+		}
+	}
+	setEnchant(proto.Profession_Enchanting, perks.EnchantingRingEnchant, proto.ItemSlot_ItemSlotFinger1, proto.ItemSlot_ItemSlotFinger2)
+	setEnchant(proto.Profession_Leatherworking, perks.LeatherworkingWristEnchant, proto.ItemSlot_ItemSlotWrist)
+	setEnchant(proto.Profession_Tailoring, perks.TailoringBackEnchant, proto.ItemSlot_ItemSlotBack)
+	setEnchant(proto.Profession_Inscription, perks.InscriptionShoulderEnchant, proto.ItemSlot_ItemSlotShoulder)
+
+	if spec := itemIn(proto.ItemSlot_ItemSlotHands); spec != nil && has(proto.Profession_Engineering) {
+		spec.Tinker = perks.EngineeringTinker
+	}
+
+	if has(proto.Profession_Blacksmithing) && perks.BlacksmithingGem != 0 {
+		for _, slot := range []proto.ItemSlot{proto.ItemSlot_ItemSlotWrist, proto.ItemSlot_ItemSlotHands} {
+			spec := itemIn(slot)
+			if spec == nil {
+				continue
+			}
+			numSockets := len(ItemsByID[spec.Id].GemSockets)
+			if len(spec.Gems) <= numSockets {
+				spec.Gems = append(spec.Gems, make([]int32, numSockets+1-len(spec.Gems))...)
+			}
+			spec.Gems[numSockets] = perks.BlacksmithingGem
+		}
+	}
+
+	if has(proto.Profession_Jewelcrafting) {
+		for _, gemID := range perks.JewelcraftingGems {
+			if gem, ok := GemsByID[gemID]; ok {
+				socketJewelcraftingGem(items, gem)
+			}
+		}
+	}
+}
+
+// Sockets the gem in place of the first other gem it fits, preferring sockets of a matching color.
+func socketJewelcraftingGem(items []*proto.ItemSpec, gem Gem) {
+	for _, matchColor := range []bool{true, false} {
+		for _, spec := range items {
+			item, ok := ItemsByID[spec.GetId()]
+			if !ok {
+				continue
+			}
+			for i, equippedID := range spec.Gems {
+				// Extra sockets accept any gem.
+				socketColor := proto.GemColor_GemColorPrismatic
+				if i < len(item.GemSockets) {
+					socketColor = item.GemSockets[i]
+				}
+				if equipped, ok := GemsByID[equippedID]; ok && equipped.RequiredProfession == proto.Profession_Jewelcrafting {
+					continue
+				}
+				if !gemFitsSocket(gem, socketColor) || (matchColor && !ColorIntersects(socketColor, gem.Color)) {
+					continue
+				}
+				spec.Gems[i] = gem.ID
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+const (
+	perksTestBracers   = 990301
+	perksTestGloves    = 990302
+	perksTestRing      = 990303
+	perksTestCloak     = 990304
+	perksTestRedGem    = 990311
+	perksTestJcGem     = 990312
+	perksTestRing1     = 990321
+	perksTestRing2     = 990322
+	perksTestFurLining = 990323
+	perksTestTinker    = 990324
+)
+
+func addProfessionPerksTestDatabase() {
+	addToDatabase(&proto.SimDatabase{
+		Items: []*proto.SimItem{
+			{Id: perksTestBracers, Type: proto.ItemType_ItemTypeWrist, GemSockets: []proto.GemColor{proto.GemColor_GemColorBlue}},
+			{Id: perksTestGloves, Type: proto.ItemType_ItemTypeHands, GemSockets: []proto.GemColor{proto.GemColor_GemColorRed}},
+			{Id: perksTestRing, Type: proto.ItemType_ItemTypeFinger},
+			{Id: perksTestCloak, Type: proto.ItemType_ItemTypeBack},
+		},
+		Gems: []*proto.SimGem{
+			{Id: perksTestRedGem, Color: proto.GemColor_GemColorRed},
+			{Id: perksTestJcGem, Color: proto.GemColor_GemColorRed, RequiredProfession: proto.Profession_Jewelcrafting},
+		},
+		Enchants: []*proto.SimEnchant{
+			{EffectId: perksTestRing1, Type: proto.ItemType_ItemTypeFinger},
+			{EffectId: perksTestRing2, Type: proto.ItemType_ItemTypeFinger, RequiredProfession: proto.Profession_Enchanting, Stats: stats.Stats{stats.Stamina: 10}.ToProtoArray()},
+			{EffectId: perksTestFurLining, Type: proto.ItemType_ItemTypeWrist, RequiredProfession: proto.Profession_Leatherworking},
+			{EffectId: perksTestTinker, Type: proto.ItemType_ItemTypeHands, RequiredProfession: proto.Profession_Engineering},
+		},
+	})
+}
+
+func newProfessionPerksTestPlayer() *proto.Player {
+	return &proto.Player{
+		Name:        "Crafter",
+		Class:       proto.Class_ClassShaman,
+		Spec:        &proto.Player_ElementalShaman{},
+		Profession1: proto.Profession_Leatherworking,
+		Profession2: proto.Profession_Jewelcrafting,
+		Equipment: createEquipmentFromItems(
+			&itemWithSlot{Item: &proto.ItemSpec{Id: perksTestBracers, Enchant: perksTestFurLining}, Slot: proto.ItemSlot_ItemSlotWrist},
+			&itemWithSlot{Item: &proto.ItemSpec{Id: perksTestGloves, Gems: []int32{perksTestJcGem}}, Slot: proto.ItemSlot_ItemSlotHands},
+			&itemWithSlot{Item: &proto.ItemSpec{Id: perksTestRing, Enchant: perksTestRing1}, Slot: proto.ItemSlot_ItemSlotFinger1},
+		),
+	}
+}
+
+func TestApplyProfessionPerks(t *testing.T) {
+	addProfessionPerksTestDatabase()
+	perks := &proto.ProfessionPerks{
+		BlacksmithingGem:            perksTestRedGem,
+		JewelcraftingGems:           []int32{perksTestJcGem},
+		JewelcraftingReplacementGem: perksTestRedGem,
+		EngineeringTinker:           perksTestTinker,
+		EnchantingRingEnchant:       perksTestRing2,
+		LeatherworkingWristEnchant:  perksTestFurLining,
+	}
+
+	for _, tc := range []struct {
+		professions [2]proto.Profession
+		wrist       *proto.ItemSpec
+		hands       *proto.ItemSpec
+		finger      *proto.ItemSpec
+	}{
+		{
+			professions: [2]proto.Profession{proto.Profession_Leatherworking, proto.Profession_Jewelcrafting},
+			wrist:       &proto.ItemSpec{Id: perksTestBracers, Enchant: perksTestFurLining},
+			hands:       &proto.ItemSpec{Id: perksTestGloves, Gems: []int32{perksTestJcGem}},
+			finger:      &proto.ItemSpec{Id: perksTestRing, Enchant: perksTestRing1},
+		},
+		{
+			professions: [2]proto.Profession{proto.Profession_Blacksmithing, proto.Profession_Engineering},
+			wrist:       &proto.ItemSpec{Id: perksTestBracers, Gems: []int32{0, perksTestRedGem}},
+			hands:       &proto.ItemSpec{Id: perksTestGloves, Gems: []int32{perksTestRedGem, perksTestRedGem}, Tinker: perksTestTinker},
+			finger:      &proto.ItemSpec{Id: perksTestRing, Enchant: perksTestRing1},
+		},
+		{
+			professions: [2]proto.Profession{proto.Profession_Enchanting, proto.Profession_Mining},
+			wrist:       &proto.ItemSpec{Id: perksTestBracers},
+			hands:       &proto.ItemSpec{Id: perksTestGloves, Gems: []int32{perksTestRedGem}},
+			finger:      &proto.ItemSpec{Id: perksTestRing, Enchant: perksTestRing2},
+		},
+	} {
+		player := newProfessionPerksTestPlayer()
+		player.Profession1, player.Profession2 = tc.professions[0], tc.professions[1]
+		applyProfessionPerks(player, perks)
+
+		items := player.Equipment.Items
+		for _, want := range []struct {
+			slot proto.ItemSlot
+			spec *proto.ItemSpec
+		}{
+			{proto.ItemSlot_ItemSlotWrist, tc.wrist},
+			{proto.ItemSlot_ItemSlotHands, tc.hands},
+			{proto.ItemSlot_ItemSlotFinger1, tc.finger},
+		} {
+			if !goproto.Equal(items[want.slot], want.spec) {
+				t.Errorf("%v: %s = %v, want %v", tc.professions, want.slot, items[want.slot], want.spec)
+			}
+		}
+	}
+}
+
+func TestDefaultProfessionPerks(t *testing.T) {
+	addProfessionPerksTestDatabase()
+	weights := func() (stats.Stats, error) {
+		return stats.Stats{stats.Stamina: 1}, nil
+	}
+
+	// Equipped perks are used for the current professions, and the best ones otherwise.
+	perks := &proto.ProfessionPerks{}
+	professions := []proto.Profession{proto.Profession_Jewelcrafting, proto.Profession_Leatherworking, proto.Profession_Enchanting, proto.Profession_Mining}
+	if err := defaultProfessionPerks(perks, newProfessionPerksTestPlayer(), professions, weights); err != nil {
+		t.Fatalf("defaultProfessionPerks() returned error: %v", err)
+	}
+	if perks.LeatherworkingWristEnchant != perksTestFurLining || perks.EnchantingRingEnchant != perksTestRing2 ||
+		len(perks.JewelcraftingGems) != 1 || perks.JewelcraftingGems[0] != perksTestJcGem || perks.JewelcraftingReplacementGem == 0 {
+		t.Errorf("defaultProfessionPerks() = %v, want the equipped fur lining and Jewelcrafting gem and the best ring enchant", perks)
+	}
+
+	// Tailoring has a perk but there's no embroidery to use.
+	player := newProfessionPerksTestPlayer()
+	player.Equipment.Items[proto.ItemSlot_ItemSlotBack] = &proto.ItemSpec{Id: perksTestCloak}
+	if err := defaultProfessionPerks(&proto.ProfessionPerks{}, player, []proto.Profession{proto.Profession_Tailoring, proto.Profession_Mining}, weights); err == nil {
+		t.Errorf("defaultProfessionPerks() returned no error without a Tailoring enchant")
+	}
+}
+
+func TestProfessionComparison(t *testing.T) {
+	addProfessionPerksTestDatabase()
+
+	// Only the ring enchant is worth any DPS.
+	fakeRunSim := newFakeBulkRunSim(func(player *proto.Player) float64 {
+		dps := 1000.0
+		if player.Equipment.Items[proto.ItemSlot_ItemSlotFinger1].Enchant == perksTestRing2 {
+			dps += 100
+		}
+		if player.Profession1 == proto.Profession_Mining || player.Profession2 == proto.Profession_Mining {
+			dps += 10
+		}
+		return dps
+	}, 0, nil)
+
+	comparison := &professionComparison{
+		SingleRaidSimRunner: fakeRunSim,
+		Request: &proto.ProfessionComparisonRequest{
+			BaseSettings: &proto.RaidSimRequest{
+				Raid:       SinglePlayerRaidProto(newProfessionPerksTestPlayer(), &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+				SimOptions: &proto.SimOptions{},
+			},
+			Professions: []proto.Profession{proto.Profession_Jewelcrafting, proto.Profession_Leatherworking, proto.Profession_Enchanting, proto.Profession_Mining},
+			Perks:       &proto.ProfessionPerks{EnchantingRingEnchant: perksTestRing2},
+			StatWeights: &proto.UnitStats{Stats: stats.Stats{stats.Stamina: 1}.ToProtoArray()},
+		},
+	}
+	result := comparison.Run(simsignals.CreateSignals(), nil)
+	if result.Error != nil {
+		t.Fatalf("Run() returned error: %s", result.Error.Message)
+	}
+
+	if len(result.Results) != 6 {
+		t.Fatalf("got %d pairs, want 6", len(result.Results))
+	}
+	best := result.Results[0]
+	if best.Profession1 != proto.Profession_Enchanting || best.Profession2 != proto.Profession_Mining || best.Dps.Avg != 1110 {
+		t.Errorf("best pair = %s/%s with %f DPS, want Enchanting/Mining with 1110 DPS", best.Profession1, best.Profession2, best.Dps.Avg)
+	}
+	numCurrent := 0
+	for _, pair := range result.Results {
+		if pair.Current {
+			numCurrent++
+			if pair.Profession1 != proto.Profession_Jewelcrafting || pair.Profession2 != proto.Profession_Leatherworking {
+				t.Errorf("current pair = %s/%s, want Jewelcrafting/Leatherworking", pair.Profession1, pair.Profession2)
+			}
+		}
+	}
+	if numCurrent != 1 {
+		t.Errorf("got %d current pairs, want 1", numCurrent)
+	}
+}
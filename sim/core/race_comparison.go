@@ -0,0 +1,143 @@
+package core
+
+import (
+	"fmt"
+	"runtime/debug"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// raceComparison sims the player as each race, using the bulk sim combo runner.
+type raceComparison struct {
+	// Function used to run the sim of each race.
+	SingleRaidSimRunner raidSimRunner
+	Request             *proto.RaceComparisonRequest
+}
+
+func runRaceComparison(signals simsignals.Signals, request *proto.RaceComparisonRequest, progress chan *proto.ProgressMetrics) *proto.RaceComparisonResult {
+	comparison := &raceComparison{
+		SingleRaidSimRunner: runSim,
+		Request:             request,
+	}
+	return comparison.Run(signals, progress)
+}
+
+func (rc *raceComparison) Run(signals simsignals.Signals, progress chan *proto.ProgressMetrics) (result *proto.RaceComparisonResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.RaceComparisonResult{
+				Error: &proto.ErrorOutcome{
+					Message: fmt.Sprintf("%v\nStack Trace:\n%s", err, string(debug.Stack())),
+				},
+			}
+		}
+	}()
+
+	baseSettings, player, err := singlePlayerBaseSettings(rc.Request.GetBaseSettings())
+	if err != nil {
+		return &proto.RaceComparisonResult{
+			Error: &proto.ErrorOutcome{Message: "race comparison: " + err.Error()},
+		}
+	}
+
+	classRaces := racesForClass(player.Class)
+	races := rc.Request.Races
+	if len(races) == 0 {
+		races = classRaces
+	}
+	for _, race := range races {
+		if !slices.Contains(classRaces, race) {
+			return &proto.RaceComparisonResult{
+				Error: &proto.ErrorOutcome{
+					Message: fmt.Sprintf("race comparison: %s can't be %s", race, player.Class),
+				},
+			}
+		}
+	}
+
+	weights := rc.Request.StatWeights
+	if weights == nil && !rc.Request.KeepReforges {
+		var errorOutcome *proto.ErrorOutcome
+		weights, errorOutcome = gearStatWeights(signals, baseSettings, player)
+		if errorOutcome != nil {
+			return &proto.RaceComparisonResult{Error: errorOutcome}
+		}
+	}
+
+	raceResults := make(map[*proto.RaidSimRequest]*proto.RaceResult)
+	var combos []singleBulkSim
+	for _, race := range races {
+		request := goproto.Clone(baseSettings).(*proto.RaidSimRequest)
+		racePlayer := request.Raid.Parties[0].Players[0]
+		racePlayer.Race = race
+		if !rc.Request.KeepReforges {
+			equipment, err := reforgeToCaps(request, racePlayer, stats.FromProtoArray(weights.Stats))
+			if err != nil {
+				return &proto.RaceComparisonResult{
+					Error: &proto.ErrorOutcome{Message: fmt.Sprintf("race comparison: %s: %s", race, err)},
+				}
+			}
+			racePlayer.Equipment = equipment
+		}
+
+		raceResults[request] = &proto.RaceResult{
+			Race:      race,
+			Equipment: racePlayer.Equipment,
+			Current:   race == player.Race,
+		}
+		combos = append(combos, singleBulkSim{req: request, cl: &raidSimRequestChangeLog{}, eq: &equipmentSubstitution{}})
+	}
+
+	iterations := rc.Request.IterationsPerRace
+	if iterations <= 0 {
+		iterations = defaultIterationsPerCombo
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	bulk := &bulkSimRunner{SingleRaidSimRunner: rc.SingleRaidSimRunner}
+	rankedResults, _, errorOutcome := bulk.getRankedResults(signals, bulkSimsToChan(combos, done), int32(len(combos)), iterations, progress)
+	if errorOutcome != nil {
+		return &proto.RaceComparisonResult{Error: errorOutcome}
+	}
+
+	result = &proto.RaceComparisonResult{StatWeights: weights}
+	for _, r := range rankedResults {
+		raceResult := raceResults[r.Request]
+		raceResult.Dps, raceResult.DpsInterval = r.playerDps(defaultBulkConfidenceLevel)
+		result.Results = append(result.Results, raceResult)
+	}
+	return result
+}
+
+// The races with base stats for the class, in enum order.
+func racesForClass(class proto.Class) []proto.Race {
+	var races []proto.Race
+	for key := range BaseStats {
+		if key.Class == class {
+			races = append(races, key.Race)
+		}
+	}
+	slices.Sort(races)
+	return races
+}
+
+// Changes only the reforges of the player's gear to best fit its hit and expertise caps, and returns
+// the new equipment. The request must be reduced to the single player.
+func reforgeToCaps(request *proto.RaidSimRequest, player *proto.Player, weights stats.Stats) (*proto.EquipmentSpec, error) {
+	caps, err := gearCaps(request, player, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	search, err := newGearSearch(player, &proto.GearOptimizerRequest{OptimizeReforges: true}, weights, caps)
+	if err != nil {
+		return nil, err
+	}
+	// Without gems to choose from, the search only moves reforges.
+	search.gems = nil
+	return search.equipmentSpec(&search.run(1)[0]), nil
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func TestRacesForClass(t *testing.T) {
+	want := []proto.Race{
+		proto.Race_RaceDwarf,
+		proto.Race_RaceOrc,
+		proto.Race_RaceTauren,
+		proto.Race_RaceTroll,
+		proto.Race_RaceDraenei,
+		proto.Race_RaceGoblin,
+		proto.Race_RaceAlliancePandaren,
+		proto.Race_RaceHordePandaren,
+	}
+	slices.Sort(want)
+	if got := racesForClass(proto.Class_ClassShaman); !slices.Equal(got, want) {
+		t.Errorf("racesForClass(Shaman) = %v, want %v", got, want)
+	}
+}
+
+func TestRaceComparison(t *testing.T) {
+	addOptimizerTestDatabase()
+
+	// Trolls do the most DPS, and reforging doesn't matter.
+	fakeRunSim := newFakeBulkRunSim(func(player *proto.Player) float64 {
+		return Ternary(player.Race == proto.Race_RaceTroll, 1100.0, 1000.0)
+	}, 0, nil)
+
+	// Just under the spell hit cap without the chest, so its hit reforge is mostly wasted.
+	const hitRing = 990401
+	addToDatabase(&proto.SimDatabase{
+		Items: []*proto.SimItem{{
+			Id:   hitRing,
+			Type: proto.ItemType_ItemTypeFinger,
+			ScalingOptions: map[int32]*proto.ScalingItemProperties{
+				int32(proto.ItemLevelState_Base): {Stats: map[int32]float64{int32(stats.HitRating): 5000}},
+			},
+		}},
+	})
+	player := newOptimizerTestPlayer()
+	player.Race = proto.Race_RaceOrc
+	player.Class = proto.Class_ClassShaman
+	player.Spec = &proto.Player_ElementalShaman{}
+	player.Equipment.Items[proto.ItemSlot_ItemSlotFinger1] = &proto.ItemSpec{Id: hitRing}
+	player.Equipment.Items[proto.ItemSlot_ItemSlotChest].Reforging = optimizerCritToHit
+
+	for _, keepReforges := range []bool{false, true} {
+		comparison := &raceComparison{
+			SingleRaidSimRunner: fakeRunSim,
+			Request: &proto.RaceComparisonRequest{
+				BaseSettings: &proto.RaidSimRequest{
+					Raid:       SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+					Encounter:  &proto.Encounter{Duration: 60, Targets: []*proto.Target{{Level: 93}}},
+					SimOptions: &proto.SimOptions{},
+				},
+				Races:        []proto.Race{proto.Race_RaceOrc, proto.Race_RaceTroll, proto.Race_RaceGoblin},
+				KeepReforges: keepReforges,
+				StatWeights:  &proto.UnitStats{Stats: newOptimizerTestWeights(2, 0, 1, 0).ToProtoArray()},
+			},
+		}
+		result := comparison.Run(simsignals.CreateSignals(), nil)
+		if result.Error != nil {
+			t.Fatalf("Run() returned error: %s", result.Error.Message)
+		}
+
+		if len(result.Results) != 3 {
+			t.Fatalf("got %d races, want 3", len(result.Results))
+		}
+		if best := result.Results[0]; best.Race != proto.Race_RaceTroll || best.Dps.Avg != 1100 {
+			t.Errorf("best race = %s with %f DPS, want Troll with 1100 DPS", best.Race, best.Dps.Avg)
+		}
+		wantReforge := int32(Ternary(keepReforges, optimizerCritToHit, optimizerCritToHst))
+		for _, race := range result.Results {
+			if got := race.Equipment.Items[proto.ItemSlot_ItemSlotChest].Reforging; got != wantReforge {
+				t.Errorf("keepReforges %t: %s chest reforge = %d, want %d", keepReforges, race.Race, got, wantReforge)
+			}
+			if race.Current != (race.Race == proto.Race_RaceOrc) {
+				t.Errorf("%s current = %t", race.Race, race.Current)
+			}
+		}
+	}
+
+	comparison := &raceComparison{
+		SingleRaidSimRunner: fakeRunSim,
+		Request: &proto.RaceComparisonRequest{
+			BaseSettings: &proto.RaidSimRequest{
+				Raid: SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+			},
+			Races:        []proto.Race{proto.Race_RaceHuman},
+			KeepReforges: true,
+		},
+	}
+	if result := comparison.Run(simsignals.CreateSignals(), nil); result.Error == nil {
+		t.Errorf("Run() with a race the class can't be didn't return an error")
+	}
+}
@@ -217,7 +217,7 @@ func (rp *runicPowerBar) addRunicPowerInternal(sim *Simulation, amount float64,
 	}
 
 	if sim.Log != nil {
-		rp.character.Log(sim, "Gained %0.3f runic power from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, rp.currentRunicPower, newRunicPower, rp.maxRunicPower)
+		rp.character.logResourceChange(sim, metrics.ActionID, proto.ResourceType_ResourceTypeRunicPower, amount, rp.currentRunicPower, newRunicPower, "Gained %0.3f runic power from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, rp.currentRunicPower, newRunicPower, rp.maxRunicPower)
 	}
 
 	rp.currentRunicPower = newRunicPower
@@ -251,7 +251,7 @@ func (rp *runicPowerBar) spendRunicPower(sim *Simulation, amount float64, metric
 	}
 
 	if sim.Log != nil {
-		rp.character.Log(sim, "Spent %0.3f runic power from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, rp.currentRunicPower, newRunicPower, rp.maxRunicPower)
+		rp.character.logResourceChange(sim, metrics.ActionID, proto.ResourceType_ResourceTypeRunicPower, -amount, rp.currentRunicPower, newRunicPower, "Spent %0.3f runic power from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, rp.currentRunicPower, newRunicPower, rp.maxRunicPower)
 	}
 
 	rp.currentRunicPower = newRunicPower
@@ -614,7 +614,7 @@ func (rp *runicPowerBar) gainRuneMetrics(sim *Simulation, metrics *ResourceMetri
 
 	if sim.Log != nil {
 		name, currRunes := rp.typeAmount(metrics)
-		rp.character.Log(sim, "Gained %0.3f %s rune from %s (%d --> %d).", float64(gainAmount), name, metrics.ActionID, currRunes-gainAmount, currRunes)
+		rp.character.logResourceChange(sim, metrics.ActionID, metrics.Type, float64(gainAmount), float64(currRunes-gainAmount), float64(currRunes), "Gained %0.3f %s rune from %s (%d --> %d).", float64(gainAmount), name, metrics.ActionID, currRunes-gainAmount, currRunes)
 	}
 }
 
@@ -624,7 +624,7 @@ func (rp *runicPowerBar) spendRuneMetrics(sim *Simulation, metrics *ResourceMetr
 
 	if sim.Log != nil {
 		name, currRunes := rp.typeAmount(metrics)
-		rp.character.Log(sim, "Spent 1.000 %s rune from %s (%d --> %d).", name, metrics.ActionID, currRunes+spendAmount, currRunes)
+		rp.character.logResourceChange(sim, metrics.ActionID, metrics.Type, -float64(spendAmount), float64(currRunes+spendAmount), float64(currRunes), "Spent 1.000 %s rune from %s (%d --> %d).", name, metrics.ActionID, currRunes+spendAmount, currRunes)
 	}
 }
 
@@ -9,7 +9,6 @@ import (
 	"runtime/debug"
 	"slices"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -45,6 +44,9 @@ type Simulation struct {
 
 	Log func(string, ...interface{})
 
+	// Structured log backing Log, only set while debug logging.
+	combatLog *CombatLog
+
 	executePhase int32 // 20, 25, 35, 45 or 90 for the respective execute range, 100 otherwise
 
 	executePhaseCallbacks []func(*Simulation, int32) // 2nd parameter is 90 for 90%, 45 for 45%, 35 for 35%, 25 for 25% and 20 for 20%
@@ -295,10 +297,11 @@ func (sim *Simulation) Reseed(seed int64) {
 func (sim *Simulation) run() *proto.RaidSimResult {
 	t0 := time.Now()
 
-	logsBuffer := &strings.Builder{}
+	combatLog := &CombatLog{}
 	if sim.Options.Debug || sim.Options.DebugFirstIteration {
+		sim.combatLog = combatLog
 		sim.Log = func(message string, vals ...interface{}) {
-			logsBuffer.WriteString(fmt.Sprintf("[%0.2f] "+message+"\n", append([]interface{}{sim.CurrentTime.Seconds()}, vals...)...))
+			sim.LogEvent(&proto.CombatLogEvent{}, message, vals...)
 		}
 	}
 
@@ -317,6 +320,7 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 
 	if !sim.Options.Debug {
 		sim.Log = nil
+		sim.combatLog = nil
 	}
 
 	var st time.Time
@@ -355,11 +359,14 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 		RaidMetrics:      sim.Raid.GetMetrics(),
 		EncounterMetrics: sim.Encounter.GetMetricsProto(),
 
-		Logs:                   logsBuffer.String(),
+		Logs:                   combatLog.String(),
 		FirstIterationDuration: firstIterationDuration.Seconds(),
 		AvgIterationDuration:   totalDuration.Seconds() / float64(sim.Options.Iterations),
 		IterationsDone:         sim.Options.Iterations,
 	}
+	if sim.Options.CombatLog {
+		result.CombatLog = combatLog.Events
+	}
 
 	// Final progress report
 	if sim.ProgressReport != nil {
@@ -676,6 +683,19 @@ func (sim *Simulation) GetConsumedPendingActionFromPool() *PendingAction {
 func (sim *Simulation) RegisterExecutePhaseCallback(callback func(sim *Simulation, isExecute int32)) {
 	sim.executePhaseCallbacks = append(sim.executePhaseCallbacks, callback)
 }
+
+// ForceExecutePhase moves the fight into the given execute phase (90, 45, 35, 25 or 20), e.g. when
+// an encounter script decides the boss health has dropped. It has no effect on later phases.
+func (sim *Simulation) ForceExecutePhase(phase int32) {
+	phase = max(phase, 20)
+	for sim.executePhase > phase {
+		sim.nextExecutePhase()
+		for _, callback := range sim.executePhaseCallbacks {
+			callback(sim, sim.executePhase)
+		}
+	}
+}
+
 func (sim *Simulation) IsExecutePhase20() bool {
 	return sim.executePhase <= 20
 }
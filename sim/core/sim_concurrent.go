@@ -236,6 +236,25 @@ func (rsrc *raidSimResultCombiner) addResourceMetrics(unit *proto.UnitMetrics, a
 	rm.ActualGain += add.ActualGain
 }
 
+func (rsrc *raidSimResultCombiner) addAPLEntryMetrics(unit *proto.UnitMetrics, add *proto.APLEntryMetrics) {
+	var em *proto.APLEntryMetrics
+	for _, baseEntry := range unit.AplEntries {
+		if baseEntry.Index == add.Index {
+			em = baseEntry
+			break
+		}
+	}
+
+	if em == nil {
+		em = &proto.APLEntryMetrics{Index: add.Index}
+		unit.AplEntries = append(unit.AplEntries, em)
+	}
+
+	em.Evaluated += add.Evaluated
+	em.ConditionTrue += add.ConditionTrue
+	em.Executed += add.Executed
+}
+
 func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, add *proto.UnitMetrics, isLast bool, weight float64) {
 	rsrc.combineDistMetrics(base.Dps, add.Dps, isLast, weight)
 	rsrc.combineDistMetrics(base.Threat, add.Threat, isLast, weight)
@@ -259,6 +278,10 @@ func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, a
 		rsrc.addResourceMetrics(base, addResource)
 	}
 
+	for _, addEntry := range add.AplEntries {
+		rsrc.addAPLEntryMetrics(base, addEntry)
+	}
+
 	for i, addPet := range add.Pets {
 		rsrc.combineUnitMetrics(base.Pets[i], addPet, isLast, weight)
 	}
@@ -286,6 +309,7 @@ func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast
 
 	if rsrc.Debug {
 		rsrc.Combined.Logs += "-SIMSTART-\n" + result.Logs
+		rsrc.Combined.CombatLog = append(rsrc.Combined.CombatLog, result.CombatLog...)
 	}
 }
 
@@ -304,6 +328,7 @@ func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
 
 	if !rsrc.Debug {
 		newRsr.Logs = baseRsr.Logs
+		newRsr.CombatLog = baseRsr.CombatLog
 	}
 
 	for i, party := range baseRsr.RaidMetrics.Parties {
@@ -400,6 +425,19 @@ func (csd *concurrentSimData) MakeProgressMetrics() *proto.ProgressMetrics {
 	}
 }
 
+// RaidSimFunc runs a single raid sim, sending its progress and final result to the progress
+// channel and closing it when done.
+type RaidSimFunc func(*proto.RaidSimRequest, chan *proto.ProgressMetrics, simsignals.Signals) *proto.RaidSimResult
+
+// Returns the function which runs raid sims in this process, on all threads except in wasm.
+func LocalRaidSimFunc() RaidSimFunc {
+	// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
+	if IsRunningInWasm() {
+		return RunSim
+	}
+	return runSimConcurrent
+}
+
 // Run sim on multiple threads concurrently by splitting interations over multiple sims, transparently combining results into the progress channel.
 func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) (result *proto.RaidSimResult) {
 	defer func() {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/stats"
 )
 
@@ -32,12 +33,16 @@ type SpellConfig struct {
 	Cast               CastConfig
 	ExtraCastCondition CanCastCondition
 
-	// Optional range constraints. If supplied, these are used to modify the ExtraCastCondition above to additionally check for DistanceFromTarget.
+	// Optional range constraints. If supplied, these are used to modify the ExtraCastCondition above to additionally check the distance to the target.
 	MinRange     float64
 	MaxRange     float64
 	Charges      int // The maximum amount of charges this spell can have
 	RechargeTime time.Duration
 
+	// Radius in yards of the area hit by AoE damage, around the target of the cast or around the caster
+	// with SpellFlagAreaAroundCaster. If 0, AoE damage hits every active target.
+	Radius float64
+
 	BonusHitPercent      float64
 	BonusCritPercent     float64
 	BonusSpellPower      float64
@@ -104,13 +109,17 @@ type Spell struct {
 	SharedCD           Cooldown
 	ExtraCastCondition CanCastCondition
 
-	// Optional range constraints. If supplied, these are used to modify the ExtraCastCondition above to additionally check for DistanceFromTarget.
+	// Optional range constraints. If supplied, these are used to modify the ExtraCastCondition above to additionally check the distance to the target.
 	MinRange     float64
 	MaxRange     float64
 	MaxCharges   int // Maximum amount of charges the spell can have
 	charges      int // Current amount of charges the spell has
 	RechargeTime time.Duration
 
+	Radius      float64
+	AreaCenter  Vector2 // Where the area of the most recent cast was placed, if Radius is set.
+	areaTargets []*Unit
+
 	rechargeTimer *PendingAction // used for the recharge timer
 
 	castTimeFn func(spell *Spell) time.Duration // allows to override CastTime()
@@ -258,6 +267,7 @@ func (unit *Unit) RegisterSpell(config SpellConfig) *Spell {
 		charges:      config.Charges,
 		MaxCharges:   config.Charges,
 		RechargeTime: config.RechargeTime,
+		Radius:       config.Radius,
 
 		resultCache: make(SpellResultCache, 1),
 		resultSlice: make(SpellResultSlice, 0, 1),
@@ -337,7 +347,11 @@ func (unit *Unit) RegisterSpell(config SpellConfig) *Spell {
 		spell.MaxRange = config.MaxRange
 		oldExtraCastCondition := spell.ExtraCastCondition
 		spell.ExtraCastCondition = func(sim *Simulation, target *Unit) bool {
-			if ((spell.MinRange != 0) && (spell.Unit.DistanceFromTarget < spell.MinRange)) || ((spell.MaxRange != 0) && (spell.Unit.DistanceFromTarget > spell.MaxRange)) {
+			distance := spell.Unit.DistanceFromTarget
+			if target != nil && target.Type == EnemyUnit && target != spell.Unit.CurrentTarget {
+				distance = spell.Unit.DistanceTo(sim, target)
+			}
+			if ((spell.MinRange != 0) && (distance < spell.MinRange)) || ((spell.MaxRange != 0) && (distance > spell.MaxRange)) {
 				/*if sim.Log != nil {
 					sim.Log("Cannot cast spell %s, out of range!", spell.ActionID)
 				}*/
@@ -673,9 +687,12 @@ func (spell *Spell) CastOnAllOtherTargets(sim *Simulation, mainTarget *Unit) {
 // Skips the actual cast and applies spell effects immediately.
 func (spell *Spell) SkipCastAndApplyEffects(sim *Simulation, target *Unit) {
 	if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-		spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s)",
-			spell.ActionID, spell.DefaultCast.Cost, time.Duration(0))
-		spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
+		spell.Unit.LogEvent(sim, &proto.CombatLogEvent{
+			Type:     proto.CombatLogEventType_CombatLogEventCastStart,
+			ActionId: spell.ActionID.ToProto(),
+			Cost:     spell.DefaultCast.Cost,
+		}, "Casting %s (Cost = %0.03f, Cast Time = %s)", spell.ActionID, spell.DefaultCast.Cost, time.Duration(0))
+		spell.logCastFinish(sim)
 	}
 	spell.applyEffects(sim, target)
 }
@@ -691,9 +708,33 @@ func (spell *Spell) applyEffects(sim *Simulation, target *Unit) {
 		spell.Unit.OnApplyEffects(sim, target, spell)
 	}
 
+	if spell.Radius > 0 {
+		if spell.Flags.Matches(SpellFlagAreaAroundCaster) {
+			spell.AreaCenter = spell.Unit.GetPosition(sim)
+		} else {
+			spell.AreaCenter = target.GetPosition(sim)
+		}
+	}
+
 	spell.ApplyEffects(sim, target, spell)
 }
 
+// AreaTargets returns the active targets hit by AoE damage of the spell, which are all of them
+// unless the spell has a Radius.
+func (spell *Spell) AreaTargets(sim *Simulation) []*Unit {
+	if spell.Radius == 0 {
+		return sim.Encounter.ActiveTargetUnits
+	}
+
+	spell.areaTargets = spell.areaTargets[:0]
+	for _, target := range sim.Encounter.ActiveTargetUnits {
+		if target.GetPosition(sim).DistanceTo(spell.AreaCenter) <= spell.Radius {
+			spell.areaTargets = append(spell.areaTargets, target)
+		}
+	}
+	return spell.areaTargets
+}
+
 func (spell *Spell) ApplyAOEThreatIgnoreMultipliers(threatAmount float64) {
 	for _, target := range spell.Unit.Env.GetActiveTargetUnits() {
 		spell.SpellMetrics[target.UnitIndex].TotalThreat += threatAmount
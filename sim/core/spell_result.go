@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/stats"
 )
 
@@ -106,6 +107,18 @@ func (result *SpellResult) HealingString() string {
 	return fmt.Sprintf("%s for %0.3f healing", result.Outcome.String(), result.Damage)
 }
 
+func (result *SpellResult) combatLogEvent(spell *Spell, eventType proto.CombatLogEventType, isPeriodic bool) *proto.CombatLogEvent {
+	return &proto.CombatLogEvent{
+		Type:       eventType,
+		Target:     result.Target.Label,
+		ActionId:   spell.ActionID.ToProto(),
+		Outcome:    result.Outcome.String(),
+		Amount:     result.Damage,
+		IsPeriodic: isPeriodic,
+		Threat:     result.Threat,
+	}
+}
+
 func (spell *Spell) ThreatFromDamage(sim *Simulation, outcome HitOutcome, damage float64, attackTable *AttackTable) float64 {
 	if outcome.Matches(OutcomeLanded) {
 		threat := (damage*spell.ThreatMultiplier + spell.FlatThreatBonus) * spell.Unit.PseudoStats.ThreatMultiplier
@@ -346,10 +359,11 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 	}
 
 	if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		event := result.combatLogEvent(spell, proto.CombatLogEventType_CombatLogEventDamage, isPeriodic)
 		if isPeriodic {
-			spell.Unit.Log(sim, "%s %s tick %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
+			spell.Unit.LogEvent(sim, event, "%s %s tick %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
 		} else {
-			spell.Unit.Log(sim, "%s %s %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
+			spell.Unit.LogEvent(sim, event, "%s %s %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
 		}
 	}
 
@@ -390,7 +404,7 @@ func fixedBaseDamageFactory(baseDamage float64) BaseDamageCalculator {
 func (spell *Spell) aoeIteration(sim *Simulation, outcomeApplier OutcomeApplier, baseDamageCalculator BaseDamageCalculator, singleResultCalculator SpellResultIteration) SpellResultSlice {
 	spell.resultSlice = spell.resultSlice[:0]
 
-	for _, aoeTarget := range sim.Encounter.ActiveTargetUnits {
+	for _, aoeTarget := range spell.AreaTargets(sim) {
 		baseDamage := baseDamageCalculator(sim, spell)
 		spell.resultSlice = append(spell.resultSlice, singleResultCalculator(sim, aoeTarget, baseDamage, outcomeApplier))
 	}
@@ -564,10 +578,11 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 	}
 
 	if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		event := result.combatLogEvent(spell, proto.CombatLogEventType_CombatLogEventHeal, isPeriodic)
 		if isPeriodic {
-			spell.Unit.Log(sim, "%s %s tick %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
+			spell.Unit.LogEvent(sim, event, "%s %s tick %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
 		} else {
-			spell.Unit.Log(sim, "%s %s %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
+			spell.Unit.LogEvent(sim, event, "%s %s %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
 		}
 	}
 
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"errors"
+	"math"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Stats which may count towards a hit or expertise cap through stat dependencies, such as
+// spirit for casters with a spirit to hit conversion.
+var statCapContributingStats = []stats.Stat{stats.HitRating, stats.ExpertiseRating, stats.Spirit}
+
+// Amount of each contributing stat added when measuring how much it counts towards a cap.
+const statCapProbeAmount = 1000.0
+
+// Computes the hit and expertise caps of a player. Contributions from other stats are found by
+// rebuilding the player with extra points of each stat and measuring the change, so that every
+// conversion the spec applies is picked up without having to special case it here.
+func computeStatCaps(request *proto.StatCapsRequest) ([]*proto.StatCap, error) {
+	if request.Player == nil {
+		return nil, errors.New("no player in stat caps request")
+	}
+
+	caps := playerStatCaps(request, stats.Stats{})
+	for _, stat := range statCapContributingStats {
+		var bonus stats.Stats
+		bonus[stat] = statCapProbeAmount
+		probeCaps := playerStatCaps(request, bonus)
+		if len(probeCaps) != len(caps) {
+			return nil, errors.New("stat caps changed when adding bonus stats")
+		}
+
+		for i, statCap := range caps {
+			if stats.Stat(statCap.Stat) == stat {
+				continue
+			}
+			coefficient := (probeCaps[i].Current - statCap.Current) / statCapProbeAmount
+			if math.Abs(coefficient) > 1e-6 {
+				statCap.Contributions = append(statCap.Contributions, &proto.StatCapContribution{
+					Stat:        proto.Stat(stat),
+					Coefficient: coefficient,
+				})
+			}
+		}
+	}
+	return caps, nil
+}
+
+// Builds the player in a stats only environment with the given bonus stats, and returns its caps
+// against the first target.
+func playerStatCaps(request *proto.StatCapsRequest, bonus stats.Stats) []*proto.StatCap {
+	player := googleProto.Clone(request.Player).(*proto.Player)
+	if player.BonusStats == nil {
+		player.BonusStats = &proto.UnitStats{}
+	}
+	player.BonusStats.Stats = stats.FromProtoArray(player.BonusStats.Stats).Add(bonus).ToProtoArray()
+
+	encounter := request.Encounter
+	if encounter == nil {
+		encounter = &proto.Encounter{}
+	}
+
+	env, raidStats, _ := NewEnvironment(SinglePlayerRaidProto(player, request.PartyBuffs, request.RaidBuffs, request.Debuffs), encounter, true)
+	character := env.Raid.Parties[0].Players[0].GetCharacter()
+	attackTable := character.AttackTables[env.Encounter.ActiveTargetUnits[0].UnitIndex]
+	finalStats := stats.FromUnitStatsProto(raidStats.Parties[0].Players[0].FinalStats)
+
+	return newStatCaps(character, attackTable, finalStats)
+}
+
+// Caps of a character with the given final stats, without contributions from other stats.
+// Hit caps are in hit rating, converted from the character's total hit chance so that hit
+// from talents and racials is included. Expertise caps are for main hand attacks, or ranged
+// attacks for characters without melee auto attacks.
+func newStatCaps(character *Character, attackTable *AttackTable, finalStats stats.Stats) []*proto.StatCap {
+	aa := &character.AutoAttacks
+	if !aa.AutoSwingMelee && !aa.AutoSwingRanged {
+		return []*proto.StatCap{{
+			Stat:    proto.Stat_StatHitRating,
+			Kind:    proto.StatCapKind_StatCapKindSpellHit,
+			Cap:     attackTable.BaseSpellMissChance * 100 * SpellHitRatingPerHitPercent,
+			Current: finalStats[stats.SpellHitPercent] * SpellHitRatingPerHitPercent,
+		}}
+	}
+
+	hit := finalStats[stats.PhysicalHitPercent] * PhysicalHitRatingPerHitPercent
+	caps := []*proto.StatCap{{
+		Stat:    proto.Stat_StatHitRating,
+		Kind:    proto.StatCapKind_StatCapKindMeleeHit,
+		Cap:     attackTable.BaseMissChance * 100 * PhysicalHitRatingPerHitPercent,
+		Current: hit,
+	}}
+	if aa.AutoSwingMelee && aa.IsDualWielding && !character.PseudoStats.DisableDWMissPenalty {
+		caps = append(caps, &proto.StatCap{
+			Stat:    proto.Stat_StatHitRating,
+			Kind:    proto.StatCapKind_StatCapKindDualWieldHit,
+			Cap:     (attackTable.BaseMissChance + 0.19) * 100 * PhysicalHitRatingPerHitPercent,
+			Current: hit,
+		})
+	}
+
+	// Racial expertise which only applies to one hand is on the auto attack spell rather than the character.
+	autoSpell := Ternary(aa.AutoSwingMelee, aa.MHAuto(), aa.RangedAuto())
+	expertise := finalStats[stats.ExpertiseRating]
+	if autoSpell != nil {
+		expertise += autoSpell.BonusExpertiseRating
+	}
+	expertisePerChance := ExpertisePerQuarterPercentReduction * 400
+	caps = append(caps, &proto.StatCap{
+		Stat:    proto.Stat_StatExpertiseRating,
+		Kind:    proto.StatCapKind_StatCapKindExpertiseDodge,
+		Cap:     attackTable.BaseDodgeChance * expertisePerChance,
+		Current: expertise,
+	})
+	if aa.AutoSwingMelee {
+		caps = append(caps, &proto.StatCap{
+			Stat:    proto.Stat_StatExpertiseRating,
+			Kind:    proto.StatCapKind_StatCapKindExpertiseParry,
+			Cap:     (attackTable.BaseDodgeChance + attackTable.BaseParryChance) * expertisePerChance,
+			Current: expertise,
+		})
+	}
+	return caps
+}
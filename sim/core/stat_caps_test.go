@@ -0,0 +1,150 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func TestNewStatCapsDualWield(t *testing.T) {
+	character := &Character{}
+	character.AutoAttacks = AutoAttacks{AutoSwingMelee: true, IsDualWielding: true}
+	// Racial expertise for one weapon type ends up on the auto attack spell.
+	character.AutoAttacks.SetMHSpell(&Spell{BonusExpertiseRating: 340})
+
+	attackTable := &AttackTable{BaseMissChance: 0.075, BaseDodgeChance: 0.075, BaseParryChance: 0.075}
+	var finalStats stats.Stats
+	finalStats[stats.PhysicalHitPercent] = 2
+	finalStats[stats.ExpertiseRating] = 1000
+
+	caps := newStatCaps(character, attackTable, finalStats)
+
+	expected := []*proto.StatCap{
+		{Stat: proto.Stat_StatHitRating, Kind: proto.StatCapKind_StatCapKindMeleeHit, Cap: 2550, Current: 680},
+		{Stat: proto.Stat_StatHitRating, Kind: proto.StatCapKind_StatCapKindDualWieldHit, Cap: 9010, Current: 680},
+		{Stat: proto.Stat_StatExpertiseRating, Kind: proto.StatCapKind_StatCapKindExpertiseDodge, Cap: 2550, Current: 1340},
+		{Stat: proto.Stat_StatExpertiseRating, Kind: proto.StatCapKind_StatCapKindExpertiseParry, Cap: 5100, Current: 1340},
+	}
+	checkStatCaps(t, caps, expected)
+
+	character.PseudoStats.DisableDWMissPenalty = true
+	caps = newStatCaps(character, attackTable, finalStats)
+	checkStatCaps(t, caps, []*proto.StatCap{expected[0], expected[2], expected[3]})
+}
+
+func TestComputeStatCapsCaster(t *testing.T) {
+	var bonusStats stats.Stats
+	bonusStats[stats.HitRating] = 340
+
+	result := ComputeStatCaps(&proto.StatCapsRequest{
+		Player: &proto.Player{
+			Name:       "Caster",
+			Race:       proto.Race_RaceTroll,
+			Class:      proto.Class_ClassShaman,
+			Spec:       &proto.Player_ElementalShaman{ElementalShaman: &proto.ElementalShaman{}},
+			Equipment:  &proto.EquipmentSpec{},
+			BonusStats: &proto.UnitStats{Stats: bonusStats.ToProtoArray()},
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{{Level: 93}},
+		},
+	})
+	if result.ErrorResult != "" {
+		t.Fatalf("ComputeStatCaps() returned error: %s", result.ErrorResult)
+	}
+
+	checkStatCaps(t, result.Caps, []*proto.StatCap{{
+		Stat:    proto.Stat_StatHitRating,
+		Kind:    proto.StatCapKind_StatCapKindSpellHit,
+		Cap:     5100,
+		Current: 340,
+		// Expertise counts towards spell hit for every class.
+		Contributions: []*proto.StatCapContribution{{Stat: proto.Stat_StatExpertiseRating, Coefficient: 1}},
+	}})
+}
+
+func checkStatCaps(t *testing.T, caps []*proto.StatCap, expected []*proto.StatCap) {
+	t.Helper()
+	if len(caps) != len(expected) {
+		t.Fatalf("got %d caps, want %d: %v", len(caps), len(expected), caps)
+	}
+	for i, want := range expected {
+		got := caps[i]
+		if got.Stat != want.Stat || got.Kind != want.Kind || math.Abs(got.Cap-want.Cap) > 1e-6 || math.Abs(got.Current-want.Current) > 1e-6 {
+			t.Errorf("cap %d = %v, want %v", i, got, want)
+		}
+		if len(got.Contributions) != len(want.Contributions) {
+			t.Errorf("cap %d contributions = %v, want %v", i, got.Contributions, want.Contributions)
+			continue
+		}
+		for j, contribution := range want.Contributions {
+			if got.Contributions[j].Stat != contribution.Stat || math.Abs(got.Contributions[j].Coefficient-contribution.Coefficient) > 1e-6 {
+				t.Errorf("cap %d contribution %d = %v, want %v", i, j, got.Contributions[j], contribution)
+			}
+		}
+	}
+}
+
+func TestComputeStatWeightsCapWeights(t *testing.T) {
+	newResult := func(dps ...float64) *proto.RaidSimResult {
+		metrics := &proto.DistributionMetrics{AllValues: dps}
+		for _, v := range dps {
+			metrics.Avg += v / float64(len(dps))
+		}
+		return &proto.RaidSimResult{
+			RaidMetrics: &proto.RaidMetrics{
+				Parties: []*proto.PartyMetrics{{
+					Players: []*proto.UnitMetrics{{
+						Dps:    metrics,
+						Hps:    &proto.DistributionMetrics{AllValues: make([]float64, len(dps))},
+						Threat: &proto.DistributionMetrics{AllValues: make([]float64, len(dps))},
+						Dtps:   &proto.DistributionMetrics{AllValues: make([]float64, len(dps))},
+						Tmi:    &proto.DistributionMetrics{AllValues: make([]float64, len(dps))},
+					}},
+				}},
+			},
+		}
+	}
+
+	statCap := &proto.StatCap{Stat: proto.Stat_StatHitRating, Kind: proto.StatCapKind_StatCapKindMeleeHit, Cap: 2550}
+	result := computeStatWeights(&proto.StatWeightsCalcRequest{
+		BaseResult:      newResult(1000, 1000),
+		EpReferenceStat: proto.Stat_StatAgility,
+		StatSimResults: []*proto.StatWeightsStatResultData{
+			{
+				StatData:   &proto.StatWeightsStatData{UnitStat: int32(stats.Agility), ModLow: -160, ModHigh: 160},
+				ResultLow:  newResult(840, 840),
+				ResultHigh: newResult(1160, 1160),
+			},
+			{
+				StatData:   &proto.StatWeightsStatData{UnitStat: int32(stats.HitRating), ModLow: -640, ModHigh: 0, Cap: statCap},
+				ResultLow:  newResult(800, 820),
+				ResultHigh: newResult(1000, 1020),
+			},
+			{
+				StatData:   &proto.StatWeightsStatData{UnitStat: int32(stats.HitRating), ModLow: 0, ModHigh: 640, Cap: statCap, AboveCap: true},
+				ResultLow:  newResult(1000, 1020),
+				ResultHigh: newResult(1032, 1052),
+			},
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("computeStatWeights() returned error: %s", result.Error.Message)
+	}
+
+	if got := result.Dps.Weights.Stats[stats.HitRating]; got != 0 {
+		t.Errorf("linear hit weight = %f, want 0", got)
+	}
+	if len(result.CapWeights) != 1 {
+		t.Fatalf("got %d cap weights, want 1", len(result.CapWeights))
+	}
+	capWeights := result.CapWeights[0]
+	if math.Abs(capWeights.WeightBelowCap-0.3125) > 1e-9 || math.Abs(capWeights.WeightAboveCap-0.05) > 1e-9 {
+		t.Errorf("cap weights = %f below, %f above, want 0.3125 below, 0.05 above", capWeights.WeightBelowCap, capWeights.WeightAboveCap)
+	}
+	if math.Abs(capWeights.EpBelowCap-0.3125) > 1e-9 || math.Abs(capWeights.EpAboveCap-0.05) > 1e-9 {
+		t.Errorf("cap EPs = %f below, %f above, want 0.3125 below, 0.05 above", capWeights.EpBelowCap, capWeights.EpAboveCap)
+	}
+}
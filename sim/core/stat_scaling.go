@@ -0,0 +1,181 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Upper bound on the number of points in a scaling curve, to catch a step which is much too small.
+const maxStatScalingPoints = 1000
+
+// Sims each point of the scaling curve in turn, with the iterations of each point split across
+// threads by the concurrent runner.
+func runStatScaling(request *proto.StatScalingRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals, simFunc RaidSimFunc) *proto.StatScalingResult {
+	requests, currentValue, err := buildStatScalingRequests(request)
+	if err != nil {
+		return &proto.StatScalingResult{Error: &proto.ErrorOutcome{Message: err.Error()}}
+	}
+
+	var iterationsTotal int32
+	for _, pointRequest := range requests {
+		iterationsTotal += pointRequest.SimOptions.Iterations
+	}
+	var iterationsDone int32
+	var simsCompleted int32
+
+	waitForResult := func(srcProgressChannel chan *proto.ProgressMetrics) *proto.RaidSimResult {
+		var lastCompleted int32 = 0
+		for metrics := range srcProgressChannel {
+			iterationsDone += metrics.CompletedIterations - lastCompleted
+			lastCompleted = metrics.CompletedIterations
+
+			if progress != nil {
+				progress <- &proto.ProgressMetrics{
+					TotalIterations:     iterationsTotal,
+					CompletedIterations: iterationsDone,
+					CompletedSims:       simsCompleted,
+					TotalSims:           int32(len(requests)),
+				}
+			}
+
+			if metrics.FinalRaidResult != nil {
+				simsCompleted++
+				return metrics.FinalRaidResult
+			}
+		}
+		return nil
+	}
+
+	results := make([]*proto.RaidSimResult, len(requests))
+	for i, pointRequest := range requests {
+		pointProgress := make(chan *proto.ProgressMetrics, 100)
+		go simFunc(pointRequest, pointProgress, signals)
+		results[i] = waitForResult(pointProgress)
+		if results[i] == nil {
+			return &proto.StatScalingResult{Error: &proto.ErrorOutcome{Message: "sim finished without a result"}}
+		}
+		if results[i].Error != nil {
+			return &proto.StatScalingResult{Error: results[i].Error}
+		}
+	}
+
+	return computeStatScaling(request, requests, results, currentValue)
+}
+
+// Builds one sim request per point of the curve, and returns the current value of the stat.
+func buildStatScalingRequests(request *proto.StatScalingRequest) ([]*proto.RaidSimRequest, float64, error) {
+	if request.Player == nil {
+		return nil, 0, errors.New("no player in stat scaling request")
+	}
+	if request.UnitStat == nil {
+		return nil, 0, errors.New("no stat in stat scaling request")
+	}
+	if request.SimOptions == nil {
+		return nil, 0, errors.New("no sim options in stat scaling request")
+	}
+	if request.Step <= 0 || request.MaxValue < request.MinValue {
+		return nil, 0, fmt.Errorf("invalid stat scaling range %g to %g in steps of %g", request.MinValue, request.MaxValue, request.Step)
+	}
+	// Small tolerance so a range which is an exact multiple of the step includes max_value.
+	numPoints := int(math.Floor((request.MaxValue-request.MinValue)/request.Step+1e-9)) + 1
+	if numPoints > maxStatScalingPoints {
+		return nil, 0, fmt.Errorf("stat scaling range has %d points, maximum is %d", numPoints, maxStatScalingPoints)
+	}
+
+	var unitStat stats.UnitStat
+	switch s := request.UnitStat.(type) {
+	case *proto.StatScalingRequest_Stat:
+		unitStat = stats.UnitStatFromStat(stats.Stat(s.Stat))
+	case *proto.StatScalingRequest_PseudoStat:
+		unitStat = stats.UnitStatFromPseudoStat(s.PseudoStat)
+	}
+
+	player := googleProto.Clone(request.Player).(*proto.Player)
+	if player.BonusStats == nil {
+		player.BonusStats = &proto.UnitStats{}
+	}
+	if player.BonusStats.Stats == nil {
+		player.BonusStats.Stats = make([]float64, stats.ProtoStatsLen)
+	}
+	if player.BonusStats.PseudoStats == nil {
+		player.BonusStats.PseudoStats = make([]float64, stats.PseudoStatsLen)
+	}
+
+	raidProto := SinglePlayerRaidProto(player, request.PartyBuffs, request.RaidBuffs, request.Debuffs)
+	raidProto.Tanks = request.Tanks
+
+	statsResult := ComputeStats(&proto.ComputeStatsRequest{Raid: raidProto, Encounter: request.Encounter})
+	if statsResult.ErrorResult != "" {
+		return nil, 0, errors.New(statsResult.ErrorResult)
+	}
+	finalStats := statsResult.RaidStats.Parties[0].Players[0].FinalStats
+	var currentValue float64
+	if unitStat.IsStat() {
+		currentValue = finalStats.Stats[unitStat.StatIdx()]
+	} else {
+		currentValue = finalStats.PseudoStats[unitStat.PseudoStatIdx()]
+	}
+
+	simOptions := googleProto.Clone(request.SimOptions).(*proto.SimOptions)
+	simOptions.SaveAllValues = true
+	// Same as stat weights: all points share a seed and use labeled rands, so the difference
+	// between two points is mostly down to the stat rather than RNG.
+	if simOptions.RandomSeed == 0 {
+		simOptions.RandomSeed = time.Now().UnixNano()
+	}
+	simOptions.UseLabeledRands = true
+
+	baseRequest := &proto.RaidSimRequest{
+		Raid:       raidProto,
+		Encounter:  request.Encounter,
+		SimOptions: simOptions,
+	}
+	requests := make([]*proto.RaidSimRequest, numPoints)
+	for i := range requests {
+		value := request.MinValue + float64(i)*request.Step
+		requests[i] = googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+		unitStat.AddToStatsProto(requests[i].Raid.Parties[0].Players[0].BonusStats, value-currentValue)
+	}
+	return requests, currentValue, nil
+}
+
+func computeStatScaling(request *proto.StatScalingRequest, requests []*proto.RaidSimRequest, results []*proto.RaidSimResult, currentValue float64) *proto.StatScalingResult {
+	result := &proto.StatScalingResult{CurrentValue: currentValue}
+
+	var previousDps *proto.DistributionMetrics
+	for i, simResult := range results {
+		dps := simResult.RaidMetrics.Parties[0].Players[0].Dps
+		point := &proto.StatScalingPoint{
+			Value:    request.MinValue + float64(i)*request.Step,
+			DpsError: dps.Stdev / math.Sqrt(float64(requests[i].SimOptions.Iterations)),
+		}
+
+		if previousDps != nil {
+			var marginal aggregator
+			for j := range dps.AllValues {
+				marginal.add(dps.AllValues[j] - previousDps.AllValues[j])
+			}
+			marginal.scale(1 / request.Step)
+			mean, stdev := marginal.meanAndStdDev()
+			point.MarginalDps = mean
+			point.MarginalDpsError = stdev / math.Sqrt(float64(len(dps.AllValues)))
+			if request.EpReferenceWeight != 0 {
+				point.MarginalEp = point.MarginalDps / request.EpReferenceWeight
+				point.MarginalEpError = point.MarginalDpsError / math.Abs(request.EpReferenceWeight)
+			}
+		}
+		previousDps = dps
+
+		point.Dps = googleProto.Clone(dps).(*proto.DistributionMetrics)
+		point.Dps.AllValues = nil
+		result.Points = append(result.Points, point)
+	}
+	return result
+}
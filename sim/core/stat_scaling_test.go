@@ -0,0 +1,90 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func TestStatScaling(t *testing.T) {
+	var bonusStats stats.Stats
+	bonusStats[stats.HasteRating] = 1000
+
+	request := &proto.StatScalingRequest{
+		Player: &proto.Player{
+			Name:       "Caster",
+			Race:       proto.Race_RaceTroll,
+			Class:      proto.Class_ClassShaman,
+			Spec:       &proto.Player_ElementalShaman{ElementalShaman: &proto.ElementalShaman{}},
+			Equipment:  &proto.EquipmentSpec{},
+			BonusStats: &proto.UnitStats{Stats: bonusStats.ToProtoArray()},
+		},
+		Encounter:  &proto.Encounter{Targets: []*proto.Target{{Level: 93}}},
+		SimOptions: &proto.SimOptions{Iterations: 4},
+		UnitStat:   &proto.StatScalingRequest_Stat{Stat: proto.Stat_StatHasteRating},
+		MinValue:   0,
+		MaxValue:   3000,
+		Step:       1500,
+
+		EpReferenceWeight: 2,
+	}
+
+	// DPS has a breakpoint at 2000 haste, and every iteration differs by the same amount so the
+	// paired differences have no spread.
+	fakeRunSim := newFakeBulkRunSim(func(player *proto.Player) float64 {
+		haste := player.BonusStats.Stats[stats.HasteRating]
+		return haste + Ternary(haste >= 2000, 3000.0, 0)
+	}, 1, nil)
+
+	progress := make(chan *proto.ProgressMetrics, 100)
+	result := runStatScaling(request, progress, simsignals.CreateSignals(), func(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
+		return fakeRunSim(rsr, progress, false, signals)
+	})
+	if result.Error != nil {
+		t.Fatalf("runStatScaling() returned error: %s", result.Error.Message)
+	}
+
+	if result.CurrentValue != 1000 {
+		t.Errorf("current value = %f, want 1000", result.CurrentValue)
+	}
+	if len(result.Points) != 3 {
+		t.Fatalf("got %d points, want 3", len(result.Points))
+	}
+	for i, want := range []struct {
+		value       float64
+		marginalDps float64
+	}{
+		{value: 0, marginalDps: 0},
+		{value: 1500, marginalDps: 1},
+		{value: 3000, marginalDps: 3},
+	} {
+		point := result.Points[i]
+		if point.Value != want.value {
+			t.Errorf("point %d value = %f, want %f", i, point.Value, want.value)
+		}
+		if math.Abs(point.MarginalDps-want.marginalDps) > 1e-9 || point.MarginalDpsError > 1e-9 {
+			t.Errorf("point %d marginal DPS = %f +- %f, want %f +- 0", i, point.MarginalDps, point.MarginalDpsError, want.marginalDps)
+		}
+		if math.Abs(point.MarginalEp-want.marginalDps/2) > 1e-9 {
+			t.Errorf("point %d marginal EP = %f, want %f", i, point.MarginalEp, want.marginalDps/2)
+		}
+		if point.DpsError != 0.5 {
+			t.Errorf("point %d DPS error = %f, want 0.5", i, point.DpsError)
+		}
+		if len(point.Dps.AllValues) != 0 {
+			t.Errorf("point %d kept all DPS values", i)
+		}
+	}
+
+	close(progress)
+	var last *proto.ProgressMetrics
+	for metrics := range progress {
+		last = metrics
+	}
+	if last == nil || last.CompletedIterations != 12 || last.TotalSims != 3 {
+		t.Errorf("last progress = %v, want 12 completed iterations of 3 sims", last)
+	}
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -215,36 +216,85 @@ func buildStatWeightRequests(swr *proto.StatWeightsRequest) *proto.StatWeightReq
 
 	}
 
-	for i := range statModsLow {
-		stat := stats.UnitStatFromIdx(i)
-		if statModsLow[stat] == 0 {
-			continue
-		}
-
-		lowSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
-		stat.AddToStatsProto(lowSimRequest.Raid.Parties[0].Players[0].BonusStats, statModsLow[stat])
+	if swr.Regression != nil {
+		swBaseResponse.StatSimRequests = buildRegressionSampleRequests(swBaseResponse.BaseRequest, statModsHigh, swr.Regression)
+	} else {
+		for i := range statModsLow {
+			stat := stats.UnitStatFromIdx(i)
+			if statModsLow[stat] == 0 {
+				continue
+			}
 
-		highSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
-		stat.AddToStatsProto(highSimRequest.Raid.Parties[0].Players[0].BonusStats, statModsHigh[stat])
+			lowSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
+			stat.AddToStatsProto(lowSimRequest.Raid.Parties[0].Players[0].BonusStats, statModsLow[stat])
+
+			highSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
+			stat.AddToStatsProto(highSimRequest.Raid.Parties[0].Players[0].BonusStats, statModsHigh[stat])
+
+			swBaseResponse.StatSimRequests = append(swBaseResponse.StatSimRequests, &proto.StatWeightsStatRequestData{
+				StatData: &proto.StatWeightsStatData{
+					UnitStat: int32(stat),
+					ModLow:   statModsLow[stat],
+					ModHigh:  statModsHigh[stat],
+				},
+				RequestLow:  lowSimRequest,
+				RequestHigh: highSimRequest,
+			})
+		}
+	}
 
-		swBaseResponse.StatSimRequests = append(swBaseResponse.StatSimRequests, &proto.StatWeightsStatRequestData{
-			StatData: &proto.StatWeightsStatData{
-				UnitStat: int32(stat),
-				ModLow:   statModsLow[stat],
-				ModHigh:  statModsHigh[stat],
-			},
-			RequestLow:  lowSimRequest,
-			RequestHigh: highSimRequest,
+	if swr.PiecewiseCaps {
+		caps, err := computeStatCaps(&proto.StatCapsRequest{
+			Player:     swr.Player,
+			RaidBuffs:  swr.RaidBuffs,
+			PartyBuffs: swr.PartyBuffs,
+			Debuffs:    swr.Debuffs,
+			Encounter:  swr.Encounter,
 		})
+		if err != nil {
+			return &proto.StatWeightRequestsData{Error: &proto.ErrorOutcome{Message: err.Error()}}
+		}
+
+		// Weigh each capped stat over a segment ending at the cap, and another one starting at it.
+		for _, statCap := range caps {
+			stat := stats.UnitStatFromStat(stats.Stat(statCap.Stat))
+			toCap := statCap.Cap - statCap.Current
+			for _, aboveCap := range []bool{false, true} {
+				modLow := Ternary(aboveCap, toCap, toCap-2*defaultStatMod)
+				modHigh := modLow + 2*defaultStatMod
+
+				lowSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
+				stat.AddToStatsProto(lowSimRequest.Raid.Parties[0].Players[0].BonusStats, modLow)
+
+				highSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
+				stat.AddToStatsProto(highSimRequest.Raid.Parties[0].Players[0].BonusStats, modHigh)
+
+				swBaseResponse.StatSimRequests = append(swBaseResponse.StatSimRequests, &proto.StatWeightsStatRequestData{
+					StatData: &proto.StatWeightsStatData{
+						UnitStat: int32(stat),
+						ModLow:   modLow,
+						ModHigh:  modHigh,
+						Cap:      statCap,
+						AboveCap: aboveCap,
+					},
+					RequestLow:  lowSimRequest,
+					RequestHigh: highSimRequest,
+				})
+			}
+		}
 	}
 
 	return swBaseResponse
 }
 
 func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsResult {
+	if slices.ContainsFunc(swcr.StatSimResults, isRegressionSample) {
+		return computeRegressionStatWeights(swcr)
+	}
+
 	haveRefStat := false
 	for _, statResult := range swcr.StatSimResults {
-		if statResult.StatData.UnitStat == int32(swcr.EpReferenceStat) {
+		if statResult.StatData.Cap == nil && statResult.StatData.UnitStat == int32(swcr.EpReferenceStat) {
 			haveRefStat = true
 			break
 		}
@@ -255,6 +305,9 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 
 	result := NewStatWeightsResult()
 	for _, statResult := range swcr.StatSimResults {
+		if statResult.StatData.Cap != nil {
+			continue
+		}
 		stat := stats.UnitStatFromIdx(int(statResult.StatData.UnitStat))
 
 		baselinePlayer := swcr.BaseResult.RaidMetrics.Parties[0].Players[0]
@@ -294,12 +347,22 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 		result.PDeath.WeightsStdev.AddStat(stat, 0)
 	}
 
+	var weighedStats []stats.UnitStat
+	for _, statResult := range swcr.StatSimResults {
+		if statResult.StatData.Cap == nil {
+			weighedStats = append(weighedStats, stats.UnitStatFromIdx(int(statResult.StatData.UnitStat)))
+		}
+	}
 	referenceStat := stats.Stat(swcr.EpReferenceStat)
+	computeEpValues(result, weighedStats, referenceStat)
 
-	// Compute EP results.
-	for _, statData := range swcr.StatSimResults {
-		stat := stats.UnitStatFromIdx(int(statData.StatData.UnitStat))
+	resultProto := result.ToProto()
+	resultProto.CapWeights = computeCapWeights(swcr, result.Dps.Weights.Stats[referenceStat])
+	return resultProto
+}
 
+func computeEpValues(result *StatWeightsResult, weighedStats []stats.UnitStat, referenceStat stats.Stat) {
+	for _, stat := range weighedStats {
 		calcEpResults := func(weightResults *StatWeightValues, refStat stats.Stat) {
 			if weightResults.Weights.Stats[refStat] == 0 {
 				return
@@ -317,13 +380,57 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 		calcEpResults(&result.Tmi, DTPSReferenceStat)
 		calcEpResults(&result.PDeath, DTPSReferenceStat)
 	}
+}
+
+// DPS weights of capped stats on either side of their caps. Unlike regular stat weights these
+// don't use the baseline, since the slope is taken between the low and high results directly.
+func computeCapWeights(swcr *proto.StatWeightsCalcRequest, referenceWeight float64) []*proto.StatCapWeights {
+	var capWeights []*proto.StatCapWeights
+	for _, statResult := range swcr.StatSimResults {
+		statData := statResult.StatData
+		if statData.Cap == nil {
+			continue
+		}
+
+		lowMetrics := statResult.ResultLow.RaidMetrics.Parties[0].Players[0].Dps
+		highMetrics := statResult.ResultHigh.RaidMetrics.Parties[0].Players[0].Dps
+		var slope aggregator
+		for i := range lowMetrics.AllValues {
+			slope.add(highMetrics.AllValues[i] - lowMetrics.AllValues[i])
+		}
+		slope.scale(1 / (statData.ModHigh - statData.ModLow))
+		weight, stdev := slope.meanAndStdDev()
+		ep := 0.0
+		if referenceWeight != 0 {
+			ep = weight / referenceWeight
+		}
 
-	return result.ToProto()
+		idx := slices.IndexFunc(capWeights, func(cw *proto.StatCapWeights) bool {
+			return cw.Cap.Kind == statData.Cap.Kind
+		})
+		if idx == -1 {
+			capWeights = append(capWeights, &proto.StatCapWeights{Cap: statData.Cap})
+			idx = len(capWeights) - 1
+		}
+		if statData.AboveCap {
+			capWeights[idx].WeightAboveCap = weight
+			capWeights[idx].WeightAboveCapStdev = stdev
+			capWeights[idx].EpAboveCap = ep
+		} else {
+			capWeights[idx].WeightBelowCap = weight
+			capWeights[idx].WeightBelowCapStdev = stdev
+			capWeights[idx].EpBelowCap = ep
+		}
+	}
+	return capWeights
 }
 
 // Run stat weight sims and compute weights.
-func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.StatWeightsResult {
+func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals, simFunc RaidSimFunc) *proto.StatWeightsResult {
 	requestData := buildStatWeightRequests(request)
+	if requestData.Error != nil {
+		return &proto.StatWeightsResult{Error: requestData.Error}
+	}
 
 	var iterationsTotal int32 = requestData.BaseRequest.SimOptions.Iterations
 	var iterationsDone int32 = 0
@@ -359,12 +466,6 @@ func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.Prog
 		return nil
 	}
 
-	simFunc := runSimConcurrent
-	// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
-	if IsRunningInWasm() {
-		simFunc = RunSim
-	}
-
 	baseProgress := make(chan *proto.ProgressMetrics, 100)
 	go simFunc(requestData.BaseRequest, baseProgress, signals)
 	baselineResult := waitForResult(baseProgress)
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Builds the sim requests for regression stat weights. Each sample perturbs every weighed stat by
+// a random amount up to its stat mod, and is simmed together with the negated perturbation so the
+// samples are balanced around the current stats.
+func buildRegressionSampleRequests(baseRequest *proto.RaidSimRequest, statMods []float64, regression *proto.StatWeightsRegression) []*proto.StatWeightsStatRequestData {
+	numStats := 0
+	for _, mod := range statMods {
+		if mod != 0 {
+			numStats++
+		}
+	}
+	numSamples := int(regression.NumSamples)
+	if numSamples <= 0 {
+		numSamples = 2 * numStats
+	}
+
+	// Seeded from the sim seed, so a fixed seed also gives fixed perturbations.
+	rand := NewSplitMix(uint64(baseRequest.SimOptions.RandomSeed))
+
+	requests := make([]*proto.StatWeightsStatRequestData, 0, numSamples)
+	for range numSamples {
+		sampleMods := make([]float64, len(statMods))
+		lowSimRequest := googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+		highSimRequest := googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+		for i, mod := range statMods {
+			if mod == 0 {
+				continue
+			}
+			stat := stats.UnitStatFromIdx(i)
+			sampleMods[i] = mod * (2*rand.NextFloat64() - 1)
+			stat.AddToStatsProto(lowSimRequest.Raid.Parties[0].Players[0].BonusStats, -sampleMods[i])
+			stat.AddToStatsProto(highSimRequest.Raid.Parties[0].Players[0].BonusStats, sampleMods[i])
+		}
+
+		requests = append(requests, &proto.StatWeightsStatRequestData{
+			StatData: &proto.StatWeightsStatData{
+				SampleMods: sampleMods,
+				Quadratic:  regression.Quadratic,
+			},
+			RequestLow:  lowSimRequest,
+			RequestHigh: highSimRequest,
+		})
+	}
+	return requests
+}
+
+func isRegressionSample(statResult *proto.StatWeightsStatResultData) bool {
+	return len(statResult.StatData.SampleMods) > 0
+}
+
+// Fits the change from the baseline of each metric to the sample perturbations. Weights are the
+// linear coefficients, and the weight stdevs hold their standard errors.
+func computeRegressionStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsResult {
+	var samples []*proto.StatWeightsStatResultData
+	for _, statResult := range swcr.StatSimResults {
+		if isRegressionSample(statResult) {
+			samples = append(samples, statResult)
+		}
+	}
+
+	// Only stats which were perturbed in some sample can be fit. Each column is scaled by its
+	// largest perturbation, to keep the normal equations well conditioned.
+	var weighedStats []stats.UnitStat
+	var scales []float64
+	for i := range samples[0].StatData.SampleMods {
+		scale := 0.0
+		for _, sample := range samples {
+			scale = max(scale, math.Abs(sample.StatData.SampleMods[i]))
+		}
+		if scale > 0 {
+			weighedStats = append(weighedStats, stats.UnitStatFromIdx(i))
+			scales = append(scales, scale)
+		}
+	}
+
+	referenceStat := stats.Stat(swcr.EpReferenceStat)
+	if !slices.Contains(weighedStats, stats.UnitStatFromStat(referenceStat)) {
+		return &proto.StatWeightsResult{Error: &proto.ErrorOutcome{Message: "No result for reference stat exists!"}}
+	}
+
+	quadratic := samples[0].StatData.Quadratic
+	var x [][]float64
+	for _, sample := range samples {
+		for _, sign := range []float64{-1, 1} {
+			row := make([]float64, 0, 2*len(weighedStats))
+			for i, stat := range weighedStats {
+				row = append(row, sign*sample.StatData.SampleMods[stat]/scales[i])
+			}
+			if quadratic {
+				for i := range weighedStats {
+					row = append(row, row[i]*row[i])
+				}
+			}
+			x = append(x, row)
+		}
+	}
+
+	result := NewStatWeightsResult()
+	baselinePlayer := swcr.BaseResult.RaidMetrics.Parties[0].Players[0]
+	fitMetric := func(getMetric func(*proto.UnitMetrics) float64, weightResults *StatWeightValues) error {
+		y := make([]float64, 0, len(x))
+		for _, sample := range samples {
+			y = append(y, getMetric(sample.ResultLow.RaidMetrics.Parties[0].Players[0])-getMetric(baselinePlayer))
+			y = append(y, getMetric(sample.ResultHigh.RaidMetrics.Parties[0].Players[0])-getMetric(baselinePlayer))
+		}
+
+		coefficients, standardErrors, err := fitLeastSquares(x, y)
+		if err != nil {
+			return err
+		}
+		for i, stat := range weighedStats {
+			weightResults.Weights.AddStat(stat, coefficients[i]/scales[i])
+			weightResults.WeightsStdev.AddStat(stat, standardErrors[i]/scales[i])
+		}
+		return nil
+	}
+
+	for _, metric := range []struct {
+		getMetric     func(*proto.UnitMetrics) float64
+		weightResults *StatWeightValues
+	}{
+		{func(m *proto.UnitMetrics) float64 { return m.GetDps().GetAvg() }, &result.Dps},
+		{func(m *proto.UnitMetrics) float64 { return m.GetHps().GetAvg() }, &result.Hps},
+		{func(m *proto.UnitMetrics) float64 { return m.GetThreat().GetAvg() }, &result.Tps},
+		{func(m *proto.UnitMetrics) float64 { return m.GetDtps().GetAvg() }, &result.Dtps},
+		{func(m *proto.UnitMetrics) float64 { return m.GetTmi().GetAvg() }, &result.Tmi},
+		{func(m *proto.UnitMetrics) float64 { return m.ChanceOfDeath }, &result.PDeath},
+	} {
+		if err := fitMetric(metric.getMetric, metric.weightResults); err != nil {
+			return &proto.StatWeightsResult{Error: &proto.ErrorOutcome{Message: err.Error()}}
+		}
+	}
+
+	computeEpValues(result, weighedStats, referenceStat)
+
+	resultProto := result.ToProto()
+	resultProto.CapWeights = computeCapWeights(swcr, result.Dps.Weights.Stats[referenceStat])
+	return resultProto
+}
+
+// Ordinary least squares fit of y = x * coefficients, without an intercept. Returns the
+// coefficients and their standard errors.
+func fitLeastSquares(x [][]float64, y []float64) ([]float64, []float64, error) {
+	numRows := len(x)
+	numCols := len(x[0])
+	if numRows <= numCols {
+		return nil, nil, fmt.Errorf("regression needs more than %d samples to fit %d coefficients, got %d", numCols, numCols, numRows)
+	}
+
+	// Invert the normal matrix x^T x with Gauss-Jordan elimination, carrying x^T y along.
+	normal := make([][]float64, numCols)
+	for i := range normal {
+		normal[i] = make([]float64, 2*numCols+1)
+		for j := range numCols {
+			for _, row := range x {
+				normal[i][j] += row[i] * row[j]
+			}
+		}
+		normal[i][numCols+i] = 1
+		for r, row := range x {
+			normal[i][2*numCols] += row[i] * y[r]
+		}
+	}
+
+	for col := range numCols {
+		pivot := col
+		for r := col + 1; r < numCols; r++ {
+			if math.Abs(normal[r][col]) > math.Abs(normal[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(normal[pivot][col]) < 1e-12 {
+			return nil, nil, errors.New("regression samples don't vary every stat independently")
+		}
+		normal[col], normal[pivot] = normal[pivot], normal[col]
+
+		divisor := normal[col][col]
+		for j := range normal[col] {
+			normal[col][j] /= divisor
+		}
+		for r := range numCols {
+			if r == col || normal[r][col] == 0 {
+				continue
+			}
+			factor := normal[r][col]
+			for j := range normal[r] {
+				normal[r][j] -= factor * normal[col][j]
+			}
+		}
+	}
+
+	coefficients := make([]float64, numCols)
+	for i := range coefficients {
+		coefficients[i] = normal[i][2*numCols]
+	}
+
+	residualSumOfSquares := 0.0
+	for r, row := range x {
+		residual := y[r]
+		for j, v := range row {
+			residual -= v * coefficients[j]
+		}
+		residualSumOfSquares += residual * residual
+	}
+	variance := residualSumOfSquares / float64(numRows-numCols)
+
+	standardErrors := make([]float64, numCols)
+	for i := range standardErrors {
+		standardErrors[i] = math.Sqrt(variance * normal[i][numCols+i])
+	}
+	return coefficients, standardErrors, nil
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func TestRegressionStatWeights(t *testing.T) {
+	for _, tc := range []struct {
+		comment   string
+		quadratic bool
+		critCurve float64
+	}{
+		{comment: "linear", quadratic: false},
+		{comment: "quadratic", quadratic: true, critCurve: -0.001},
+	} {
+		t.Run(tc.comment, func(t *testing.T) {
+			baseRequest := &proto.RaidSimRequest{
+				Raid: &proto.Raid{
+					Parties: []*proto.Party{{Players: []*proto.Player{{
+						BonusStats: &proto.UnitStats{
+							Stats:       make([]float64, stats.ProtoStatsLen),
+							PseudoStats: make([]float64, stats.PseudoStatsLen),
+						},
+					}}}},
+				},
+				SimOptions: &proto.SimOptions{RandomSeed: 5},
+			}
+			statMods := make([]float64, stats.UnitStatsLen)
+			statMods[stats.Agility] = 160
+			statMods[stats.CritRating] = 320
+
+			requests := buildRegressionSampleRequests(baseRequest, statMods, &proto.StatWeightsRegression{Quadratic: tc.quadratic})
+			if len(requests) != 4 {
+				t.Fatalf("got %d samples, want 4", len(requests))
+			}
+
+			// DPS is exactly 2 per agility and 0.5 per crit at the current stats.
+			newResult := func(request *proto.RaidSimRequest) *proto.RaidSimResult {
+				bonusStats := request.Raid.Parties[0].Players[0].BonusStats.Stats
+				crit := bonusStats[stats.CritRating]
+				dps := &proto.DistributionMetrics{Avg: 1000 + 2*bonusStats[stats.Agility] + 0.5*crit + tc.critCurve*crit*crit}
+				return &proto.RaidSimResult{
+					RaidMetrics: &proto.RaidMetrics{
+						Parties: []*proto.PartyMetrics{{Players: []*proto.UnitMetrics{{Dps: dps}}}},
+					},
+				}
+			}
+
+			calcRequest := &proto.StatWeightsCalcRequest{
+				BaseResult:      newResult(baseRequest),
+				EpReferenceStat: proto.Stat_StatAgility,
+			}
+			for _, request := range requests {
+				calcRequest.StatSimResults = append(calcRequest.StatSimResults, &proto.StatWeightsStatResultData{
+					StatData:   request.StatData,
+					ResultLow:  newResult(request.RequestLow),
+					ResultHigh: newResult(request.RequestHigh),
+				})
+			}
+
+			result := computeStatWeights(calcRequest)
+			if result.Error != nil {
+				t.Fatalf("computeStatWeights() returned error: %s", result.Error.Message)
+			}
+			if got := result.Dps.Weights.Stats[stats.Agility]; math.Abs(got-2) > 1e-6 {
+				t.Errorf("agility weight = %f, want 2", got)
+			}
+			if got := result.Dps.Weights.Stats[stats.CritRating]; math.Abs(got-0.5) > 1e-6 {
+				t.Errorf("crit weight = %f, want 0.5", got)
+			}
+			if got := result.Dps.EpValues.Stats[stats.CritRating]; math.Abs(got-0.25) > 1e-6 {
+				t.Errorf("crit EP = %f, want 0.25", got)
+			}
+		})
+	}
+}
+
+func TestFitLeastSquares(t *testing.T) {
+	x := [][]float64{{1, 0}, {0, 1}, {1, 1}, {-1, 1}}
+	y := []float64{1, 2, 3, 1.5}
+
+	coefficients, standardErrors, err := fitLeastSquares(x, y)
+	if err != nil {
+		t.Fatalf("fitLeastSquares() returned error: %v", err)
+	}
+	// Normal equations: [3 0; 0 3] b = [2.5 6.5].
+	if math.Abs(coefficients[0]-2.5/3) > 1e-9 || math.Abs(coefficients[1]-6.5/3) > 1e-9 {
+		t.Errorf("coefficients = %v, want [%f %f]", coefficients, 2.5/3, 6.5/3)
+	}
+	for i, standardError := range standardErrors {
+		if standardError <= 0 {
+			t.Errorf("standard error %d = %f, want > 0 for a noisy fit", i, standardError)
+		}
+	}
+
+	if _, _, err := fitLeastSquares([][]float64{{1, 2}, {2, 4}, {3, 6}}, []float64{1, 2, 3}); err == nil {
+		t.Errorf("fitLeastSquares() with collinear columns returned no error")
+	}
+}
@@ -0,0 +1,347 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"runtime/debug"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultSweepPruningIterations = 100
+	defaultSweepMaxMajorGlyphs    = 8
+	numTalentTiers                = 6
+	numMajorGlyphSlots            = 3
+)
+
+// The major glyphs of each class, by glyph ID.
+var classMajorGlyphs = map[proto.Class]map[int32]string{
+	proto.Class_ClassDeathKnight: proto.DeathKnightMajorGlyph_name,
+	proto.Class_ClassDruid:       proto.DruidMajorGlyph_name,
+	proto.Class_ClassHunter:      proto.HunterMajorGlyph_name,
+	proto.Class_ClassMage:        proto.MageMajorGlyph_name,
+	proto.Class_ClassMonk:        proto.MonkMajorGlyph_name,
+	proto.Class_ClassPaladin:     proto.PaladinMajorGlyph_name,
+	proto.Class_ClassPriest:      proto.PriestMajorGlyph_name,
+	proto.Class_ClassRogue:       proto.RogueMajorGlyph_name,
+	proto.Class_ClassShaman:      proto.ShamanMajorGlyph_name,
+	proto.Class_ClassWarlock:     proto.WarlockMajorGlyph_name,
+	proto.Class_ClassWarrior:     proto.WarriorMajorGlyph_name,
+}
+
+// talentSweep sims the choices of talent tiers and combinations of major glyphs, using the bulk
+// sim combo runner.
+type talentSweep struct {
+	// Function used to run the sim of each choice.
+	SingleRaidSimRunner raidSimRunner
+	Request             *proto.TalentSweepRequest
+
+	baseSettings *proto.RaidSimRequest
+	player       *proto.Player
+	confidence   float64
+}
+
+func runTalentSweep(signals simsignals.Signals, request *proto.TalentSweepRequest, progress chan *proto.ProgressMetrics) *proto.TalentSweepResult {
+	sweep := &talentSweep{
+		SingleRaidSimRunner: runSim,
+		Request:             request,
+	}
+	return sweep.Run(signals, progress)
+}
+
+func (ts *talentSweep) Run(signals simsignals.Signals, progress chan *proto.ProgressMetrics) (result *proto.TalentSweepResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.TalentSweepResult{
+				Error: &proto.ErrorOutcome{
+					Message: fmt.Sprintf("%v\nStack Trace:\n%s", err, string(debug.Stack())),
+				},
+			}
+		}
+	}()
+	errorResult := func(format string, args ...any) *proto.TalentSweepResult {
+		return &proto.TalentSweepResult{
+			Error: &proto.ErrorOutcome{Message: "talent sweep: " + fmt.Sprintf(format, args...)},
+		}
+	}
+
+	baseSettings, player, err := singlePlayerBaseSettings(ts.Request.GetBaseSettings())
+	if err != nil {
+		return errorResult("%v", err)
+	}
+	ts.baseSettings = baseSettings
+	ts.player = player
+
+	ts.confidence = ts.Request.ConfidenceLevel
+	if ts.confidence == 0 {
+		ts.confidence = defaultBulkConfidenceLevel
+	} else if ts.confidence < 0 || ts.confidence >= 1 {
+		return errorResult("confidence level must be between 0 and 1, got %f", ts.confidence)
+	}
+
+	if len(ts.Request.TalentTiers) == 0 && !ts.Request.SweepMajorGlyphs {
+		return errorResult("no talent tiers or glyphs to sweep")
+	}
+	if len(ts.Request.TalentTiers) > 0 && len(player.TalentsString) != numTalentTiers {
+		return errorResult("expected a %d-digit talents string, got %q", numTalentTiers, player.TalentsString)
+	}
+	for _, tier := range ts.Request.TalentTiers {
+		if tier < 1 || tier > numTalentTiers {
+			return errorResult("talent tier must be between 1 and %d, got %d", numTalentTiers, tier)
+		}
+	}
+
+	majorGlyphs := ts.Request.MajorGlyphs
+	if ts.Request.SweepMajorGlyphs {
+		classGlyphs, ok := classMajorGlyphs[player.Class]
+		if !ok {
+			return errorResult("no major glyphs for %s", player.Class)
+		}
+		if len(majorGlyphs) == 0 {
+			majorGlyphs = slices.DeleteFunc(slices.Sorted(maps.Keys(classGlyphs)), func(glyph int32) bool { return glyph == 0 })
+		}
+		for _, glyph := range majorGlyphs {
+			if _, ok := classGlyphs[glyph]; !ok || glyph == 0 {
+				return errorResult("%d is not a major glyph of %s", glyph, player.Class)
+			}
+		}
+	}
+
+	result = &proto.TalentSweepResult{}
+	if len(ts.Request.TalentTiers) > 0 {
+		tiers, errorOutcome := ts.sweepTalents(signals, progress)
+		if errorOutcome != nil {
+			return &proto.TalentSweepResult{Error: errorOutcome}
+		}
+		result.Tiers = tiers
+	}
+	if ts.Request.SweepMajorGlyphs {
+		glyphSets, pruned, errorOutcome := ts.sweepMajorGlyphs(signals, majorGlyphs, progress)
+		if errorOutcome != nil {
+			return &proto.TalentSweepResult{Error: errorOutcome}
+		}
+		result.GlyphSets = glyphSets
+		result.PrunedMajorGlyphs = pruned
+	}
+	return result
+}
+
+// Sims each choice of the swept tiers at low iterations, then the choices which aren't clearly
+// worse than the best of their tier at full iterations.
+func (ts *talentSweep) sweepTalents(signals simsignals.Signals, progress chan *proto.ProgressMetrics) ([]*proto.TalentTierResult, *proto.ErrorOutcome) {
+	// Tiers share the loadout of their current choice, which only needs to be simmed once.
+	var loadouts []string
+	loadoutIndex := make(map[string]int)
+	tierLoadouts := make([][]string, len(ts.Request.TalentTiers))
+	for i, tier := range ts.Request.TalentTiers {
+		for choice := 1; choice <= 3; choice++ {
+			talents := []byte(ts.player.TalentsString)
+			talents[tier-1] = byte('0' + choice)
+			loadout := string(talents)
+			if _, ok := loadoutIndex[loadout]; !ok {
+				loadoutIndex[loadout] = len(loadouts)
+				loadouts = append(loadouts, loadout)
+			}
+			tierLoadouts[i] = append(tierLoadouts[i], loadout)
+		}
+	}
+
+	withTalents := func(loadouts []string) []*proto.RaidSimRequest {
+		return MapSlice(loadouts, func(talents string) *proto.RaidSimRequest {
+			request := goproto.Clone(ts.baseSettings).(*proto.RaidSimRequest)
+			request.Raid.Parties[0].Players[0].TalentsString = talents
+			return request
+		})
+	}
+	pruningResults, errorOutcome := ts.simAll(signals, withTalents(loadouts), ts.pruningIterations(), progress)
+	if errorOutcome != nil {
+		return nil, errorOutcome
+	}
+
+	survived := make(map[string]bool)
+	for _, tierLoadout := range tierLoadouts {
+		for _, r := range undominatedResults(rankedLoadouts(tierLoadout, loadoutIndex, pruningResults), 1, ts.confidence) {
+			survived[r.Request.Raid.Parties[0].Players[0].TalentsString] = true
+		}
+	}
+	survivors := slices.DeleteFunc(slices.Clone(loadouts), func(loadout string) bool { return !survived[loadout] })
+	survivorIndex := make(map[string]int)
+	for i, loadout := range survivors {
+		survivorIndex[loadout] = i
+	}
+	finalResults, errorOutcome := ts.simAll(signals, withTalents(survivors), ts.iterations(), progress)
+	if errorOutcome != nil {
+		return nil, errorOutcome
+	}
+
+	tiers := make([]*proto.TalentTierResult, len(ts.Request.TalentTiers))
+	for i, tier := range ts.Request.TalentTiers {
+		tiers[i] = &proto.TalentTierResult{Tier: tier}
+		choiceResult := func(r *itemSubstitutionSimResult, pruned bool) *proto.TalentChoiceResult {
+			talents := r.Request.Raid.Parties[0].Players[0].TalentsString
+			dps, dpsInterval := r.playerDps(ts.confidence)
+			return &proto.TalentChoiceResult{
+				Choice:        int32(talents[tier-1] - '0'),
+				TalentsString: talents,
+				Dps:           dps,
+				DpsInterval:   dpsInterval,
+				Pruned:        pruned,
+				Current:       talents == ts.player.TalentsString,
+			}
+		}
+		for _, r := range rankedLoadouts(FilterSlice(tierLoadouts[i], func(loadout string) bool { return survived[loadout] }), survivorIndex, finalResults) {
+			tiers[i].Choices = append(tiers[i].Choices, choiceResult(r, false))
+		}
+		for _, r := range rankedLoadouts(FilterSlice(tierLoadouts[i], func(loadout string) bool { return !survived[loadout] }), loadoutIndex, pruningResults) {
+			tiers[i].Choices = append(tiers[i].Choices, choiceResult(r, true))
+		}
+	}
+	return tiers, nil
+}
+
+// Sims each major glyph alone at low iterations, then every combination of the glyphs which aren't
+// clearly worse than the best few at full iterations.
+func (ts *talentSweep) sweepMajorGlyphs(signals simsignals.Signals, majorGlyphs []int32, progress chan *proto.ProgressMetrics) ([]*proto.GlyphSetResult, []int32, *proto.ErrorOutcome) {
+	withMajorGlyphs := func(glyphSets [][]int32) []*proto.RaidSimRequest {
+		return MapSlice(glyphSets, func(glyphs []int32) *proto.RaidSimRequest {
+			request := goproto.Clone(ts.baseSettings).(*proto.RaidSimRequest)
+			player := request.Raid.Parties[0].Players[0]
+			if player.Glyphs == nil {
+				player.Glyphs = &proto.Glyphs{}
+			}
+			glyphs = append(slices.Clone(glyphs), make([]int32, numMajorGlyphSlots-len(glyphs))...)
+			player.Glyphs.Major1, player.Glyphs.Major2, player.Glyphs.Major3 = glyphs[0], glyphs[1], glyphs[2]
+			return request
+		})
+	}
+
+	candidates := majorGlyphs
+	var pruned []int32
+	if len(majorGlyphs) > numMajorGlyphSlots {
+		singles := MapSlice(majorGlyphs, func(glyph int32) []int32 { return []int32{glyph} })
+		pruningResults, errorOutcome := ts.simAll(signals, withMajorGlyphs(singles), ts.pruningIterations(), progress)
+		if errorOutcome != nil {
+			return nil, nil, errorOutcome
+		}
+		ranked := slices.Clone(pruningResults)
+		slices.SortStableFunc(ranked, func(a, b *itemSubstitutionSimResult) int {
+			return compareScores(b, a)
+		})
+		survivors := undominatedResults(ranked, numMajorGlyphSlots, ts.confidence)
+		maxGlyphs := int(ts.Request.MaxMajorGlyphs)
+		if maxGlyphs <= 0 {
+			maxGlyphs = defaultSweepMaxMajorGlyphs
+		}
+		if len(survivors) > max(maxGlyphs, numMajorGlyphSlots) {
+			survivors = survivors[:max(maxGlyphs, numMajorGlyphSlots)]
+		}
+
+		candidates = MapSlice(survivors, func(r *itemSubstitutionSimResult) int32 {
+			return r.Request.Raid.Parties[0].Players[0].Glyphs.Major1
+		})
+		pruned = FilterSlice(majorGlyphs, func(glyph int32) bool { return !slices.Contains(candidates, glyph) })
+		slices.Sort(candidates)
+	}
+
+	glyphSets := glyphCombinations(candidates, numMajorGlyphSlots)
+	finalResults, errorOutcome := ts.simAll(signals, withMajorGlyphs(glyphSets), ts.iterations(), progress)
+	if errorOutcome != nil {
+		return nil, nil, errorOutcome
+	}
+
+	currentGlyphs := ts.player.GetGlyphs()
+	current := []int32{currentGlyphs.GetMajor1(), currentGlyphs.GetMajor2(), currentGlyphs.GetMajor3()}
+	current = slices.DeleteFunc(current, func(glyph int32) bool { return glyph == 0 })
+	slices.Sort(current)
+
+	results := make([]*proto.GlyphSetResult, len(glyphSets))
+	for i, r := range finalResults {
+		dps, dpsInterval := r.playerDps(ts.confidence)
+		results[i] = &proto.GlyphSetResult{
+			MajorGlyphs: glyphSets[i],
+			Dps:         dps,
+			DpsInterval: dpsInterval,
+			Current:     slices.Equal(glyphSets[i], current),
+		}
+	}
+	slices.SortStableFunc(results, func(a, b *proto.GlyphSetResult) int {
+		return cmp.Compare(b.Dps.Avg, a.Dps.Avg)
+	})
+	return results, pruned, nil
+}
+
+// All sorted combinations of size n of the sorted glyphs, or just all of them if there are fewer.
+func glyphCombinations(glyphs []int32, n int) [][]int32 {
+	if len(glyphs) <= n {
+		return [][]int32{slices.Clone(glyphs)}
+	}
+	var combinations [][]int32
+	var combine func(start int, combination []int32)
+	combine = func(start int, combination []int32) {
+		if len(combination) == n {
+			combinations = append(combinations, slices.Clone(combination))
+			return
+		}
+		for i := start; i <= len(glyphs)-(n-len(combination)); i++ {
+			combine(i+1, append(combination, glyphs[i]))
+		}
+	}
+	combine(0, make([]int32, 0, n))
+	return combinations
+}
+
+// Sims each request with the given iterations, returning the results in the same order.
+func (ts *talentSweep) simAll(signals simsignals.Signals, requests []*proto.RaidSimRequest, iterations int32, progress chan *proto.ProgressMetrics) ([]*itemSubstitutionSimResult, *proto.ErrorOutcome) {
+	index := make(map[*proto.RaidSimRequest]int, len(requests))
+	combos := make([]singleBulkSim, len(requests))
+	for i, request := range requests {
+		index[request] = i
+		combos[i] = singleBulkSim{req: request, cl: &raidSimRequestChangeLog{}, eq: &equipmentSubstitution{}}
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	bulk := &bulkSimRunner{SingleRaidSimRunner: ts.SingleRaidSimRunner}
+	rankedResults, _, errorOutcome := bulk.getRankedResults(signals, bulkSimsToChan(combos, done), int32(len(combos)), iterations, progress)
+	if errorOutcome != nil {
+		return nil, errorOutcome
+	}
+
+	results := make([]*itemSubstitutionSimResult, len(requests))
+	for _, r := range rankedResults {
+		results[index[r.Request]] = r
+	}
+	return results, nil
+}
+
+// The results of the loadouts, best first.
+func rankedLoadouts(loadouts []string, index map[string]int, results []*itemSubstitutionSimResult) []*itemSubstitutionSimResult {
+	ranked := MapSlice(loadouts, func(loadout string) *itemSubstitutionSimResult { return results[index[loadout]] })
+	slices.SortStableFunc(ranked, func(a, b *itemSubstitutionSimResult) int {
+		return compareScores(b, a)
+	})
+	return ranked
+}
+
+func (ts *talentSweep) pruningIterations() int32 {
+	if ts.Request.PruningIterations > 0 {
+		return ts.Request.PruningIterations
+	}
+	return defaultSweepPruningIterations
+}
+
+func (ts *talentSweep) iterations() int32 {
+	if ts.Request.Iterations > 0 {
+		return ts.Request.Iterations
+	}
+	return defaultIterationsPerCombo
+}
+
+func compareScores(a, b *itemSubstitutionSimResult) int {
+	return cmp.Compare(a.Score(), b.Score())
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+func TestGlyphCombinations(t *testing.T) {
+	got := glyphCombinations([]int32{1, 2, 3, 4}, 3)
+	want := [][]int32{{1, 2, 3}, {1, 2, 4}, {1, 3, 4}, {2, 3, 4}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("glyphCombinations() = %v, want %v", got, want)
+	}
+	if got := glyphCombinations([]int32{1, 2}, 3); len(got) != 1 || !slices.Equal(got[0], []int32{1, 2}) {
+		t.Errorf("glyphCombinations() with too few glyphs = %v, want [[1 2]]", got)
+	}
+}
+
+func TestTalentSweep(t *testing.T) {
+	glyphBonus := map[int32]float64{
+		int32(proto.ShamanMajorGlyph_GlyphOfUnstableEarth):  20,
+		int32(proto.ShamanMajorGlyph_GlyphOfChainLightning): 20,
+		int32(proto.ShamanMajorGlyph_GlyphOfSpiritWalk):     20,
+		int32(proto.ShamanMajorGlyph_GlyphOfCapacitorTotem): 19,
+	}
+	talentBonus := map[string]float64{"211111": 100, "311111": 101, "121111": 50}
+
+	fakeRunSim := newFakeBulkRunSim(func(player *proto.Player) float64 {
+		dps := 1000 + talentBonus[player.TalentsString]
+		for _, glyph := range []int32{player.Glyphs.Major1, player.Glyphs.Major2, player.Glyphs.Major3} {
+			dps += glyphBonus[glyph]
+		}
+		return dps
+	}, 10, nil)
+
+	sweep := &talentSweep{
+		SingleRaidSimRunner: fakeRunSim,
+		Request: &proto.TalentSweepRequest{
+			BaseSettings: &proto.RaidSimRequest{
+				Raid: SinglePlayerRaidProto(&proto.Player{
+					Name:          "Sweeper",
+					Class:         proto.Class_ClassShaman,
+					TalentsString: "111111",
+					Glyphs:        &proto.Glyphs{Major1: int32(proto.ShamanMajorGlyph_GlyphOfFireNova)},
+				}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+				SimOptions: &proto.SimOptions{},
+			},
+			TalentTiers:      []int32{1, 2},
+			SweepMajorGlyphs: true,
+			MajorGlyphs: []int32{
+				int32(proto.ShamanMajorGlyph_GlyphOfUnstableEarth),
+				int32(proto.ShamanMajorGlyph_GlyphOfChainLightning),
+				int32(proto.ShamanMajorGlyph_GlyphOfSpiritWalk),
+				int32(proto.ShamanMajorGlyph_GlyphOfCapacitorTotem),
+				int32(proto.ShamanMajorGlyph_GlyphOfPurge),
+			},
+		},
+	}
+	result := sweep.Run(simsignals.CreateSignals(), nil)
+	if result.Error != nil {
+		t.Fatalf("Run() returned error: %s", result.Error.Message)
+	}
+
+	type choice struct {
+		choice  int32
+		pruned  bool
+		current bool
+	}
+	wantTiers := [][]choice{
+		{{3, false, false}, {2, false, false}, {1, true, true}},
+		{{2, false, false}, {1, true, true}, {3, true, false}},
+	}
+	if len(result.Tiers) != len(wantTiers) {
+		t.Fatalf("got %d tiers, want %d", len(result.Tiers), len(wantTiers))
+	}
+	for i, tier := range result.Tiers {
+		got := MapSlice(tier.Choices, func(c *proto.TalentChoiceResult) choice { return choice{c.Choice, c.Pruned, c.Current} })
+		if !slices.Equal(got, wantTiers[i]) {
+			t.Errorf("tier %d choices = %v, want %v", tier.Tier, got, wantTiers[i])
+		}
+	}
+
+	// Purge is clearly worse than the other three glyphs, Capacitor Totem isn't.
+	if want := []int32{int32(proto.ShamanMajorGlyph_GlyphOfPurge)}; !slices.Equal(result.PrunedMajorGlyphs, want) {
+		t.Errorf("pruned major glyphs = %v, want %v", result.PrunedMajorGlyphs, want)
+	}
+	if len(result.GlyphSets) != 4 {
+		t.Fatalf("got %d glyph sets, want 4", len(result.GlyphSets))
+	}
+	best := result.GlyphSets[0]
+	wantBest := []int32{
+		int32(proto.ShamanMajorGlyph_GlyphOfUnstableEarth),
+		int32(proto.ShamanMajorGlyph_GlyphOfChainLightning),
+		int32(proto.ShamanMajorGlyph_GlyphOfSpiritWalk),
+	}
+	if !slices.Equal(best.MajorGlyphs, wantBest) || best.Dps.Avg != 1060 {
+		t.Errorf("best glyph set = %v with %f DPS, want %v with 1060 DPS", best.MajorGlyphs, best.Dps.Avg, wantBest)
+	}
+}
@@ -182,6 +182,7 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 			StatDependencyManager: stats.NewStatDependencyManager(),
 			ReactionTime:          time.Millisecond * 1620,
 			enabled:               !options.DisabledAtStart,
+			StartPosition:         Vector2FromProto(options.Position),
 		},
 	}
 	defaultRaidBossLevel := int32(CharacterLevel + 3)
@@ -208,6 +209,8 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 	preset := GetPresetTargetWithID(options.Id)
 	if preset != nil && preset.AI != nil {
 		target.AI = preset.AI()
+	} else if options.Timeline != nil {
+		target.AI = NewTimelineAI()
 	}
 
 	return target
@@ -244,6 +247,10 @@ func (target *Target) Enable(sim *Simulation) {
 	if !target.IsEnabled() {
 		target.enabled = true
 		sim.Encounter.addActiveTarget(target)
+
+		if sim.Log != nil {
+			target.LogEvent(sim, &proto.CombatLogEvent{Type: proto.CombatLogEventType_CombatLogEventTargetEnabled}, "Target enabled")
+		}
 	}
 }
 
@@ -271,6 +278,10 @@ func (target *Target) Disable(sim *Simulation, expireAuras bool) {
 	target.enabled = false
 	sim.Encounter.removeInactiveTarget(target)
 
+	if sim.Log != nil {
+		target.LogEvent(sim, &proto.CombatLogEvent{Type: proto.CombatLogEventType_CombatLogEventTargetDisabled}, "Target disabled")
+	}
+
 	if expireAuras {
 		target.auraTracker.expireAll(sim)
 	}
@@ -0,0 +1,314 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// TimelineAI runs a boss fight described by an EncounterTimeline, for targets without a custom AI.
+// All events are scheduled as pending actions, so they also happen while the target is disabled.
+type TimelineAI struct {
+	Target   *Target
+	Timeline *proto.EncounterTimeline
+
+	phaseStarts []time.Duration
+	phaseEnds   []time.Duration
+
+	// Whether each target of the encounter is enabled at the start of the fight.
+	enabledAtStart []bool
+
+	// Spells, auras and paths prepared for the events.
+	raidDamageSpells map[*proto.TimelineEvent]*Spell
+	raidBuffAuras    map[*proto.TimelineEvent][]*Aura
+	targetPaths      map[*proto.TimelineEvent][]Vector2
+
+	// Scratch list for picking random players.
+	playerPool []*Unit
+}
+
+func NewTimelineAI() TargetAI {
+	return &TimelineAI{}
+}
+
+func (ai *TimelineAI) Initialize(target *Target, config *proto.Target) {
+	ai.Target = target
+	ai.Timeline = config.Timeline
+	ai.raidDamageSpells = make(map[*proto.TimelineEvent]*Spell)
+	ai.raidBuffAuras = make(map[*proto.TimelineEvent][]*Aura)
+	ai.targetPaths = make(map[*proto.TimelineEvent][]Vector2)
+
+	env := target.Env
+	for _, encounterTarget := range env.Encounter.AllTargets {
+		ai.enabledAtStart = append(ai.enabledAtStart, encounterTarget.IsEnabled())
+	}
+
+	checkTarget := func(index int32) {
+		if index < 0 || int(index) >= len(env.Encounter.AllTargets) {
+			panic(fmt.Sprintf("%s timeline: no target with index %d", config.Name, index))
+		}
+	}
+	checkPlayer := func(index int32) {
+		if ai.playerWithIndex(index) == nil {
+			panic(fmt.Sprintf("%s timeline: no player with index %d", config.Name, index))
+		}
+	}
+
+	phases := ai.Timeline.GetPhases()
+	ai.phaseStarts = make([]time.Duration, len(phases))
+	ai.phaseEnds = make([]time.Duration, len(phases))
+	for i, phase := range phases {
+		ai.phaseStarts[i] = DurationFromSeconds(phase.Start)
+		ai.phaseEnds[i] = NeverExpires
+		if i > 0 {
+			if ai.phaseStarts[i] < ai.phaseStarts[i-1] {
+				panic(fmt.Sprintf("%s timeline: phase %q starts before the previous phase", config.Name, phase.Name))
+			}
+			ai.phaseEnds[i-1] = ai.phaseStarts[i]
+		}
+
+		for _, event := range phase.Events {
+			switch e := event.Event.(type) {
+			case *proto.TimelineEvent_EnableTargets:
+				for _, index := range e.EnableTargets.TargetIndices {
+					checkTarget(index)
+				}
+			case *proto.TimelineEvent_DisableTargets:
+				for _, index := range e.DisableTargets.TargetIndices {
+					checkTarget(index)
+				}
+			case *proto.TimelineEvent_TargetSwitch:
+				checkTarget(e.TargetSwitch.TargetIndex)
+				for _, index := range e.TargetSwitch.PlayerIndices {
+					checkPlayer(index)
+				}
+			case *proto.TimelineEvent_RaidDamage:
+				for _, index := range e.RaidDamage.PlayerIndices {
+					checkPlayer(index)
+				}
+				ai.raidDamageSpells[event] = ai.registerRaidDamage(e.RaidDamage, int32(len(ai.raidDamageSpells)+1))
+			case *proto.TimelineEvent_RaidBuff:
+				ai.raidBuffAuras[event] = ai.registerRaidBuff(e.RaidBuff, int32(len(ai.raidBuffAuras)+1))
+			case *proto.TimelineEvent_TargetMove:
+				checkTarget(e.TargetMove.TargetIndex)
+				for _, point := range e.TargetMove.Path {
+					ai.targetPaths[event] = append(ai.targetPaths[event], Vector2FromProto(point))
+				}
+			}
+		}
+	}
+}
+
+func (ai *TimelineAI) registerRaidDamage(config *proto.TimelineRaidDamage, tag int32) *Spell {
+	return ai.Target.RegisterSpell(SpellConfig{
+		ActionID:         ActionID{SpellID: config.SpellId}.WithTag(tag),
+		SpellSchool:      SpellSchoolFromProto(config.SpellSchool),
+		ProcMask:         ProcMaskSpellDamage,
+		Flags:            SpellFlagIgnoreAttackerModifiers,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+			spell.CalcAndDealDamage(sim, target, config.Damage, spell.OutcomeAlwaysHit)
+		},
+	})
+}
+
+func (ai *TimelineAI) registerRaidBuff(config *proto.TimelineRaidBuff, tag int32) []*Aura {
+	actionID := ActionID{SpellID: config.SpellId}.WithTag(tag)
+	duration := NeverExpires
+	if config.Duration > 0 {
+		duration = DurationFromSeconds(config.Duration)
+	}
+	hasteMultiplier := TernaryFloat64(config.HasteMultiplier > 0, config.HasteMultiplier, 1)
+	damageMultiplier := TernaryFloat64(config.DamageMultiplier > 0, config.DamageMultiplier, 1)
+
+	auras := make([]*Aura, 0, len(ai.Target.Env.Raid.AllPlayerUnits))
+	for _, player := range ai.Target.Env.Raid.AllPlayerUnits {
+		auras = append(auras, player.RegisterAura(Aura{
+			Label:    "Timeline Buff-" + actionID.String(),
+			ActionID: actionID,
+			Duration: duration,
+			OnGain: func(aura *Aura, sim *Simulation) {
+				aura.Unit.MultiplyAttackSpeed(sim, hasteMultiplier)
+				aura.Unit.MultiplyCastSpeed(sim, hasteMultiplier)
+				aura.Unit.PseudoStats.DamageDealtMultiplier *= damageMultiplier
+			},
+			OnExpire: func(aura *Aura, sim *Simulation) {
+				aura.Unit.MultiplyAttackSpeed(sim, 1/hasteMultiplier)
+				aura.Unit.MultiplyCastSpeed(sim, 1/hasteMultiplier)
+				aura.Unit.PseudoStats.DamageDealtMultiplier /= damageMultiplier
+			},
+		}))
+	}
+	return auras
+}
+
+func (ai *TimelineAI) Reset(sim *Simulation) {
+	// Targets stay enabled or disabled across iterations, so restore the start of the fight. Enabling
+	// first keeps at least one target active.
+	for i, target := range sim.Encounter.AllTargets {
+		if ai.enabledAtStart[i] && !target.IsEnabled() {
+			target.Enable(sim)
+		}
+	}
+	for i, target := range sim.Encounter.AllTargets {
+		if !ai.enabledAtStart[i] && target.IsEnabled() {
+			target.Disable(sim, true)
+		}
+	}
+
+	for i, phase := range ai.Timeline.GetPhases() {
+		for _, event := range phase.Events {
+			ai.scheduleEvent(sim, event, ai.phaseStarts[i]+DurationFromSeconds(event.At), ai.phaseEnds[i])
+		}
+	}
+}
+
+func (ai *TimelineAI) scheduleEvent(sim *Simulation, event *proto.TimelineEvent, at time.Duration, phaseEnd time.Duration) {
+	if at >= phaseEnd {
+		return
+	}
+
+	pa := sim.GetConsumedPendingActionFromPool()
+	pa.NextActionAt = at
+	pa.Priority = ActionPriorityDOT
+	pa.OnAction = func(sim *Simulation) {
+		ai.doEvent(sim, event)
+		if event.RepeatInterval > 0 {
+			ai.scheduleEvent(sim, event, at+DurationFromSeconds(event.RepeatInterval), phaseEnd)
+		}
+	}
+	sim.AddPendingAction(pa)
+}
+
+func (ai *TimelineAI) doEvent(sim *Simulation, event *proto.TimelineEvent) {
+	switch e := event.Event.(type) {
+	case *proto.TimelineEvent_EnableTargets:
+		for _, index := range e.EnableTargets.TargetIndices {
+			sim.EnableTargetUnit(sim.Encounter.AllTargetUnits[index])
+		}
+	case *proto.TimelineEvent_DisableTargets:
+		for _, index := range e.DisableTargets.TargetIndices {
+			ai.disableTarget(sim, sim.Encounter.AllTargetUnits[index])
+		}
+	case *proto.TimelineEvent_RaidDamage:
+		spell := ai.raidDamageSpells[event]
+		for _, player := range ai.damagedPlayers(sim, e.RaidDamage) {
+			spell.Cast(sim, player)
+		}
+	case *proto.TimelineEvent_Movement:
+		ai.movePlayers(sim, DurationFromSeconds(e.Movement.Duration))
+	case *proto.TimelineEvent_TargetSwitch:
+		ai.switchTarget(sim, e.TargetSwitch)
+	case *proto.TimelineEvent_ExecutePhase:
+		sim.ForceExecutePhase(e.ExecutePhase.Phase)
+	case *proto.TimelineEvent_RaidBuff:
+		for _, aura := range ai.raidBuffAuras[event] {
+			aura.Activate(sim)
+		}
+	case *proto.TimelineEvent_TargetMove:
+		target := sim.Encounter.AllTargetUnits[e.TargetMove.TargetIndex]
+		if e.TargetMove.Start != nil {
+			target.SetPosition(sim, Vector2FromProto(e.TargetMove.Start))
+		}
+		target.MoveAlongPath(sim, ai.targetPaths[event])
+	}
+}
+
+func (ai *TimelineAI) ExecuteCustomRotation(_ *Simulation) {}
+
+func (ai *TimelineAI) playerWithIndex(index int32) *Unit {
+	for _, player := range ai.Target.Env.Raid.AllPlayerUnits {
+		if player.Index == index {
+			return player
+		}
+	}
+	return nil
+}
+
+func (ai *TimelineAI) disableTarget(sim *Simulation, targetUnit *Unit) {
+	if !targetUnit.IsEnabled() {
+		return
+	}
+
+	sim.DisableTargetUnit(targetUnit, true)
+	for _, player := range sim.Raid.AllPlayerUnits {
+		if player.CurrentTarget == targetUnit {
+			player.CurrentTarget = sim.Encounter.ActiveTargetUnits[0]
+		}
+	}
+}
+
+func (ai *TimelineAI) damagedPlayers(sim *Simulation, config *proto.TimelineRaidDamage) []*Unit {
+	ai.playerPool = ai.playerPool[:0]
+	if len(config.PlayerIndices) > 0 {
+		for _, index := range config.PlayerIndices {
+			ai.playerPool = append(ai.playerPool, ai.playerWithIndex(index))
+		}
+	} else {
+		ai.playerPool = append(ai.playerPool, sim.Raid.AllPlayerUnits...)
+	}
+
+	numPlayers := len(ai.playerPool)
+	if config.NumPlayers > 0 && int(config.NumPlayers) < numPlayers {
+		numPlayers = int(config.NumPlayers)
+		for i := 0; i < numPlayers; i++ {
+			j := i + int(sim.RandomFloat("Timeline Raid Damage")*float64(len(ai.playerPool)-i))
+			ai.playerPool[i], ai.playerPool[j] = ai.playerPool[j], ai.playerPool[i]
+		}
+	}
+	return ai.playerPool[:numPlayers]
+}
+
+// Players finish their current cast before moving, like the Movement preset.
+func (ai *TimelineAI) movePlayers(sim *Simulation, duration time.Duration) {
+	for _, player := range sim.Raid.AllPlayerUnits {
+		if player.Hardcast.Expires > sim.CurrentTime && !player.Hardcast.CanMove {
+			pa := sim.GetConsumedPendingActionFromPool()
+			pa.NextActionAt = player.Hardcast.Expires
+			pa.Priority = ActionPriorityPrePull + 1
+			pa.OnAction = func(sim *Simulation) {
+				player.MoveDuration(duration, sim)
+			}
+			sim.AddPendingAction(pa)
+		} else {
+			player.MoveDuration(duration, sim)
+		}
+	}
+}
+
+func (ai *TimelineAI) switchTarget(sim *Simulation, config *proto.TimelineTargetSwitch) {
+	newTarget := sim.Encounter.AllTargetUnits[config.TargetIndex]
+	if !newTarget.IsEnabled() {
+		return
+	}
+
+	var players []*Unit
+	if len(config.PlayerIndices) > 0 {
+		for _, index := range config.PlayerIndices {
+			players = append(players, ai.playerWithIndex(index))
+		}
+	} else {
+		players = sim.Raid.AllPlayerUnits
+	}
+
+	for _, player := range players {
+		previousTarget := player.CurrentTarget
+		player.CurrentTarget = newTarget
+		if config.Duration <= 0 || previousTarget == nil || previousTarget == newTarget {
+			continue
+		}
+
+		pa := sim.GetConsumedPendingActionFromPool()
+		pa.NextActionAt = sim.CurrentTime + DurationFromSeconds(config.Duration)
+		pa.Priority = ActionPriorityDOT
+		pa.OnAction = func(sim *Simulation) {
+			// Stay on the add if the boss went away in the meantime.
+			if player.CurrentTarget == newTarget && previousTarget.IsEnabled() {
+				player.CurrentTarget = previousTarget
+			}
+		}
+		sim.AddPendingAction(pa)
+	}
+}
@@ -0,0 +1,172 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+func setupTimelineSim(timeline *proto.EncounterTimeline) *Simulation {
+	sim := NewSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{
+			RandomSeed: 100,
+		},
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				{
+					Players: []*proto.Player{
+						{
+							Name:      "Caster",
+							Class:     proto.Class_ClassShaman,
+							Buffs:     &proto.IndividualBuffs{},
+							Spec:      &proto.Player_ElementalShaman{},
+							Equipment: &proto.EquipmentSpec{},
+						},
+					},
+					Buffs: &proto.PartyBuffs{},
+				},
+			},
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "boss", Level: 93, MobType: proto.MobType_MobTypeMechanical, Timeline: timeline},
+				{Name: "add", Level: 92, MobType: proto.MobType_MobTypeMechanical, DisabledAtStart: true},
+			},
+			Duration: 60,
+		},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+
+	return sim
+}
+
+func stepTimelineSimUntil(sim *Simulation, at time.Duration) {
+	for sim.CurrentTime < at {
+		if sim.Step() {
+			return
+		}
+	}
+}
+
+func TestTimelineAI(t *testing.T) {
+	sim := setupTimelineSim(&proto.EncounterTimeline{
+		Phases: []*proto.TimelinePhase{
+			{
+				Name: "Adds",
+				Events: []*proto.TimelineEvent{
+					{At: 5, Event: &proto.TimelineEvent_EnableTargets{EnableTargets: &proto.TimelineTargets{TargetIndices: []int32{1}}}},
+					{At: 5, Event: &proto.TimelineEvent_TargetSwitch{TargetSwitch: &proto.TimelineTargetSwitch{TargetIndex: 1, Duration: 5}}},
+					{At: 8, RepeatInterval: 2, Event: &proto.TimelineEvent_RaidDamage{RaidDamage: &proto.TimelineRaidDamage{SpellId: 1234, Damage: 1000}}},
+					{At: 15, Event: &proto.TimelineEvent_DisableTargets{DisableTargets: &proto.TimelineTargets{TargetIndices: []int32{1}}}},
+				},
+			},
+			{
+				Name:  "Burn",
+				Start: 20,
+				Events: []*proto.TimelineEvent{
+					{Event: &proto.TimelineEvent_ExecutePhase{ExecutePhase: &proto.TimelineExecutePhase{Phase: 35}}},
+					{Event: &proto.TimelineEvent_RaidBuff{RaidBuff: &proto.TimelineRaidBuff{SpellId: 5678, Duration: 10, HasteMultiplier: 1.5, DamageMultiplier: 2}}},
+				},
+			},
+		},
+	})
+
+	boss := sim.Encounter.AllTargetUnits[0]
+	add := sim.Encounter.AllTargetUnits[1]
+	player := sim.Raid.AllPlayerUnits[0]
+	if _, ok := sim.Encounter.AllTargets[0].AI.(*TimelineAI); !ok {
+		t.Fatalf("Expected the timeline AI on the boss, got %T", sim.Encounter.AllTargets[0].AI)
+	}
+
+	stepTimelineSimUntil(sim, time.Second*6)
+	if !add.IsEnabled() || player.CurrentTarget != add {
+		t.Fatalf("Expected the player on the enabled add at 6s, add enabled: %t", add.IsEnabled())
+	}
+
+	// The next iteration starts without the add.
+	sim.Cleanup()
+	sim.Reset()
+	if add.IsEnabled() || player.CurrentTarget != boss {
+		t.Fatalf("Expected the add to be disabled after a reset")
+	}
+
+	stepTimelineSimUntil(sim, time.Second*11)
+	if player.CurrentTarget != boss {
+		t.Fatalf("Expected the player back on the boss at 11s")
+	}
+
+	stepTimelineSimUntil(sim, time.Second*16)
+	if add.IsEnabled() {
+		t.Fatalf("Expected the add to be disabled at 16s")
+	}
+
+	damageMultiplier := player.PseudoStats.DamageDealtMultiplier
+	castSpeed := player.PseudoStats.CastSpeedMultiplier
+	if sim.IsExecutePhase35() {
+		t.Fatalf("Expected no execute phase before the burn phase")
+	}
+
+	stepTimelineSimUntil(sim, time.Second*21)
+	if !sim.IsExecutePhase35() || sim.IsExecutePhase25() {
+		t.Fatalf("Expected the 35%% execute phase at 21s")
+	}
+	if player.PseudoStats.DamageDealtMultiplier != damageMultiplier*2 || player.PseudoStats.CastSpeedMultiplier != castSpeed*1.5 {
+		t.Fatalf("Expected the raid buff at 21s, damage multiplier %0.2f, cast speed %0.2f", player.PseudoStats.DamageDealtMultiplier, player.PseudoStats.CastSpeedMultiplier)
+	}
+
+	// Repeating raid damage stops at the end of its phase: 8, 10, 12, 14, 16 and 18s.
+	raidDamage := boss.GetSpell(ActionID{SpellID: 1234}.WithTag(1))
+	if casts := raidDamage.SpellMetrics[sim.Raid.AllPlayerUnits[0].UnitIndex].Casts; casts != 6 {
+		t.Fatalf("Expected 6 raid damage casts, got %d", casts)
+	}
+
+	stepTimelineSimUntil(sim, time.Second*31)
+	if player.PseudoStats.DamageDealtMultiplier != damageMultiplier {
+		t.Fatalf("Expected the raid buff to expire at 30s")
+	}
+}
+
+func TestTimelineAIRandomRaidDamage(t *testing.T) {
+	sim := setupTimelineSim(&proto.EncounterTimeline{
+		Phases: []*proto.TimelinePhase{
+			{
+				Events: []*proto.TimelineEvent{
+					{At: 1, Event: &proto.TimelineEvent_RaidDamage{RaidDamage: &proto.TimelineRaidDamage{SpellId: 1234, Damage: 1000, NumPlayers: 3}}},
+				},
+			},
+		},
+	})
+
+	stepTimelineSimUntil(sim, time.Second*2)
+
+	// Only one player in the raid, so it is hit once.
+	raidDamage := sim.Encounter.AllTargetUnits[0].GetSpell(ActionID{SpellID: 1234}.WithTag(1))
+	if casts := raidDamage.SpellMetrics[sim.Raid.AllPlayerUnits[0].UnitIndex].Casts; casts != 1 {
+		t.Fatalf("Expected 1 raid damage cast, got %d", casts)
+	}
+}
+
+func TestForceExecutePhase(t *testing.T) {
+	sim := SetupFakeSim()
+
+	var phases []int32
+	sim.RegisterExecutePhaseCallback(func(_ *Simulation, phase int32) {
+		phases = append(phases, phase)
+	})
+
+	sim.ForceExecutePhase(45)
+	sim.ForceExecutePhase(90)
+	sim.ForceExecutePhase(0)
+
+	expected := []int32{90, 45, 35, 25, 20}
+	if len(phases) != len(expected) {
+		t.Fatalf("Expected execute phases %v, got %v", expected, phases)
+	}
+	for i := range expected {
+		if phases[i] != expected[i] {
+			t.Fatalf("Expected execute phases %v, got %v", expected, phases)
+		}
+	}
+}
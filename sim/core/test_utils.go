@@ -218,6 +218,11 @@ func RaidBenchmark(b *testing.B, rsr *proto.RaidSimRequest) {
 }
 
 func GetAplRotation(dir string, file string) RotationCombo {
+	// Prefer the text format if present.
+	if data, err := os.ReadFile(dir + "/" + file + ".apl"); err == nil {
+		return RotationCombo{Label: file, Rotation: APLRotationFromTextString(string(data))}
+	}
+
 	filePath := dir + "/" + file + ".apl.json"
 	data, err := os.ReadFile(filePath)
 	if err != nil {
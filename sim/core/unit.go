@@ -65,6 +65,9 @@ type Unit struct {
 	// Amount of time following a post-GCD channel tick, to when the next action can be performed.
 	ChannelClipDelay time.Duration
 
+	// Latency, missed GCDs and slow reactions of an imperfect human player, nil for perfect play.
+	humanError *humanErrorModel
+
 	// How far this unit is from its target(s). Measured in yards, this is used
 	// for calculating spell travel time for certain spells.
 	StartDistanceFromTarget float64
@@ -75,6 +78,12 @@ type Unit struct {
 	moveSpell               *Spell
 	movementAction          *MovementAction
 
+	// Where an enemy stands, in yards. Players and pets stand relative to their target instead, see
+	// GetPosition.
+	StartPosition Vector2
+	position      Vector2
+	pathMovement  *pathMovement
+
 	// Environment in which this Unit exists. This will be nil until after the
 	// construction phase.
 	Env *Environment
@@ -734,6 +743,11 @@ func (unit *Unit) reset(sim *Simulation, _ Agent) {
 	unit.ChanneledDot = nil
 	unit.QueuedSpell = nil
 	unit.DistanceFromTarget = unit.StartDistanceFromTarget
+	unit.position = unit.StartPosition
+	unit.pathMovement = nil
+	if unit.humanError != nil {
+		unit.humanError.reset()
+	}
 	unit.Metrics.reset()
 	unit.ResetStatDeps()
 	unit.statsWithoutDeps = unit.initialStatsWithoutDeps
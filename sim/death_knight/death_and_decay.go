@@ -24,6 +24,7 @@ func (dk *DeathKnight) registerDeathAndDecay() {
 		SpellSchool:    core.SpellSchoolShadow,
 		ProcMask:       core.ProcMaskEmpty, // D&D doesn't seem to proc things in game.
 		ClassSpellMask: DeathKnightSpellDeathAndDecay,
+		Radius:         10,
 
 		RuneCost: core.RuneCostOptions{
 			UnholyRuneCost: 1,
@@ -54,7 +55,7 @@ func (dk *DeathKnight) registerDeathAndDecay() {
 			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
 				// DnD recalculates everything on each tick
 				baseDamage := 26 + dot.Spell.MeleeAttackPower()*0.06400000304
-				for _, aoeTarget := range sim.Encounter.ActiveTargetUnits {
+				for _, aoeTarget := range dot.Spell.AreaTargets(sim) {
 					dot.Spell.SpellMetrics[aoeTarget.UnitIndex].Casts++
 					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, baseDamage, dot.Spell.OutcomeMagicHitAndCrit)
 				}
@@ -381,3 +381,14 @@ func (unit *BalanceDruid) NewSolarEnergyMetrics(actionID core.ActionID) *core.Re
 func (unit *BalanceDruid) NewLunarEnergyMetrics(actionID core.ActionID) *core.ResourceMetrics {
 	return unit.Metrics.NewResourceMetrics(actionID, proto.ResourceType_ResourceTypeLunarEnergy)
 }
+
+// Implements core.InteractiveResourceProvider.
+func (moonkin *BalanceDruid) InteractiveResources() []core.InteractiveResource {
+	if !moonkin.HasEclipseBar() {
+		return nil
+	}
+	return []core.InteractiveResource{
+		{Name: "SolarEnergy", Value: func(_ *core.Simulation) float64 { return float64(moonkin.CurrentSolarEnergy()) }},
+		{Name: "LunarEnergy", Value: func(_ *core.Simulation) float64 { return float64(moonkin.CurrentLunarEnergy()) }},
+	}
+}
@@ -25,8 +25,11 @@ func (druid *Druid) registerHurricaneSpell() {
 		BonusCoefficient: HurricaneBonusCoeff,
 
 		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// The storm stays where the channel was placed, even if the target moves out of it.
 			damage := druid.CalcScalingSpellDmg(HurricaneCoeff)
-			spell.CalcAndDealAoeDamage(sim, damage, spell.OutcomeMagicHitAndCrit)
+			for _, aoeTarget := range druid.Hurricane.AreaTargets(sim) {
+				spell.CalcAndDealDamage(sim, aoeTarget, damage, spell.OutcomeMagicHitAndCrit)
+			}
 		},
 	})
 
@@ -36,6 +39,7 @@ func (druid *Druid) registerHurricaneSpell() {
 		ProcMask:       core.ProcMaskSpellDamage,
 		Flags:          core.SpellFlagChanneled | core.SpellFlagAPL,
 		ClassSpellMask: DruidSpellHurricane,
+		Radius:         8,
 
 		ManaCost: core.ManaCostOptions{
 			BaseCostPercent: 50.3,
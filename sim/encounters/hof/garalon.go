@@ -0,0 +1,35 @@
+package hof
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Garalon's legs are killed for Broken Leg, each slowing him and increasing the damage he takes
+// until it regenerates, so the raid keeps switching to them.
+func addGaralon(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{180_000_000, 540_000_000, 270_000_000, 810_000_000})
+		scale := d.DamageScale()
+
+		garalon := timeline.Boss(62164, "Garalon", d, proto.MobType_MobTypeBeast, health, d.Pick(bossMinBaseDamage))
+		garalon.SwingSpeed = 0
+		leg := timeline.Add(63053, "Garalon's Leg", d, proto.MobType_MobTypeBeast, health*0.03)
+
+		timeline.AddBossEncounter(raidPrefix, "Garalon", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Legs", 0,
+					timeline.Repeat(30, timeline.EnableTargets(0, 1)),
+					timeline.Repeat(30, timeline.TargetSwitch(0, 1, 12)),
+					timeline.Repeat(30, timeline.DisableTargets(12, 1)),
+					timeline.Repeat(30, timeline.RaidBuff(12, 122786, 18, 1, 1.15)),
+					// Furious Swipe and Crush: the raid runs out from under him.
+					timeline.Repeat(35, timeline.Movement(25, 3)),
+					timeline.Repeat(35, timeline.RaidDamage(28, 122774, proto.SpellSchool_SpellSchoolPhysical, 120_000*scale, 0)),
+					// Pheromones trail the carrier through the raid.
+					timeline.Repeat(3, timeline.RaidDamage(2, 122835, proto.SpellSchool_SpellSchoolNature, 8_000*scale, 0)),
+				),
+			},
+		}, garalon, leg)
+	}
+}
@@ -0,0 +1,13 @@
+package hof
+
+// Melee damage of the bosses for 10 N, 25 N, 10 H and 25 H.
+var bossMinBaseDamage = [4]float64{210_000, 250_000, 290_000, 375_000}
+
+func Register() {
+	addZorlok("Heart of Fear")
+	addTayak("Heart of Fear")
+	addGaralon("Heart of Fear")
+	addMeljarak("Heart of Fear")
+	addUnsok("Heart of Fear")
+	addShekzeer("Heart of Fear")
+}
@@ -0,0 +1,44 @@
+package hof
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Mel'jarak fights alongside three packs of adds, which are crowd controlled and then killed one
+// pack at a time. When the adds are dead he gains Recklessness and the raid burns him down.
+func addMeljarak(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{90_000_000, 270_000_000, 135_000_000, 405_000_000})
+		addHealth := health * 0.08
+		scale := d.DamageScale()
+
+		meljarak := timeline.Boss(62397, "Wind Lord Mel'jarak", d, proto.MobType_MobTypeHumanoid, health, d.Pick(bossMinBaseDamage))
+		trapper := timeline.Add(62405, "Sra'thik Amber-Trapper", d, proto.MobType_MobTypeHumanoid, addHealth)
+		mender := timeline.Add(62408, "Zar'thik Battle-Mender", d, proto.MobType_MobTypeHumanoid, addHealth)
+		blademaster := timeline.Add(62402, "Kor'thik Elite Blademaster", d, proto.MobType_MobTypeHumanoid, addHealth)
+		for _, add := range []*proto.Target{trapper, mender, blademaster} {
+			add.DisabledAtStart = false
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Wind Lord Mel'jarak", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Adds", 0,
+					timeline.TargetSwitch(0, 2, 0),
+					timeline.DisableTargets(35, 2),
+					timeline.TargetSwitch(36, 1, 0),
+					timeline.DisableTargets(70, 1),
+					timeline.TargetSwitch(71, 3, 0),
+					timeline.DisableTargets(105, 3),
+					// Rain of Blades and Wind Bombs.
+					timeline.Repeat(50, timeline.RaidDamage(30, 122406, proto.SpellSchool_SpellSchoolPhysical, 100_000*scale, 0)),
+					timeline.Repeat(15, timeline.Movement(10, 1.5)),
+				),
+				timeline.Phase("Recklessness", 110,
+					timeline.Repeat(50, timeline.RaidDamage(20, 122406, proto.SpellSchool_SpellSchoolPhysical, 130_000*scale, 0)),
+					timeline.Repeat(15, timeline.Movement(5, 1.5)),
+				),
+			},
+		}, meljarak, trapper, mender, blademaster)
+	}
+}
@@ -0,0 +1,56 @@
+package hof
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Shek'zeer retreats to her chamber at full energy, leaving the raid to Kor'thik Reavers and
+// Set'thik Windblades, then comes back. At 30% she stays for good and casts Sha Energy.
+func addShekzeer(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{117_000_000, 351_000_000, 175_500_000, 526_500_000})
+		scale := d.DamageScale()
+
+		shekzeer := timeline.Boss(62837, "Grand Empress Shek'zeer", d, proto.MobType_MobTypeHumanoid, health, d.Pick(bossMinBaseDamage))
+		reaver := timeline.Add(63591, "Kor'thik Reaver", d, proto.MobType_MobTypeHumanoid, health*0.1)
+		reaver.TankIndex = 0
+		windblade := timeline.Add(63589, "Set'thik Windblade", d, proto.MobType_MobTypeHumanoid, health*0.04)
+
+		empressPhase := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.EnableTargets(0, 0),
+				timeline.DisableTargets(0.5, 1, 2),
+				// Cry of Terror and Eyes of the Empress.
+				timeline.Repeat(25, timeline.RaidDamage(10, 123788, proto.SpellSchool_SpellSchoolShadow, 40_000*scale, 2)),
+				timeline.Repeat(20, timeline.Movement(15, 2)),
+			)
+		}
+		retreatPhase := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.EnableTargets(0, 1, 2),
+				timeline.DisableTargets(0.5, 0),
+				timeline.TargetSwitch(0.5, 2, 0),
+				timeline.Repeat(15, timeline.RaidDamage(5, 125826, proto.SpellSchool_SpellSchoolPhysical, 50_000*scale, 0)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Grand Empress Shek'zeer", d, &proto.EncounterTimeline{
+			FightLength: 540,
+			Phases: []*proto.TimelinePhase{
+				empressPhase("Empress", 0),
+				retreatPhase("Retreat", 150),
+				empressPhase("Empress 2", 200),
+				retreatPhase("Retreat 2", 350),
+				timeline.Phase("Sha Energy", 400,
+					timeline.EnableTargets(0, 0),
+					timeline.DisableTargets(0.5, 1, 2),
+					timeline.ExecutePhase(0, 35),
+					timeline.Repeat(8, timeline.RaidDamage(4, 125451, proto.SpellSchool_SpellSchoolShadow, 45_000*scale, 0)),
+					// Visions of Demise.
+					timeline.Repeat(30, timeline.Movement(20, 3)),
+				),
+			},
+		}, shekzeer, reaver, windblade)
+	}
+}
@@ -0,0 +1,33 @@
+package hof
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Ta'yak is a tank and spank with Tempest Slash tornadoes until 20% health, when Storm Unleashed
+// pushes the raid down the gale winds corridor.
+func addTayak(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{105_000_000, 315_000_000, 157_500_000, 472_500_000})
+		scale := d.DamageScale()
+
+		tayak := timeline.Boss(62543, "Blade Lord Ta'yak", d, proto.MobType_MobTypeHumanoid, health, d.Pick(bossMinBaseDamage))
+
+		timeline.AddBossEncounter(raidPrefix, "Blade Lord Ta'yak", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Blade Lord", 0,
+					timeline.Repeat(15, timeline.Movement(10, 1.5)),
+					timeline.Repeat(30, timeline.RaidDamage(20, 122949, proto.SpellSchool_SpellSchoolPhysical, 250_000*scale, 5)),
+				),
+				timeline.Phase("Storm Unleashed", 240,
+					timeline.ExecutePhase(0, 20),
+					// Crossing to the other end of the corridor.
+					timeline.Movement(0, 8),
+					timeline.Repeat(10, timeline.Movement(10, 2)),
+					timeline.Repeat(2, timeline.RaidDamage(1, 123815, proto.SpellSchool_SpellSchoolNature, 25_000*scale, 0)),
+				),
+			},
+		}, tayak)
+	}
+}
@@ -0,0 +1,43 @@
+package hof
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Un'sok turns raid members into Mutated Constructs, and at 70% health summons the Amber
+// Monstrosity, which the raid kills before burning Un'sok in the 30% phase.
+func addUnsok(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{99_000_000, 297_000_000, 148_500_000, 445_500_000})
+		scale := d.DamageScale()
+
+		unsok := timeline.Boss(62511, "Amber-Shaper Un'sok", d, proto.MobType_MobTypeHumanoid, health, d.Pick(bossMinBaseDamage))
+		monstrosity := timeline.Add(62711, "Amber Monstrosity", d, proto.MobType_MobTypeElemental, health*0.5)
+		monstrosity.TankIndex = 0
+
+		timeline.AddBossEncounter(raidPrefix, "Amber-Shaper Un'sok", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Amber-Shaper", 0,
+					// Parasitic Growth and Amber Explosions from the constructs.
+					timeline.Repeat(20, timeline.RaidDamage(10, 121949, proto.SpellSchool_SpellSchoolNature, 60_000*scale, 1)),
+					timeline.Repeat(50, timeline.RaidDamage(45, 122398, proto.SpellSchool_SpellSchoolNature, 70_000*scale, 0)),
+					timeline.Repeat(25, timeline.Movement(15, 1.5)),
+				),
+				timeline.Phase("Amber Monstrosity", 100,
+					timeline.EnableTargets(0, 1),
+					timeline.TargetSwitch(0, 1, 0),
+					timeline.Repeat(15, timeline.RaidDamage(10, 122413, proto.SpellSchool_SpellSchoolNature, 90_000*scale, 0)),
+					timeline.Repeat(20, timeline.Movement(5, 2)),
+				),
+				timeline.Phase("Concentrated Mutation", 210,
+					timeline.DisableTargets(0, 1),
+					timeline.ExecutePhase(0, 25),
+					// Burning Amber pools everywhere.
+					timeline.Repeat(10, timeline.Movement(5, 1.5)),
+					timeline.Repeat(5, timeline.RaidDamage(2, 122504, proto.SpellSchool_SpellSchoolFire, 25_000*scale, 0)),
+				),
+			},
+		}, unsok, monstrosity)
+	}
+}
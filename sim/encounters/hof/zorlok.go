@@ -0,0 +1,34 @@
+package hof
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Zor'lok flies between platforms until 80% health, so the raid moves with him, then stays on the
+// ground casting all of his abilities. Force and Verve hits everyone outside the noise cancelling
+// zones.
+func addZorlok(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{99_000_000, 297_000_000, 148_500_000, 445_500_000})
+		scale := d.DamageScale()
+
+		zorlok := timeline.Boss(62980, "Imperial Vizier Zor'lok", d, proto.MobType_MobTypeHumanoid, health, d.Pick(bossMinBaseDamage))
+
+		timeline.AddBossEncounter(raidPrefix, "Imperial Vizier Zor'lok", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Platforms", 0,
+					timeline.Repeat(40, timeline.Movement(38, 6)),
+					timeline.Repeat(40, timeline.RaidDamage(30, 122713, proto.SpellSchool_SpellSchoolPhysical, 45_000*scale, 0)),
+					timeline.Repeat(20, timeline.Movement(12, 1.5)),
+				),
+				timeline.Phase("All Abilities", 120,
+					timeline.Repeat(40, timeline.RaidDamage(15, 122713, proto.SpellSchool_SpellSchoolPhysical, 45_000*scale, 0)),
+					// Sonic Rings and Pheromones of Zeal.
+					timeline.Repeat(15, timeline.Movement(8, 2)),
+					timeline.Repeat(20, timeline.RaidDamage(5, 123812, proto.SpellSchool_SpellSchoolNature, 30_000*scale, 0)),
+				),
+			},
+		}, zorlok)
+	}
+}
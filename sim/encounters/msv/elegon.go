@@ -0,0 +1,56 @@
+package msv
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Elegon alternates between Celestial Protector phases, with the raid standing in the Energy Vortex
+// for Touch of the Titans, and Draw Power phases where he is untargetable and Energy Charges are
+// killed before they reach him. After the second Draw Power he stays at 50% and below.
+func addElegon(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{102_000_000, 306_000_000, 153_000_000, 459_000_000})
+		scale := d.DamageScale()
+
+		elegon := timeline.Boss(60410, "Elegon", d, proto.MobType_MobTypeElemental, health, d.Pick(bossMinBaseDamage))
+		protector := timeline.Add(60793, "Celestial Protector", d, proto.MobType_MobTypeElemental, health*0.12)
+		energyCharge := timeline.Add(60913, "Energy Charge", d, proto.MobType_MobTypeElemental, health*0.02)
+
+		protectorPhase := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.EnableTargets(0, 0),
+				timeline.DisableTargets(0.5, 2),
+				// Touch of the Titans stacks up to 10% damage and haste in the vortex.
+				timeline.RaidBuff(5, 117870, 70, 1.1, 1.1),
+				timeline.EnableTargets(10, 1),
+				timeline.TargetSwitch(10, 1, 25),
+				timeline.DisableTargets(35, 1),
+				// Overcharged ticks on everyone holding Touch of the Titans.
+				timeline.Repeat(6, timeline.RaidDamage(8, 117878, proto.SpellSchool_SpellSchoolArcane, 15_000*scale, 0)),
+			)
+		}
+		drawPowerPhase := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.EnableTargets(0, 2),
+				timeline.DisableTargets(0.5, 0, 1),
+				timeline.Repeat(10, timeline.RaidDamage(3, 118018, proto.SpellSchool_SpellSchoolArcane, 70_000*scale, 0)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Elegon", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				protectorPhase("Celestial Protectors", 0),
+				drawPowerPhase("Draw Power", 75),
+				protectorPhase("Celestial Protectors 2", 105),
+				drawPowerPhase("Draw Power 2", 180),
+				timeline.Phase("Radiating Energies", 210,
+					timeline.EnableTargets(0, 0),
+					timeline.DisableTargets(0.5, 2),
+					timeline.ExecutePhase(0, 45),
+					timeline.Repeat(8, timeline.RaidDamage(4, 118310, proto.SpellSchool_SpellSchoolArcane, 35_000*scale, 0)),
+				),
+			},
+		}, elegon, protector, energyCharge)
+	}
+}
@@ -0,0 +1,40 @@
+package msv
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Feng changes spirit at 66% and 33% health: Fists (Lightning Fists and Epicenter), Spear
+// (Wildfire Spark and Arcane Velocity), then Shield (Siphoning Shield soul fragments).
+func addFeng(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{92_700_000, 278_000_000, 139_000_000, 417_000_000})
+		scale := d.DamageScale()
+
+		feng := timeline.Boss(60009, "Feng the Accursed", d, proto.MobType_MobTypeHumanoid, health, d.Pick(bossMinBaseDamage))
+		soulFragment := timeline.Add(60781, "Soul Fragment", d, proto.MobType_MobTypeUndead, health*0.02)
+
+		timeline.AddBossEncounter(raidPrefix, "Feng the Accursed", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Spirit of the Fist", 0,
+					timeline.Repeat(12, timeline.RaidDamage(8, 116157, proto.SpellSchool_SpellSchoolNature, 100_000*scale, 3)),
+					// Everyone runs away from Epicenter for its whole channel.
+					timeline.Repeat(30, timeline.Movement(25, 3)),
+					timeline.Repeat(30, timeline.RaidDamage(28, 116018, proto.SpellSchool_SpellSchoolNature, 60_000*scale, 0)),
+				),
+				timeline.Phase("Spirit of the Spear", 110,
+					timeline.Repeat(15, timeline.Movement(5, 2)),
+					timeline.Repeat(30, timeline.RaidDamage(15, 116364, proto.SpellSchool_SpellSchoolArcane, 120_000*scale, 0)),
+				),
+				timeline.Phase("Spirit of the Shield", 220,
+					timeline.ExecutePhase(0, 35),
+					// Siphoning Shield spawns soul fragments which the raid kills before they reach it.
+					timeline.Repeat(40, timeline.EnableTargets(10, 1)),
+					timeline.Repeat(40, timeline.TargetSwitch(10, 1, 8)),
+					timeline.Repeat(40, timeline.DisableTargets(18, 1)),
+				),
+			},
+		}, feng, soulFragment)
+	}
+}
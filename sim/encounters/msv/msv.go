@@ -1,5 +1,14 @@
 package msv
 
+// Melee damage of the bosses without a custom AI, for 10 N, 25 N, 10 H and 25 H. The 25 H value
+// matches Gara'jal.
+var bossMinBaseDamage = [4]float64{190_000, 225_000, 260_000, 337_865}
+
 func Register() {
+	addStoneGuard("Mogu'shan Vaults")
+	addFeng("Mogu'shan Vaults")
 	addGarajal("Mogu'shan Vaults")
+	addSpiritKings("Mogu'shan Vaults")
+	addElegon("Mogu'shan Vaults")
+	addWillOfTheEmperor("Mogu'shan Vaults")
 }
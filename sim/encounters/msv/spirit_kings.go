@@ -0,0 +1,65 @@
+package msv
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// The kings come in one at a time, Qiang, Subetai, Zian and Meng, each retreating when the next
+// one arrives. Zian summons Undying Shadows which the raid kills.
+func addSpiritKings(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{46_400_000, 139_000_000, 69_500_000, 208_500_000})
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+		scale := d.DamageScale()
+
+		kings := []*proto.Target{
+			timeline.Boss(60709, "Qiang the Merciless", d, proto.MobType_MobTypeUndead, health, minBaseDamage),
+			timeline.Boss(60710, "Subetai the Swift", d, proto.MobType_MobTypeUndead, health, minBaseDamage),
+			timeline.Boss(60701, "Zian of the Endless Shadow", d, proto.MobType_MobTypeUndead, health, minBaseDamage),
+			timeline.Boss(60708, "Meng the Demented", d, proto.MobType_MobTypeUndead, health, minBaseDamage),
+		}
+		for _, king := range kings[1:] {
+			king.DisabledAtStart = true
+		}
+		undyingShadow := timeline.Add(60731, "Undying Shadow", d, proto.MobType_MobTypeUndead, health*0.05)
+
+		// On heroic the retreating king stays in the fight for a few seconds, which is cleaved.
+		overlap := 0.5
+		if d.Heroic {
+			overlap = 10
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "The Spirit Kings", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Qiang", 0,
+					// Flanking Orders.
+					timeline.Repeat(40, timeline.Movement(20, 2)),
+					timeline.Repeat(40, timeline.RaidDamage(20, 117910, proto.SpellSchool_SpellSchoolPhysical, 150_000*scale, 2)),
+				),
+				timeline.Phase("Subetai", 75,
+					timeline.EnableTargets(0, 1),
+					timeline.DisableTargets(overlap, 0),
+					// Pillage and Volley.
+					timeline.Repeat(30, timeline.Movement(10, 2)),
+					timeline.Repeat(30, timeline.RaidDamage(20, 118088, proto.SpellSchool_SpellSchoolPhysical, 80_000*scale, 0)),
+				),
+				timeline.Phase("Zian", 150,
+					timeline.EnableTargets(0, 2),
+					timeline.DisableTargets(overlap, 1),
+					timeline.Repeat(30, timeline.EnableTargets(10, 4)),
+					timeline.Repeat(30, timeline.TargetSwitch(10, 4, 8)),
+					timeline.Repeat(30, timeline.DisableTargets(18, 4)),
+					timeline.Repeat(20, timeline.RaidDamage(5, 117628, proto.SpellSchool_SpellSchoolShadow, 70_000*scale, 0)),
+				),
+				timeline.Phase("Meng", 225,
+					timeline.EnableTargets(0, 3),
+					timeline.DisableTargets(overlap, 2),
+					timeline.ExecutePhase(0, 25),
+					// Crazed raises the raid damage taken over time, Cowardice reflects it.
+					timeline.Repeat(15, timeline.RaidDamage(10, 117708, proto.SpellSchool_SpellSchoolShadow, 60_000*scale, 0)),
+				),
+			},
+		}, append(kings, undyingShadow)...)
+	}
+}
@@ -0,0 +1,44 @@
+package msv
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Three of the four guardians are up in 10 player raids, all four in 25. They are tanked together
+// and cleaved down, so the fight is mostly about Overloads and moving out of Jasper Chains and
+// Cobalt Mines.
+func addStoneGuard(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{41_000_000, 103_000_000, 61_500_000, 154_500_000})
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+
+		guardians := []*proto.Target{
+			timeline.Boss(59915, "Jasper Guardian", d, proto.MobType_MobTypeElemental, health, minBaseDamage),
+			timeline.Boss(60043, "Jade Guardian", d, proto.MobType_MobTypeElemental, health, minBaseDamage),
+			timeline.Boss(60047, "Amethyst Guardian", d, proto.MobType_MobTypeElemental, health, minBaseDamage),
+		}
+		if d.RaidSize == 25 {
+			guardians = append(guardians, timeline.Boss(60051, "Cobalt Guardian", d, proto.MobType_MobTypeElemental, health, minBaseDamage))
+		}
+
+		events := []*proto.TimelineEvent{
+			// A guardian reaches full energy and Overloads about once a minute.
+			timeline.Repeat(60, timeline.RaidDamage(55, 115843, proto.SpellSchool_SpellSchoolNature, 180_000*d.DamageScale(), 0)),
+			// Jasper Chains pull linked players together until they run apart.
+			timeline.Repeat(30, timeline.Movement(12, 2)),
+			// Amethyst Pools.
+			timeline.Repeat(20, timeline.Movement(8, 1.5)),
+		}
+		if d.Heroic {
+			// Cobalt Mines are shot into the raid much more often.
+			events = append(events, timeline.Repeat(25, timeline.Movement(18, 1.5)))
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "The Stone Guard", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Guardians", 0, events...),
+			},
+		}, guardians...)
+	}
+}
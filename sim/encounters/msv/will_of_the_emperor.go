@@ -0,0 +1,52 @@
+package msv
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// The fight opens with waves of Emperor's Rage and Emperor's Strength, until Qin-xi and Jan-xi
+// activate after 90 seconds. Adds keep coming while the two bosses are cleaved down.
+func addWillOfTheEmperor(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{70_000_000, 210_000_000, 105_000_000, 315_000_000})
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+		scale := d.DamageScale()
+
+		qinxi := timeline.Boss(60399, "Qin-xi", d, proto.MobType_MobTypeMechanical, health, minBaseDamage)
+		qinxi.DisabledAtStart = true
+		janxi := timeline.Boss(60400, "Jan-xi", d, proto.MobType_MobTypeMechanical, health, minBaseDamage)
+		janxi.DisabledAtStart = true
+		rage := timeline.Add(60396, "Emperor's Rage", d, proto.MobType_MobTypeMechanical, health*0.01)
+		rage.DisabledAtStart = false
+		strength := timeline.Add(60397, "Emperor's Strength", d, proto.MobType_MobTypeMechanical, health*0.03)
+
+		addWaves := []*proto.TimelineEvent{
+			timeline.Repeat(30, timeline.EnableTargets(0, 2)),
+			timeline.Repeat(30, timeline.DisableTargets(25, 2)),
+			timeline.Repeat(30, timeline.EnableTargets(20, 3)),
+			timeline.Repeat(30, timeline.DisableTargets(35, 3)),
+		}
+		bossEvents := []*proto.TimelineEvent{
+			timeline.EnableTargets(0, 0, 1),
+			timeline.TargetSwitch(0, 0, 0),
+			// Energizing Smash and Devastating Arc.
+			timeline.Repeat(20, timeline.Movement(10, 2)),
+			timeline.Repeat(20, timeline.RaidDamage(10, 116550, proto.SpellSchool_SpellSchoolPhysical, 90_000*scale, 0)),
+			timeline.Repeat(30, timeline.EnableTargets(10, 2)),
+			timeline.Repeat(30, timeline.DisableTargets(25, 2)),
+			timeline.DisableTargets(5, 3),
+		}
+		if d.Heroic {
+			// Titan Gas is always on in heroic.
+			bossEvents = append(bossEvents, timeline.Repeat(5, timeline.RaidDamage(1, 116779, proto.SpellSchool_SpellSchoolArcane, 20_000*scale, 0)))
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Will of the Emperor", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Adds", 0, addWaves...),
+				timeline.Phase("Qin-xi and Jan-xi", 90, bossEvents...),
+			},
+		}, qinxi, janxi, rage, strength)
+	}
+}
@@ -7,7 +7,12 @@ import (
 	"github.com/wowsims/mop/sim/encounters/bwd"
 	"github.com/wowsims/mop/sim/encounters/dragonsoul"
 	"github.com/wowsims/mop/sim/encounters/firelands"
+	"github.com/wowsims/mop/sim/encounters/hof"
 	"github.com/wowsims/mop/sim/encounters/msv"
+	"github.com/wowsims/mop/sim/encounters/soo"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+	"github.com/wowsims/mop/sim/encounters/toes"
+	"github.com/wowsims/mop/sim/encounters/tot"
 )
 
 func init() {
@@ -17,6 +22,11 @@ func init() {
 	firelands.Register()
 	dragonsoul.Register()
 	msv.Register()
+	hof.Register()
+	toes.Register()
+	tot.Register()
+	soo.Register()
+	timeline.Register()
 }
 
 func AddSingleTargetBossEncounter(presetTarget *core.PresetTarget) {
@@ -0,0 +1,33 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Earthbreaker Haromm and Wavebinder Kardris share a health pool, so the raid cleaves them together
+// and picks up the totems they drop at 85%, 65%, 50% and 25%.
+func addDarkShamans(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		health := d.Health(130_000_000)
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+
+		haromm := timeline.Boss(71859, "Earthbreaker Haromm", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+		kardris := timeline.Boss(71858, "Wavebinder Kardris", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+
+		timeline.AddBossEncounter(raidPrefix, "Kor'kron Dark Shaman", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Dark Shamans", 0,
+					// Toxic Mist, Foul Stream and Ashen Wall.
+					timeline.Repeat(15, timeline.RaidDamage(5, 144089, proto.SpellSchool_SpellSchoolNature, 25_000*scale, 0)),
+					timeline.Repeat(30, timeline.Movement(20, 2)),
+					// Foul Geysers and Falling Ash after the last totems.
+					timeline.Repeat(20, timeline.Movement(220, 3)),
+					timeline.Repeat(20, timeline.RaidDamage(225, 143973, proto.SpellSchool_SpellSchoolShadow, 70_000*scale, 0)),
+					timeline.ExecutePhase(280, 25),
+				),
+			},
+		}, haromm, kardris)
+	}
+}
@@ -0,0 +1,51 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Rook, He and Sun are fought together and brought down evenly. Each of them enters a Desperate
+// Measures phase at 66% and 33% health, pulling the raid onto the spawned adds, and they are
+// finished together in the end.
+func addFallenProtectors(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		health := d.Health(80_000_000)
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+
+		rook := timeline.Boss(71475, "Rook Stonetoe", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+		he := timeline.Boss(71479, "He Softfoot", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+		sun := timeline.Boss(71480, "Sun Tenderheart", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+		embodiedMisery := timeline.Add(71476, "Embodied Misery", d, proto.MobType_MobTypeHumanoid, health*0.08)
+
+		// The protector in Desperate Measures is immune, so the raid kills its adds.
+		measures := func(at float64, protector int32) []*proto.TimelineEvent {
+			return []*proto.TimelineEvent{
+				timeline.EnableTargets(at, 3),
+				timeline.DisableTargets(at+0.5, protector),
+				timeline.TargetSwitch(at+0.5, 3, 0),
+				timeline.EnableTargets(at+25, protector),
+				timeline.DisableTargets(at+25.5, 3),
+			}
+		}
+
+		events := []*proto.TimelineEvent{
+			timeline.Repeat(20, timeline.Movement(10, 2)),
+			timeline.Repeat(15, timeline.RaidDamage(5, 143962, proto.SpellSchool_SpellSchoolShadow, 40_000*scale, 0)),
+		}
+		events = append(events, measures(70, 0)...)
+		events = append(events, measures(110, 1)...)
+		events = append(events, measures(150, 2)...)
+		events = append(events, measures(230, 0)...)
+		events = append(events, measures(270, 1)...)
+		events = append(events, measures(310, 2)...)
+		events = append(events, timeline.ExecutePhase(340, 25))
+
+		timeline.AddBossEncounter(raidPrefix, "Fallen Protectors", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Fallen Protectors", 0, events...),
+			},
+		}, rook, he, sun, embodiedMisery)
+	}
+}
@@ -0,0 +1,41 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Waves of Dragonmaw adds land on the bay while part of the raid goes up the towers, until the
+// proto-drake is shot down and Galakras is fought on the ground.
+func addGalakras(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(180_000_000)
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+
+		galakras := timeline.Boss(72311, "Galakras", d, proto.MobType_MobTypeDragonkin, bossHealth, minBaseDamage)
+		galakras.DisabledAtStart = true
+		bonecrusher := timeline.Boss(72354, "Dragonmaw Bonecrusher", d, proto.MobType_MobTypeHumanoid, bossHealth*0.05, minBaseDamage*0.5)
+		grunts := timeline.Add(72941, "Dragonmaw Grunt", d, proto.MobType_MobTypeHumanoid, bossHealth*0.01)
+
+		timeline.AddBossEncounter(raidPrefix, "Galakras", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Waves", 0,
+					timeline.Repeat(55, timeline.EnableTargets(10, 1)),
+					timeline.Repeat(55, timeline.TargetSwitch(10, 1, 15)),
+					timeline.Repeat(55, timeline.DisableTargets(25, 1)),
+					// Going up and coming down the towers.
+					timeline.Movement(110, 10),
+					timeline.Movement(160, 10),
+					timeline.Repeat(20, timeline.RaidDamage(10, 146764, proto.SpellSchool_SpellSchoolFire, 25_000*scale, 0)),
+				),
+				timeline.Phase("Galakras", 240,
+					timeline.EnableTargets(0, 2),
+					timeline.DisableTargets(0.5, 0),
+					timeline.Repeat(10, timeline.RaidDamage(5, 146992, proto.SpellSchool_SpellSchoolFire, 55_000*scale, 0)),
+					timeline.Repeat(20, timeline.Movement(10, 2)),
+				),
+			},
+		}, bonecrusher, grunts, galakras)
+	}
+}
@@ -0,0 +1,63 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Garrosh calls Siege Engineers and Warbringers in phase one. At 10% energy he moves into the
+// Realm of Y'Shaarj for each intermission, where the raid fights Minions of Y'Shaarj and the
+// Embodied Despair and Doubt without him. Phases three and four, as Garrosh becomes Empowered,
+// are burned down with heavy raid damage.
+func addGarrosh(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(340_000_000)
+
+		garrosh := timeline.Boss(71865, "Garrosh Hellscream", d, proto.MobType_MobTypeHumanoid, bossHealth, d.Pick(bossMinBaseDamage))
+		warbringer := timeline.Add(71979, "Kor'kron Warbringer", d, proto.MobType_MobTypeHumanoid, bossHealth*0.01)
+		minion := timeline.Add(72272, "Minion of Y'Shaarj", d, proto.MobType_MobTypeDemon, bossHealth*0.015)
+
+		// Garrosh is gone during the intermissions, leaving only the minions to attack.
+		intermission := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.EnableTargets(0, 2),
+				timeline.DisableTargets(0.5, 0),
+				timeline.Movement(0.5, 5),
+				timeline.Repeat(10, timeline.RaidDamage(5, 144954, proto.SpellSchool_SpellSchoolShadow, 35_000*scale, 0)),
+				timeline.EnableTargets(60, 0),
+				timeline.DisableTargets(60.5, 2),
+				timeline.Movement(60.5, 5),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Garrosh Hellscream", d, &proto.EncounterTimeline{
+			FightLength: 540,
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Phase 1", 0,
+					timeline.Repeat(45, timeline.EnableTargets(10, 1)),
+					timeline.Repeat(45, timeline.TargetSwitch(10, 1, 10)),
+					timeline.Repeat(45, timeline.DisableTargets(20, 1)),
+					timeline.Repeat(30, timeline.Movement(25, 2)),
+					timeline.Repeat(20, timeline.RaidDamage(10, 144582, proto.SpellSchool_SpellSchoolPhysical, 30_000*scale, 0)),
+				),
+				timeline.Phase("Phase 2", 120,
+					timeline.Repeat(20, timeline.RaidDamage(10, 144985, proto.SpellSchool_SpellSchoolShadow, 45_000*scale, 0)),
+					timeline.Repeat(30, timeline.Movement(15, 3)),
+				),
+				intermission("Intermission 1", 210),
+				timeline.Phase("Phase 2 Continued", 271,
+					timeline.Repeat(20, timeline.RaidDamage(10, 144985, proto.SpellSchool_SpellSchoolShadow, 45_000*scale, 0)),
+					timeline.Repeat(30, timeline.Movement(15, 3)),
+				),
+				intermission("Intermission 2", 330),
+				timeline.Phase("Phase 3", 391,
+					timeline.ExecutePhase(0, 20),
+					// Whirling Corruption and Empowered Gripping Despair.
+					timeline.Repeat(10, timeline.RaidDamage(5, 145037, proto.SpellSchool_SpellSchoolShadow, 70_000*scale, 0)),
+					timeline.Repeat(25, timeline.Movement(10, 4)),
+				),
+			},
+		}, garrosh, warbringer, minion)
+	}
+}
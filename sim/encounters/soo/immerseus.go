@@ -0,0 +1,51 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Immerseus splits into Sha and Contaminated Puddles whenever Swirl and Sha Bolt have worn him
+// down, and reforms once the puddles reach him. The raid cleaves the Sha Puddles during each split.
+func addImmerseus(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(200_000_000)
+
+		immerseus := timeline.Boss(71543, "Immerseus", d, proto.MobType_MobTypeElemental, bossHealth, d.Pick(bossMinBaseDamage))
+		shaPuddle := timeline.Add(71603, "Sha Puddle", d, proto.MobType_MobTypeElemental, bossHealth*0.02)
+		contaminatedPuddle := timeline.Add(71604, "Contaminated Puddle", d, proto.MobType_MobTypeElemental, bossHealth*0.02)
+
+		// Each split lasts 25 seconds, after 60 seconds of fighting the boss. The puddles splash down at
+		// the edge of the room on either side and make their way back to reform Immerseus.
+		cycle := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.Repeat(20, timeline.RaidDamage(10, 143309, proto.SpellSchool_SpellSchoolShadow, 35_000*scale, 0)),
+				// Swirl.
+				timeline.Repeat(48, timeline.Movement(24, 6)),
+				timeline.EnableTargets(60, 1, 2),
+				timeline.DisableTargets(60.5, 0),
+				timeline.TargetSwitch(60.5, 1, 0),
+				timeline.MoveTarget(60, 1, timeline.Point(-35, 0)),
+				timeline.MoveTarget(60, 2, timeline.Point(35, 0)),
+				timeline.MoveTarget(80, 1, nil, timeline.Point(-3, 0)),
+				timeline.MoveTarget(80, 2, nil, timeline.Point(3, 0)),
+				timeline.EnableTargets(85, 0),
+				timeline.DisableTargets(85.5, 1, 2),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Immerseus", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				cycle("Split 1", 0),
+				cycle("Split 2", 86),
+				cycle("Split 3", 172),
+				timeline.Phase("Final", 258,
+					timeline.ExecutePhase(0, 25),
+					timeline.Repeat(20, timeline.RaidDamage(10, 143309, proto.SpellSchool_SpellSchoolShadow, 35_000*scale, 0)),
+					timeline.Repeat(48, timeline.Movement(24, 6)),
+				),
+			},
+		}, immerseus, shaPuddle, contaminatedPuddle)
+	}
+}
@@ -0,0 +1,44 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// The Iron Juggernaut alternates two minutes of Assault mode, where it takes extra damage while
+// dealing out Flame Vents and mines, with a minute of Siege mode full of Shock Pulses and Cutter
+// Lasers.
+func addIronJuggernaut(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		juggernaut := timeline.Boss(71466, "Iron Juggernaut", d, proto.MobType_MobTypeMechanical, d.Health(250_000_000), d.Pick(bossMinBaseDamage))
+
+		assault := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				// Borer Drills and Crawler Mines.
+				timeline.Repeat(20, timeline.Movement(10, 2)),
+				timeline.Repeat(10, timeline.RaidDamage(5, 144464, proto.SpellSchool_SpellSchoolFire, 20_000*scale, 3)),
+			)
+		}
+		siege := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				// Shock Pulse knocks everyone back, and Cutter Laser chases players around.
+				timeline.Repeat(16, timeline.Movement(5, 4)),
+				timeline.Repeat(16, timeline.RaidDamage(5, 144485, proto.SpellSchool_SpellSchoolNature, 50_000*scale, 0)),
+				timeline.Repeat(10, timeline.Movement(12, 2)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Iron Juggernaut", d, &proto.EncounterTimeline{
+			FightLength: 540,
+			Phases: []*proto.TimelinePhase{
+				assault("Assault 1", 0),
+				siege("Siege 1", 120),
+				assault("Assault 2", 180),
+				siege("Siege 2", 300),
+				assault("Assault 3", 360),
+				siege("Siege 3", 480),
+			},
+		}, juggernaut)
+	}
+}
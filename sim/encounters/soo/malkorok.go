@@ -0,0 +1,36 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Malkorok alternates two minutes of Arcing Smash, Seismic Slam and Breath of Y'Shaarj with Blood
+// Rage, when the raid stacks up and takes heavy Displaced Energy damage.
+func addMalkorok(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		malkorok := timeline.Boss(71454, "Malkorok", d, proto.MobType_MobTypeHumanoid, d.Health(250_000_000), d.Pick(bossMinBaseDamage))
+
+		cycle := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				// Moving out of the Arcing Smash wedges and the Breath.
+				timeline.Repeat(20, timeline.Movement(12, 2.5)),
+				timeline.Repeat(20, timeline.RaidDamage(15, 142851, proto.SpellSchool_SpellSchoolShadow, 35_000*scale, 0)),
+				// Blood Rage.
+				timeline.Movement(120, 3),
+				timeline.Repeat(3, timeline.RaidDamage(123, 142913, proto.SpellSchool_SpellSchoolShadow, 30_000*scale, 0)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Malkorok", d, &proto.EncounterTimeline{
+			FightLength: 510,
+			Phases: []*proto.TimelinePhase{
+				cycle("Cycle 1", 0),
+				cycle("Cycle 2", 142),
+				cycle("Cycle 3", 284),
+				cycle("Cycle 4", 426),
+			},
+		}, malkorok)
+	}
+}
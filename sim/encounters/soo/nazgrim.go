@@ -0,0 +1,32 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// General Nazgrim cycles through his stances, calling Kor'kron reinforcements every 45 seconds which
+// damage dealers swap to and kill.
+func addNazgrim(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(230_000_000)
+
+		nazgrim := timeline.Boss(71515, "General Nazgrim", d, proto.MobType_MobTypeHumanoid, bossHealth, d.Pick(bossMinBaseDamage))
+		reinforcements := timeline.Add(71519, "Kor'kron Arcweaver", d, proto.MobType_MobTypeHumanoid, bossHealth*0.03)
+
+		timeline.AddBossEncounter(raidPrefix, "General Nazgrim", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Stances", 0,
+					timeline.Repeat(45, timeline.EnableTargets(30, 1)),
+					timeline.Repeat(45, timeline.TargetSwitch(30, 1, 20)),
+					timeline.Repeat(45, timeline.DisableTargets(50, 1)),
+					// Ravager and Heroic Shockwave.
+					timeline.Repeat(20, timeline.Movement(12, 2)),
+					timeline.Repeat(30, timeline.RaidDamage(15, 143716, proto.SpellSchool_SpellSchoolPhysical, 40_000*scale, 0)),
+					timeline.ExecutePhase(300, 35),
+				),
+			},
+		}, nazgrim, reinforcements)
+	}
+}
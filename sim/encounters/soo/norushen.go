@@ -0,0 +1,38 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Norushen's Amalgam of Corruption is fought while players take turns in the Test of Serenity, and
+// the raid swaps to the Manifestations of Corruption released by the tests. Frayed at 50% spawns
+// more of them and moves the raid around the Residual Corruption.
+func addNorushen(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(160_000_000)
+
+		amalgam := timeline.Boss(72276, "Amalgam of Corruption", d, proto.MobType_MobTypeElemental, bossHealth, d.Pick(bossMinBaseDamage))
+		manifestation := timeline.Add(72264, "Manifestation of Corruption", d, proto.MobType_MobTypeElemental, bossHealth*0.01)
+
+		timeline.AddBossEncounter(raidPrefix, "Norushen", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Amalgam", 0,
+					timeline.Repeat(8, timeline.RaidDamage(4, 144482, proto.SpellSchool_SpellSchoolShadow, 25_000*scale, 0)),
+					timeline.Repeat(40, timeline.EnableTargets(30, 1)),
+					timeline.Repeat(40, timeline.TargetSwitch(30, 1, 8)),
+					timeline.Repeat(40, timeline.DisableTargets(38, 1)),
+				),
+				timeline.Phase("Frayed", 180,
+					timeline.ExecutePhase(0, 45),
+					timeline.Repeat(8, timeline.RaidDamage(4, 144482, proto.SpellSchool_SpellSchoolShadow, 30_000*scale, 0)),
+					timeline.Repeat(20, timeline.EnableTargets(5, 1)),
+					timeline.Repeat(20, timeline.TargetSwitch(5, 1, 5)),
+					timeline.Repeat(20, timeline.DisableTargets(10, 1)),
+					timeline.Repeat(25, timeline.Movement(15, 2)),
+				),
+			},
+		}, amalgam, manifestation)
+	}
+}
@@ -0,0 +1,65 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Three Paragons of the Klaxxi are active at a time, and each one that dies wakes another. The raid
+// kills them in a fixed order starting with Skeer, Rik'kal and Hisek, and picks up the buffs the
+// fallen Paragons leave behind.
+func addParagons(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		health := d.Health(40_000_000)
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+
+		names := []struct {
+			id   int32
+			name string
+		}{
+			{71152, "Skeer the Bloodseeker"},
+			{71158, "Rik'kal the Dissector"},
+			{71153, "Hisek the Swarmkeeper"},
+			{71161, "Kil'ruk the Wind-Reaver"},
+			{71157, "Xaril the Poisoned Mind"},
+			{71156, "Kaz'tik the Manipulator"},
+			{71155, "Korven the Prime"},
+			{71160, "Iyyokuk the Lucid"},
+			{71154, "Ka'roz the Locust"},
+		}
+		paragons := make([]*proto.Target, len(names))
+		for i, paragon := range names {
+			paragons[i] = timeline.Boss(paragon.id, paragon.name, d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+			paragons[i].DisabledAtStart = i >= 3
+		}
+
+		// Every 50 seconds a Paragon dies and the next one joins.
+		var events []*proto.TimelineEvent
+		for i := int32(0); i < int32(len(paragons)); i++ {
+			at := 50 * float64(i+1)
+			if int(i)+3 < len(paragons) {
+				events = append(events, timeline.EnableTargets(at, i+3))
+			}
+			if int(i)+1 < len(paragons) {
+				events = append(events,
+					timeline.DisableTargets(at+0.5, i),
+					timeline.TargetSwitch(at+0.5, i+1, 0),
+				)
+			}
+		}
+		events = append(events,
+			timeline.Repeat(20, timeline.Movement(10, 2)),
+			timeline.Repeat(15, timeline.RaidDamage(5, 143701, proto.SpellSchool_SpellSchoolNature, 30_000*scale, 0)),
+			// The buff from the Paragon that just died.
+			timeline.Repeat(50, timeline.RaidBuff(52, 143666, 30, 1, 1.1)),
+			timeline.ExecutePhase(400, 20),
+		)
+
+		timeline.AddBossEncounter(raidPrefix, "Paragons of the Klaxxi", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Paragons", 0, events...),
+			},
+		}, paragons...)
+	}
+}
@@ -0,0 +1,37 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// The Sha of Pride raises raid wide damage with each Swelling Pride, and spawns a Manifestation of
+// Pride for the raid to kill after every other one. Unleashed at 30% turns the fight into a burn.
+func addShaOfPride(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(280_000_000)
+
+		sha := timeline.Boss(71734, "Sha of Pride", d, proto.MobType_MobTypeElemental, bossHealth, d.Pick(bossMinBaseDamage))
+		manifestation := timeline.Add(71946, "Manifestation of Pride", d, proto.MobType_MobTypeElemental, bossHealth*0.02)
+
+		timeline.AddBossEncounter(raidPrefix, "Sha of Pride", d, &proto.EncounterTimeline{
+			FightLength: 420,
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Swelling Pride", 0,
+					timeline.Repeat(76, timeline.RaidDamage(75, 144400, proto.SpellSchool_SpellSchoolShadow, 60_000*scale, 0)),
+					// Reaching Attack and Mark of Arrogance positioning.
+					timeline.Repeat(25, timeline.Movement(12, 2)),
+					timeline.Repeat(76, timeline.EnableTargets(60, 1)),
+					timeline.Repeat(76, timeline.TargetSwitch(60, 1, 10)),
+					timeline.Repeat(76, timeline.DisableTargets(70, 1)),
+				),
+				timeline.Phase("Unleashed", 330,
+					timeline.ExecutePhase(0, 25),
+					timeline.Repeat(10, timeline.RaidDamage(0, 144836, proto.SpellSchool_SpellSchoolShadow, 50_000*scale, 0)),
+					timeline.Repeat(25, timeline.Movement(12, 2)),
+				),
+			},
+		}, sha, manifestation)
+	}
+}
@@ -0,0 +1,32 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Siegecrafter Blackfuse sends an Automated Shredder every minute which the raid swaps to, while
+// a few players work the conveyor belt and the rest dodge the weapons it assembles.
+func addSiegecrafter(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(230_000_000)
+
+		blackfuse := timeline.Boss(71504, "Siegecrafter Blackfuse", d, proto.MobType_MobTypeHumanoid, bossHealth, d.Pick(bossMinBaseDamage))
+		shredder := timeline.Add(71591, "Automated Shredder", d, proto.MobType_MobTypeMechanical, bossHealth*0.06)
+
+		timeline.AddBossEncounter(raidPrefix, "Siegecrafter Blackfuse", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Siegecrafter Blackfuse", 0,
+					timeline.Repeat(60, timeline.EnableTargets(35, 1)),
+					timeline.Repeat(60, timeline.TargetSwitch(35, 1, 20)),
+					timeline.Repeat(60, timeline.DisableTargets(55, 1)),
+					// Laser Turrets, Electromagnets and Crawler Mines.
+					timeline.Repeat(40, timeline.Movement(10, 4)),
+					timeline.Repeat(15, timeline.RaidDamage(8, 144210, proto.SpellSchool_SpellSchoolFire, 30_000*scale, 0)),
+					timeline.ExecutePhase(330, 35),
+				),
+			},
+		}, blackfuse, shredder)
+	}
+}
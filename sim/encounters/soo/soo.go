@@ -0,0 +1,20 @@
+package soo
+
+// Melee damage of the bosses for 10 N, 25 N, 10 H and 25 H.
+var bossMinBaseDamage = [4]float64{280_000, 330_000, 400_000, 500_000}
+
+func Register() {
+	addImmerseus("Siege of Orgrimmar")
+	addFallenProtectors("Siege of Orgrimmar")
+	addNorushen("Siege of Orgrimmar")
+	addShaOfPride("Siege of Orgrimmar")
+	addGalakras("Siege of Orgrimmar")
+	addIronJuggernaut("Siege of Orgrimmar")
+	addDarkShamans("Siege of Orgrimmar")
+	addNazgrim("Siege of Orgrimmar")
+	addMalkorok("Siege of Orgrimmar")
+	addThok("Siege of Orgrimmar")
+	addSiegecrafter("Siege of Orgrimmar")
+	addParagons("Siege of Orgrimmar")
+	addGarrosh("Siege of Orgrimmar")
+}
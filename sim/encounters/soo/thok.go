@@ -0,0 +1,49 @@
+package soo
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Thok builds up Power with each Deafening Screech, which interrupts casters more often as the
+// fight goes on, until he goes into Blood Frenzy and chases a player while the raid keeps
+// attacking. Each frenzy ends when a jailer's key frees a prisoner.
+func addThok(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		thok := timeline.Boss(71529, "Thok the Bloodthirsty", d, proto.MobType_MobTypeBeast, d.Health(280_000_000), d.Pick(bossMinBaseDamage))
+
+		screech := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.Repeat(12, timeline.RaidDamage(10, 143343, proto.SpellSchool_SpellSchoolPhysical, 30_000*scale, 0)),
+				timeline.Repeat(6, timeline.RaidDamage(45, 143343, proto.SpellSchool_SpellSchoolPhysical, 30_000*scale, 0)),
+				// Each Screech interrupts casting.
+				timeline.Repeat(12, timeline.Movement(10, 0.5)),
+				timeline.Repeat(6, timeline.Movement(45, 0.5)),
+			)
+		}
+		frenzy := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				// Kiting Thok across the room.
+				timeline.Repeat(10, timeline.Movement(2, 3)),
+				timeline.Repeat(3, timeline.RaidDamage(0, 143428, proto.SpellSchool_SpellSchoolFire, 15_000*scale, 0)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Thok the Bloodthirsty", d, &proto.EncounterTimeline{
+			FightLength: 450,
+			Phases: []*proto.TimelinePhase{
+				screech("Deafening Screech 1", 0),
+				frenzy("Blood Frenzy 1", 75),
+				screech("Deafening Screech 2", 140),
+				frenzy("Blood Frenzy 2", 215),
+				screech("Deafening Screech 3", 280),
+				timeline.Phase("Blood Frenzy 3", 355,
+					timeline.ExecutePhase(0, 35),
+					timeline.Repeat(10, timeline.Movement(2, 3)),
+					timeline.Repeat(3, timeline.RaidDamage(0, 143428, proto.SpellSchool_SpellSchoolFire, 15_000*scale, 0)),
+				),
+			},
+		}, thok)
+	}
+}
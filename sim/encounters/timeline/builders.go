@@ -0,0 +1,186 @@
+package timeline
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+// Helpers for raid packages that describe their bosses as timelines in Go rather than data files.
+
+const bossArmor = 24835
+
+type Difficulty struct {
+	RaidSize int32
+	Heroic   bool
+}
+
+// The difficulties of a raid tier, in the order of the value tables passed to Pick.
+var Difficulties = []Difficulty{
+	{RaidSize: 10},
+	{RaidSize: 25},
+	{RaidSize: 10, Heroic: true},
+	{RaidSize: 25, Heroic: true},
+}
+
+// Suffix for preset names, e.g. "25 H" like the hand-written heroic presets.
+func (d Difficulty) Suffix() string {
+	if d.Heroic {
+		return fmt.Sprintf("%d H", d.RaidSize)
+	}
+	return fmt.Sprintf("%d N", d.RaidSize)
+}
+
+// Pick returns the value for this difficulty from a 10 N, 25 N, 10 H, 25 H table.
+func (d Difficulty) Pick(values [4]float64) float64 {
+	index := 0
+	if d.RaidSize == 25 {
+		index++
+	}
+	if d.Heroic {
+		index += 2
+	}
+	return values[index]
+}
+
+// Health scales 10 N health to the difficulty: 25 player raids have three times the health, and
+// heroic half again as much.
+func (d Difficulty) Health(normal10 float64) float64 {
+	return normal10 * d.Pick([4]float64{1, 3, 1.5, 4.5})
+}
+
+// DamageScale multiplies 10 N ability damage for the difficulty. Boss abilities hit about 10%
+// harder in 25 player raids and 40% harder on heroic.
+func (d Difficulty) DamageScale() float64 {
+	return d.Pick([4]float64{1, 1.1, 1.4, 1.5})
+}
+
+// Boss returns a tanked level 93 target with a melee attack, active from the pull.
+func Boss(id int32, name string, d Difficulty, mobType proto.MobType, health float64, minBaseDamage float64) *proto.Target {
+	target := Add(id, name, d, mobType, health)
+	target.Level = 93
+	target.TankIndex = 0
+	target.DisabledAtStart = false
+	target.SpellSchool = proto.SpellSchool_SpellSchoolPhysical
+	target.SwingSpeed = 1.5
+	target.MinBaseDamage = minBaseDamage
+	target.DamageSpread = 0.4
+	return target
+}
+
+// Add returns an untanked level 92 target which is disabled until the timeline enables it.
+func Add(id int32, name string, d Difficulty, mobType proto.MobType, health float64) *proto.Target {
+	return &proto.Target{
+		Id:      id,
+		Name:    name + " " + d.Suffix(),
+		Level:   92,
+		MobType: mobType,
+		Stats: stats.Stats{
+			stats.Health: health,
+			stats.Armor:  bossArmor,
+		}.ToProtoArray(),
+		TankIndex:       -1,
+		DisabledAtStart: true,
+		TargetInputs:    []*proto.TargetInput{},
+	}
+}
+
+func Phase(name string, start float64, events ...*proto.TimelineEvent) *proto.TimelinePhase {
+	return &proto.TimelinePhase{
+		Name:   name,
+		Start:  start,
+		Events: events,
+	}
+}
+
+// Repeat makes the event happen every interval seconds until the end of its phase.
+func Repeat(interval float64, event *proto.TimelineEvent) *proto.TimelineEvent {
+	event.RepeatInterval = interval
+	return event
+}
+
+func EnableTargets(at float64, targetIndices ...int32) *proto.TimelineEvent {
+	return &proto.TimelineEvent{
+		At:    at,
+		Event: &proto.TimelineEvent_EnableTargets{EnableTargets: &proto.TimelineTargets{TargetIndices: targetIndices}},
+	}
+}
+
+func DisableTargets(at float64, targetIndices ...int32) *proto.TimelineEvent {
+	return &proto.TimelineEvent{
+		At:    at,
+		Event: &proto.TimelineEvent_DisableTargets{DisableTargets: &proto.TimelineTargets{TargetIndices: targetIndices}},
+	}
+}
+
+// RaidDamage hits numPlayers random players, or everyone if numPlayers is 0.
+func RaidDamage(at float64, spellID int32, school proto.SpellSchool, damage float64, numPlayers int32) *proto.TimelineEvent {
+	return &proto.TimelineEvent{
+		At: at,
+		Event: &proto.TimelineEvent_RaidDamage{RaidDamage: &proto.TimelineRaidDamage{
+			SpellId:     spellID,
+			SpellSchool: school,
+			Damage:      damage,
+			NumPlayers:  numPlayers,
+		}},
+	}
+}
+
+func Movement(at float64, duration float64) *proto.TimelineEvent {
+	return &proto.TimelineEvent{
+		At:    at,
+		Event: &proto.TimelineEvent_Movement{Movement: &proto.TimelineMovement{Duration: duration}},
+	}
+}
+
+// TargetSwitch moves every player to the target for duration seconds, or for good if 0.
+func TargetSwitch(at float64, targetIndex int32, duration float64) *proto.TimelineEvent {
+	return &proto.TimelineEvent{
+		At:    at,
+		Event: &proto.TimelineEvent_TargetSwitch{TargetSwitch: &proto.TimelineTargetSwitch{TargetIndex: targetIndex, Duration: duration}},
+	}
+}
+
+func ExecutePhase(at float64, phase int32) *proto.TimelineEvent {
+	return &proto.TimelineEvent{
+		At:    at,
+		Event: &proto.TimelineEvent_ExecutePhase{ExecutePhase: &proto.TimelineExecutePhase{Phase: phase}},
+	}
+}
+
+// RaidBuff buffs every player for duration seconds, or until the end of the fight if 0.
+func RaidBuff(at float64, spellID int32, duration float64, hasteMultiplier float64, damageMultiplier float64) *proto.TimelineEvent {
+	return &proto.TimelineEvent{
+		At: at,
+		Event: &proto.TimelineEvent_RaidBuff{RaidBuff: &proto.TimelineRaidBuff{
+			SpellId:          spellID,
+			Duration:         duration,
+			HasteMultiplier:  hasteMultiplier,
+			DamageMultiplier: damageMultiplier,
+		}},
+	}
+}
+
+// MoveTarget places the target at start, unless it is nil, and makes it walk through the points in order.
+func MoveTarget(at float64, targetIndex int32, start *proto.Vector2, path ...*proto.Vector2) *proto.TimelineEvent {
+	return &proto.TimelineEvent{
+		At:    at,
+		Event: &proto.TimelineEvent_TargetMove{TargetMove: &proto.TimelineTargetMove{TargetIndex: targetIndex, Path: path, Start: start}},
+	}
+}
+
+// Point returns a position in yards, relative to where the boss stands at the pull.
+func Point(x float64, y float64) *proto.Vector2 {
+	return &proto.Vector2{X: x, Y: y}
+}
+
+// AddBossEncounter registers a preset encounter of the targets, with the timeline run by the first.
+func AddBossEncounter(pathPrefix string, name string, d Difficulty, timeline *proto.EncounterTimeline, targets ...*proto.Target) {
+	targets[0].Timeline = timeline
+	AddEncounter(&proto.TimelinePresetEncounter{
+		PathPrefix: pathPrefix,
+		Name:       name + " " + d.Suffix(),
+		Targets:    targets,
+	})
+}
@@ -0,0 +1,57 @@
+// Package timeline registers the preset encounters described by the data files in data/, which
+// are run by core.TimelineAI instead of a hand-written Target AI.
+package timeline
+
+import (
+	"embed"
+	"log"
+	"path"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+//go:embed data/*.json
+var dataFiles embed.FS
+
+func Register() {
+	// ReadDir returns the files sorted by name, so presets are always added in the same order.
+	entries, err := dataFiles.ReadDir("data")
+	if err != nil {
+		log.Fatalf("Failed to read timeline encounters: %s", err)
+	}
+
+	for _, entry := range entries {
+		data, err := dataFiles.ReadFile(path.Join("data", entry.Name()))
+		if err != nil {
+			log.Fatalf("Failed to read timeline encounter %s: %s", entry.Name(), err)
+		}
+
+		encounter := &proto.TimelinePresetEncounter{}
+		if err := protojson.Unmarshal(data, encounter); err != nil {
+			log.Fatalf("Failed to parse timeline encounter %s: %s", entry.Name(), err)
+		}
+		AddEncounter(encounter)
+	}
+}
+
+// AddEncounter registers the targets of the encounter and the encounter itself. Target names must
+// be unique within the path prefix, and the NPC IDs must not be used by a preset with a custom AI.
+func AddEncounter(encounter *proto.TimelinePresetEncounter) {
+	if len(encounter.Targets) == 0 {
+		log.Fatalf("Timeline encounter %s has no targets!", encounter.Name)
+	}
+
+	targetPaths := make([]string, 0, len(encounter.Targets))
+	for _, target := range encounter.Targets {
+		presetTarget := &core.PresetTarget{
+			PathPrefix: encounter.PathPrefix,
+			Config:     target,
+		}
+		core.AddPresetTarget(presetTarget)
+		targetPaths = append(targetPaths, presetTarget.Path())
+	}
+
+	core.AddPresetEncounter(encounter.Name, targetPaths)
+}
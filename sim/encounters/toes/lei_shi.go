@@ -0,0 +1,31 @@
+package toes
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Lei Shi Hides every 45 seconds, and the raid spreads out to find her again. At each 20% of her health
+// she Protects herself, summoning Animated Protectors which the raid kills to break the shield.
+func addLeiShi(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{96_000_000, 288_000_000, 144_000_000, 432_000_000})
+		scale := d.DamageScale()
+
+		leiShi := timeline.Boss(62983, "Lei Shi", d, proto.MobType_MobTypeElemental, health, d.Pick(bossMinBaseDamage))
+		protector := timeline.Add(62995, "Animated Protector", d, proto.MobType_MobTypeElemental, health*0.05)
+
+		timeline.AddBossEncounter(raidPrefix, "Lei Shi", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Lei Shi", 0,
+					timeline.Repeat(45, timeline.Movement(30, 4)),
+					timeline.Repeat(60, timeline.EnableTargets(15, 1)),
+					timeline.Repeat(60, timeline.TargetSwitch(15, 1, 10)),
+					timeline.Repeat(60, timeline.DisableTargets(25, 1)),
+					// Get Away! pushes back everyone who stays near her.
+					timeline.Repeat(20, timeline.RaidDamage(12, 123121, proto.SpellSchool_SpellSchoolFrost, 35_000*scale, 0)),
+				),
+			},
+		}, leiShi, protector)
+	}
+}
@@ -0,0 +1,58 @@
+package toes
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// The three Protectors are killed one at a time, Asani, then Kaolan, then Regail. Each death heals
+// the survivors and empowers them, so the later phases hit harder.
+func addProtectors(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{41_000_000, 123_000_000, 61_500_000, 184_500_000})
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+		scale := d.DamageScale()
+
+		targets := []*proto.Target{
+			timeline.Boss(60586, "Elder Asani", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage),
+			timeline.Boss(60583, "Protector Kaolan", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage),
+			timeline.Boss(60585, "Elder Regail", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage),
+		}
+
+		firstPhase := []*proto.TimelineEvent{
+			timeline.TargetSwitch(0, 0, 0),
+			// Lightning Prison and Corrupted Waters.
+			timeline.Repeat(25, timeline.RaidDamage(15, 111850, proto.SpellSchool_SpellSchoolNature, 60_000*scale, 3)),
+			timeline.Repeat(45, timeline.Movement(30, 2)),
+		}
+		if d.Heroic {
+			// Minions of Fear walk towards the Corrupted Waters and must be killed.
+			targets = append(targets, timeline.Add(60885, "Minion of Fear", d, proto.MobType_MobTypeElemental, health*0.02))
+			firstPhase = append(firstPhase,
+				timeline.Repeat(45, timeline.EnableTargets(20, 3)),
+				timeline.Repeat(45, timeline.TargetSwitch(20, 3, 6)),
+				timeline.Repeat(45, timeline.DisableTargets(26, 3)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Protectors of the Endless", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Three Protectors", 0, firstPhase...),
+				timeline.Phase("Two Protectors", 90,
+					timeline.DisableTargets(0, 0),
+					timeline.TargetSwitch(0, 1, 0),
+					// Defiled Ground under the tank and empowered Lightning Prisons.
+					timeline.Repeat(20, timeline.Movement(10, 1.5)),
+					timeline.Repeat(25, timeline.RaidDamage(10, 111850, proto.SpellSchool_SpellSchoolNature, 75_000*scale, 3)),
+				),
+				timeline.Phase("Last Protector", 170,
+					timeline.DisableTargets(0, 1),
+					timeline.TargetSwitch(0, 2, 0),
+					timeline.ExecutePhase(0, 35),
+					timeline.Repeat(30, timeline.RaidDamage(15, 118077, proto.SpellSchool_SpellSchoolNature, 90_000*scale, 0)),
+					timeline.Repeat(30, timeline.Movement(20, 2)),
+				),
+			},
+		}, targets...)
+	}
+}
@@ -0,0 +1,41 @@
+package toes
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// The raid fights the Sha of Fear from inside the Champion of the Light, with Terror Spawns coming
+// out of the Dread Spawns. Heroic adds the Dread Expanse phase below 66% health, with more
+// movement and Sha Globes.
+func addShaOfFear(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{149_000_000, 447_000_000, 223_500_000, 670_500_000})
+		scale := d.DamageScale()
+
+		sha := timeline.Boss(60999, "Sha of Fear", d, proto.MobType_MobTypeElemental, health, d.Pick(bossMinBaseDamage))
+		terrorSpawn := timeline.Add(61034, "Terror Spawn", d, proto.MobType_MobTypeElemental, health*0.02)
+
+		phases := []*proto.TimelinePhase{
+			timeline.Phase("Champion of the Light", 0,
+				// Breath of Fear for anyone outside the Wall of Light, and Ominous Cackle.
+				timeline.Repeat(33, timeline.RaidDamage(30, 119414, proto.SpellSchool_SpellSchoolShadow, 70_000*scale, 0)),
+				timeline.Repeat(45, timeline.Movement(40, 3)),
+				timeline.Repeat(60, timeline.EnableTargets(20, 1)),
+				timeline.Repeat(60, timeline.TargetSwitch(20, 1, 8)),
+				timeline.Repeat(60, timeline.DisableTargets(28, 1)),
+			),
+		}
+		if d.Heroic {
+			phases = append(phases, timeline.Phase("Dread Expanse", 150,
+				timeline.ExecutePhase(0, 45),
+				timeline.Repeat(10, timeline.Movement(5, 2)),
+				timeline.Repeat(20, timeline.RaidDamage(12, 120672, proto.SpellSchool_SpellSchoolShadow, 50_000*scale, 0)),
+				// Fading Light and Champion of the Light boost the raid's damage.
+				timeline.Repeat(40, timeline.RaidBuff(20, 120629, 15, 1, 1.3)),
+			))
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Sha of Fear", d, &proto.EncounterTimeline{Phases: phases}, sha, terrorSpawn)
+	}
+}
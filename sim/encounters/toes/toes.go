@@ -0,0 +1,11 @@
+package toes
+
+// Melee damage of the bosses for 10 N, 25 N, 10 H and 25 H.
+var bossMinBaseDamage = [4]float64{230_000, 270_000, 320_000, 410_000}
+
+func Register() {
+	addProtectors("Terrace of Endless Spring")
+	addTsulong("Terrace of Endless Spring")
+	addLeiShi("Terrace of Endless Spring")
+	addShaOfFear("Terrace of Endless Spring")
+}
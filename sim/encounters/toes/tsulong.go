@@ -0,0 +1,47 @@
+package toes
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Tsulong alternates between night, when he is attacked, and day, when he turns friendly and the
+// raid kills Embodied Terrors while empowered by Bathed in Light.
+func addTsulong(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		health := d.Pick([4]float64{110_000_000, 330_000_000, 165_000_000, 495_000_000})
+		scale := d.DamageScale()
+
+		tsulong := timeline.Boss(62442, "Tsulong", d, proto.MobType_MobTypeDragonkin, health, d.Pick(bossMinBaseDamage))
+		terror := timeline.Add(62969, "Embodied Terror", d, proto.MobType_MobTypeElemental, health*0.03)
+
+		night := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.EnableTargets(0, 0),
+				timeline.DisableTargets(0.5, 1),
+				// Nightmares and Dark of Night.
+				timeline.Repeat(15, timeline.RaidDamage(10, 122770, proto.SpellSchool_SpellSchoolShadow, 80_000*scale, 3)),
+				timeline.Repeat(15, timeline.Movement(12, 2)),
+			)
+		}
+		day := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.EnableTargets(0, 1),
+				timeline.DisableTargets(0.5, 0),
+				timeline.RaidBuff(0, 122858, 60, 1, 1.2),
+				timeline.Repeat(15, timeline.RaidDamage(5, 123011, proto.SpellSchool_SpellSchoolShadow, 40_000*scale, 0)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Tsulong", d, &proto.EncounterTimeline{
+			FightLength: 450,
+			Phases: []*proto.TimelinePhase{
+				night("Night", 0),
+				day("Day", 120),
+				night("Night 2", 180),
+				day("Day 2", 300),
+				night("Night 3", 360),
+			},
+		}, tsulong, terror)
+	}
+}
@@ -0,0 +1,52 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// The four elders are fought together while Gara'jal's spirit possesses one of them at a time. The
+// raid swaps to the possessed elder to push him to low health before the possession moves on, and
+// kills Sul, then Mar'li, then Malakk, then Kazra'jin.
+func addCouncilOfElders(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		health := d.Health(48_000_000)
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+
+		sul := timeline.Boss(69078, "Sul the Sandcrawler", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+		marli := timeline.Boss(69132, "High Priestess Mar'li", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+		malakk := timeline.Boss(69131, "Frost King Malakk", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+		kazrajin := timeline.Boss(69134, "Kazra'jin", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+
+		// Each elder is possessed for 40 seconds, then dies when the next is possessed.
+		possession := func(name string, start float64, possessed int32, killed ...int32) *proto.TimelinePhase {
+			events := []*proto.TimelineEvent{
+				timeline.TargetSwitch(0, possessed, 0),
+				timeline.Repeat(15, timeline.RaidDamage(8, 136992, proto.SpellSchool_SpellSchoolShadow, 45_000*scale, 0)),
+				// Quicksand and Frigid Assault.
+				timeline.Repeat(20, timeline.Movement(12, 2)),
+			}
+			if len(killed) > 0 {
+				events = append(events, timeline.DisableTargets(0, killed...))
+			}
+			return timeline.Phase(name, start, events...)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Council of Elders", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				possession("Sul Possessed", 0, 0),
+				possession("Mar'li Possessed", 80, 1, 0),
+				possession("Malakk Possessed", 160, 2, 1),
+				timeline.Phase("Kazra'jin Possessed", 240,
+					timeline.DisableTargets(0, 2),
+					timeline.TargetSwitch(0, 3, 0),
+					timeline.ExecutePhase(0, 25),
+					// Reckless Charges across the room.
+					timeline.Repeat(20, timeline.Movement(5, 2)),
+					timeline.Repeat(20, timeline.RaidDamage(5, 137122, proto.SpellSchool_SpellSchoolNature, 60_000*scale, 0)),
+				),
+			},
+		}, sul, marli, malakk, kazrajin)
+	}
+}
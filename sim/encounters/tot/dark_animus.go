@@ -0,0 +1,44 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Golems are killed near each other to wake the larger golems and finally Dark Animus, so the first
+// two minutes are spent on adds. Animus then pulses Anima Ring and Interrupting Jolt, and cleaves
+// the raid with Full Power at low health.
+func addDarkAnimus(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(140_000_000)
+
+		animus := timeline.Boss(69427, "Dark Animus", d, proto.MobType_MobTypeMechanical, bossHealth, d.Pick(bossMinBaseDamage))
+		animus.DisabledAtStart = true
+		golems := timeline.Boss(69700, "Large Anima Golem", d, proto.MobType_MobTypeMechanical, bossHealth*0.1, d.Pick(bossMinBaseDamage)*0.5)
+		massive := timeline.Add(69699, "Massive Anima Golem", d, proto.MobType_MobTypeMechanical, bossHealth*0.2)
+
+		timeline.AddBossEncounter(raidPrefix, "Dark Animus", d, &proto.EncounterTimeline{
+			FightLength: 390,
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Golems", 0,
+					timeline.Repeat(20, timeline.Movement(10, 2)),
+					timeline.EnableTargets(45, 1),
+					timeline.DisableTargets(45.5, 0),
+				),
+				timeline.Phase("Dark Animus", 100,
+					timeline.EnableTargets(0, 2),
+					timeline.DisableTargets(0.5, 1),
+					// Interrupting Jolt stops every caster.
+					timeline.Repeat(22, timeline.Movement(18, 2)),
+					timeline.Repeat(22, timeline.RaidDamage(20, 138763, proto.SpellSchool_SpellSchoolArcane, 60_000*scale, 0)),
+					timeline.Repeat(30, timeline.RaidDamage(10, 136954, proto.SpellSchool_SpellSchoolArcane, 30_000*scale, 0)),
+				),
+				timeline.Phase("Full Power", 300,
+					timeline.ExecutePhase(0, 25),
+					timeline.Repeat(1, timeline.RaidDamage(0, 138729, proto.SpellSchool_SpellSchoolArcane, 15_000*scale, 0)),
+				),
+			},
+		}, golems, massive, animus)
+	}
+}
@@ -0,0 +1,41 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Durumu cycles Light Spectrum, where the raid kills the fogs revealed by the beams, then the Disintegration
+// Beam maze, which keeps everyone walking for most of a minute.
+func addDurumu(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(160_000_000)
+
+		durumu := timeline.Boss(68036, "Durumu the Forgotten", d, proto.MobType_MobTypeUnknown, bossHealth, d.Pick(bossMinBaseDamage))
+		fog := timeline.Add(69050, "Crimson Fog", d, proto.MobType_MobTypeElemental, bossHealth*0.01)
+
+		cycle := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.Repeat(20, timeline.Movement(10, 1.5)),
+				timeline.Repeat(15, timeline.RaidDamage(5, 133732, proto.SpellSchool_SpellSchoolShadow, 30_000*scale, 0)),
+				// Light Spectrum.
+				timeline.EnableTargets(40, 1),
+				timeline.TargetSwitch(40, 1, 20),
+				timeline.DisableTargets(60, 1),
+				// Disintegration Beam maze.
+				timeline.Repeat(5, timeline.Movement(130, 4)),
+				timeline.Repeat(5, timeline.RaidDamage(130, 133775, proto.SpellSchool_SpellSchoolShadow, 20_000*scale, 0)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Durumu the Forgotten", d, &proto.EncounterTimeline{
+			FightLength: 450,
+			Phases: []*proto.TimelinePhase{
+				cycle("Cycle 1", 0),
+				cycle("Cycle 2", 185),
+				cycle("Cycle 3", 370),
+			},
+		}, durumu, fog)
+	}
+}
@@ -0,0 +1,57 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Adds pour out of the four tribal doors in turn, Farraki, Gurubashi, Drakkari and Amani, and are
+// killed while Horridon is tanked. War-God Jalak joins after the last door, and Horridon is burned
+// down once the doors are closed.
+func addHorridon(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(145_000_000)
+		addHealth := bossHealth * 0.015
+
+		horridon := timeline.Boss(68476, "Horridon", d, proto.MobType_MobTypeBeast, bossHealth, d.Pick(bossMinBaseDamage))
+		farraki := timeline.Add(69175, "Farraki Wastewalker", d, proto.MobType_MobTypeHumanoid, addHealth)
+		gurubashi := timeline.Add(69164, "Gurubashi Venom Priest", d, proto.MobType_MobTypeHumanoid, addHealth)
+		drakkari := timeline.Add(69178, "Drakkari Frozen Warlord", d, proto.MobType_MobTypeHumanoid, addHealth*2)
+		amani := timeline.Add(69176, "Amani'shi Beast Shaman", d, proto.MobType_MobTypeHumanoid, addHealth)
+		jalak := timeline.Boss(69374, "War-God Jalak", d, proto.MobType_MobTypeHumanoid, bossHealth*0.15, d.Pick(bossMinBaseDamage))
+		jalak.DisabledAtStart = true
+
+		// Each door opens for a minute and a half, with a wave of adds every 20 seconds. The adds run
+		// from their door in a corner of the room to the add tank, 10 yards from Horridon.
+		door := func(name string, start float64, addIndex int32, doorPosition *proto.Vector2) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.Repeat(20, timeline.EnableTargets(5, addIndex)),
+				timeline.Repeat(20, timeline.MoveTarget(5, addIndex, doorPosition, timeline.Point(0, 10))),
+				timeline.Repeat(20, timeline.TargetSwitch(5, addIndex, 10)),
+				timeline.Repeat(20, timeline.DisableTargets(15, addIndex)),
+				// Double Swipe and Charge.
+				timeline.Repeat(30, timeline.Movement(20, 1.5)),
+				timeline.Repeat(10, timeline.RaidDamage(5, 136723, proto.SpellSchool_SpellSchoolNature, 20_000*scale, 3)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Horridon", d, &proto.EncounterTimeline{
+			FightLength: 480,
+			Phases: []*proto.TimelinePhase{
+				door("Farraki Door", 0, 1, timeline.Point(-40, 40)),
+				door("Gurubashi Door", 90, 2, timeline.Point(40, 40)),
+				door("Drakkari Door", 180, 3, timeline.Point(40, -40)),
+				door("Amani Door", 270, 4, timeline.Point(-40, -40)),
+				timeline.Phase("War-God Jalak", 360,
+					timeline.EnableTargets(0, 5),
+					timeline.TargetSwitch(0, 5, 0),
+					timeline.DisableTargets(30, 5),
+					// Horridon goes Rampage after Jalak dies.
+					timeline.ExecutePhase(30, 35),
+					timeline.Repeat(15, timeline.RaidDamage(30, 136821, proto.SpellSchool_SpellSchoolPhysical, 60_000*scale, 0)),
+				),
+			},
+		}, horridon, farraki, gurubashi, drakkari, amani, jalak)
+	}
+}
@@ -0,0 +1,47 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Iron Qon rides each of his quilen in turn, and the raid kills Ro'shak, then Quet'zal, then
+// Dam'ren before Qon dismounts and fights alongside his Fist of Thunder.
+func addIronQon(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(120_000_000)
+		mountHealth := bossHealth * 0.4
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+
+		qon := timeline.Boss(68078, "Iron Qon", d, proto.MobType_MobTypeHumanoid, bossHealth, minBaseDamage)
+		qon.DisabledAtStart = true
+		roshak := timeline.Boss(68079, "Ro'shak", d, proto.MobType_MobTypeBeast, mountHealth, minBaseDamage)
+		quetzal := timeline.Add(68080, "Quet'zal", d, proto.MobType_MobTypeBeast, mountHealth)
+		damren := timeline.Add(68081, "Dam'ren", d, proto.MobType_MobTypeBeast, mountHealth)
+
+		// Throw Spear and the mount's ability force movement throughout.
+		mount := func(name string, start float64, mountIndex int32, nextIndex int32, spellID int32, school proto.SpellSchool) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.Repeat(30, timeline.Movement(15, 2)),
+				timeline.Repeat(8, timeline.RaidDamage(4, spellID, school, 25_000*scale, 0)),
+				timeline.EnableTargets(100, nextIndex),
+				timeline.DisableTargets(100.5, mountIndex),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Iron Qon", d, &proto.EncounterTimeline{
+			FightLength: 420,
+			Phases: []*proto.TimelinePhase{
+				mount("Ro'shak", 0, 1, 2, 134628, proto.SpellSchool_SpellSchoolFire),
+				mount("Quet'zal", 101, 2, 3, 136192, proto.SpellSchool_SpellSchoolNature),
+				mount("Dam'ren", 202, 3, 0, 135145, proto.SpellSchool_SpellSchoolFrost),
+				timeline.Phase("Fist of Thunder", 303,
+					timeline.ExecutePhase(0, 35),
+					timeline.Repeat(20, timeline.Movement(10, 2)),
+					timeline.Repeat(5, timeline.RaidDamage(2, 136146, proto.SpellSchool_SpellSchoolNature, 40_000*scale, 0)),
+				),
+			},
+		}, qon, roshak, quetzal, damren)
+	}
+}
@@ -0,0 +1,36 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Ji-Kun sends Quills and Down Drafts through the raid on her platform, while nest groups fly out
+// with Primal Feathers to kill the hatchlings, losing time on the boss.
+func addJikun(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(148_000_000)
+
+		jikun := timeline.Boss(69712, "Ji-Kun", d, proto.MobType_MobTypeBeast, bossHealth, d.Pick(bossMinBaseDamage))
+		hatchling := timeline.Add(68192, "Hatchling", d, proto.MobType_MobTypeBeast, bossHealth*0.005)
+
+		timeline.AddBossEncounter(raidPrefix, "Ji-Kun", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Ji-Kun", 0,
+					timeline.Repeat(60, timeline.RaidDamage(40, 134380, proto.SpellSchool_SpellSchoolPhysical, 90_000*scale, 0)),
+					// Down Draft pushes everyone towards the edge.
+					timeline.Repeat(90, timeline.Movement(90, 8)),
+					timeline.Repeat(40, timeline.Movement(20, 3)),
+					// Flying to a nest and back.
+					timeline.Repeat(30, timeline.Movement(28, 6)),
+					timeline.Repeat(30, timeline.EnableTargets(30, 1)),
+					timeline.Repeat(30, timeline.TargetSwitch(30, 1, 8)),
+					timeline.Repeat(30, timeline.DisableTargets(38, 1)),
+					// Primal Nutriment after eating a feather.
+					timeline.Repeat(60, timeline.RaidBuff(40, 140741, 30, 1.3, 1.3)),
+				),
+			},
+		}, jikun, hatchling)
+	}
+}
@@ -0,0 +1,30 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Jin'rokh alternates Focused Lightning and Static Burst with Lightning Storm every 90 seconds,
+// which the raid spends standing in the conductive water pools.
+func addJinrokh(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		jinrokh := timeline.Boss(69465, "Jin'rokh the Breaker", d, proto.MobType_MobTypeHumanoid, d.Health(137_000_000), d.Pick(bossMinBaseDamage))
+
+		timeline.AddBossEncounter(raidPrefix, "Jin'rokh the Breaker", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Jin'rokh", 0,
+					// Focused Lightning chases a player, who kites it into a pool.
+					timeline.Repeat(20, timeline.Movement(8, 2)),
+					timeline.Repeat(90, timeline.Movement(60, 3)),
+					timeline.Repeat(90, timeline.RaidDamage(66, 137313, proto.SpellSchool_SpellSchoolNature, 40_000*scale, 0)),
+					timeline.Repeat(90, timeline.RaidDamage(70, 137313, proto.SpellSchool_SpellSchoolNature, 40_000*scale, 0)),
+					timeline.Repeat(90, timeline.RaidDamage(74, 137313, proto.SpellSchool_SpellSchoolNature, 40_000*scale, 0)),
+					// Conductive Water boosts the damage of everyone standing in it.
+					timeline.Repeat(90, timeline.RaidBuff(63, 138470, 15, 1, 1.4)),
+				),
+			},
+		}, jinrokh)
+	}
+}
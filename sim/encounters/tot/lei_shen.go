@@ -0,0 +1,48 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Lei Shen overcharges conduits in phase one and two, sending everyone to the platforms for the
+// intermissions at 65% and 30%. Phase three adds Lightning Whip and Overwhelming Power and burns
+// him down.
+func addLeiShen(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		leiShen := timeline.Boss(68397, "Lei Shen", d, proto.MobType_MobTypeHumanoid, d.Health(225_000_000), d.Pick(bossMinBaseDamage))
+
+		// The raid splits up over the conduit platforms, so there is little time on the boss.
+		intermission := func(name string, start float64) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.Movement(0, 5),
+				timeline.Repeat(10, timeline.Movement(10, 3)),
+				timeline.Repeat(5, timeline.RaidDamage(5, 135096, proto.SpellSchool_SpellSchoolNature, 40_000*scale, 0)),
+				timeline.Movement(40, 5),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Lei Shen", d, &proto.EncounterTimeline{
+			FightLength: 420,
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Phase 1", 0,
+					timeline.Repeat(40, timeline.Movement(20, 2)),
+					timeline.Repeat(20, timeline.RaidDamage(10, 135695, proto.SpellSchool_SpellSchoolNature, 60_000*scale, 0)),
+				),
+				intermission("Intermission 1", 110),
+				timeline.Phase("Phase 2", 155,
+					timeline.Repeat(40, timeline.Movement(20, 2)),
+					timeline.Repeat(30, timeline.Movement(35, 3)),
+					timeline.Repeat(15, timeline.RaidDamage(5, 136850, proto.SpellSchool_SpellSchoolNature, 50_000*scale, 0)),
+				),
+				intermission("Intermission 2", 265),
+				timeline.Phase("Phase 3", 310,
+					timeline.ExecutePhase(0, 25),
+					timeline.Repeat(30, timeline.Movement(20, 3)),
+					timeline.Repeat(10, timeline.RaidDamage(5, 136543, proto.SpellSchool_SpellSchoolNature, 60_000*scale, 0)),
+				),
+			},
+		}, leiShen)
+	}
+}
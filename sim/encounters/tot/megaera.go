@@ -0,0 +1,48 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Two heads are up at a time and the raid kills one of them, which makes Megaera Rampage while two
+// new heads emerge. The flaming and frozen heads alternate with the venomous head after each kill.
+func addMegaera(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		health := d.Health(38_000_000)
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+
+		flaming := timeline.Boss(70212, "Flaming Head", d, proto.MobType_MobTypeDragonkin, health, minBaseDamage)
+		frozen := timeline.Boss(70235, "Frozen Head", d, proto.MobType_MobTypeDragonkin, health, minBaseDamage)
+		venomous := timeline.Boss(70247, "Venomous Head", d, proto.MobType_MobTypeDragonkin, health, minBaseDamage)
+		venomous.DisabledAtStart = true
+
+		// The heads come out of the pool about 15 yards apart, out of range of most cleaves.
+		frozen.Position = timeline.Point(15, 0)
+		venomous.Position = timeline.Point(7.5, 13)
+
+		// Each head dies after about 70 seconds, followed by 20 seconds of Rampage.
+		head := func(name string, start float64, killed int32, emerging int32) *proto.TimelinePhase {
+			return timeline.Phase(name, start,
+				timeline.TargetSwitch(0, killed, 0),
+				timeline.Repeat(25, timeline.Movement(15, 2)),
+				timeline.Repeat(15, timeline.RaidDamage(10, 139822, proto.SpellSchool_SpellSchoolFire, 50_000*scale, 2)),
+				timeline.EnableTargets(70, emerging),
+				timeline.DisableTargets(70.5, killed),
+				timeline.Repeat(2, timeline.RaidDamage(71, 139458, proto.SpellSchool_SpellSchoolFire, 35_000*scale, 0)),
+			)
+		}
+
+		timeline.AddBossEncounter(raidPrefix, "Megaera", d, &proto.EncounterTimeline{
+			FightLength: 420,
+			Phases: []*proto.TimelinePhase{
+				head("Flaming Head", 0, 0, 2),
+				head("Frozen Head", 90, 1, 0),
+				head("Venomous Head", 180, 2, 1),
+				head("Flaming Head 2", 270, 0, 2),
+				head("Frozen Head 2", 360, 1, 0),
+			},
+		}, flaming, frozen, venomous)
+	}
+}
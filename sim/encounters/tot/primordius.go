@@ -0,0 +1,27 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Primordius is a single target fight where the raid soaks Living Fluids for beneficial mutations
+// and takes growing Primordial Strike and Caustic Gas damage.
+func addPrimordius(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		primordius := timeline.Boss(69017, "Primordius", d, proto.MobType_MobTypeElemental, d.Health(155_000_000), d.Pick(bossMinBaseDamage))
+
+		timeline.AddBossEncounter(raidPrefix, "Primordius", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Primordius", 0,
+					// Moving to a Living Fluid for the next mutation.
+					timeline.Repeat(30, timeline.Movement(15, 2)),
+					timeline.Repeat(30, timeline.RaidBuff(17, 136184, 60, 1, 1.1)),
+					timeline.Repeat(12, timeline.RaidDamage(6, 136216, proto.SpellSchool_SpellSchoolNature, 45_000*scale, 0)),
+					timeline.Repeat(45, timeline.RaidDamage(40, 136037, proto.SpellSchool_SpellSchoolNature, 35_000*scale, 0)),
+				),
+			},
+		}, primordius)
+	}
+}
@@ -0,0 +1,32 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Tortos is tanked while the raid kicks shells at him to interrupt Furious Stone Breath and kills
+// Vampiric Cave Bats, with Rockfall and Quake Stomp damage throughout.
+func addTortos(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		bossHealth := d.Health(176_000_000)
+
+		tortos := timeline.Boss(67977, "Tortos", d, proto.MobType_MobTypeBeast, bossHealth, d.Pick(bossMinBaseDamage))
+		bats := timeline.Add(69352, "Vampiric Cave Bat", d, proto.MobType_MobTypeBeast, bossHealth*0.01)
+
+		timeline.AddBossEncounter(raidPrefix, "Tortos", d, &proto.EncounterTimeline{
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Tortos", 0,
+					// Quake Stomp, with Rockfalls after it.
+					timeline.Repeat(47, timeline.RaidDamage(27, 134920, proto.SpellSchool_SpellSchoolPhysical, 150_000*scale, 0)),
+					timeline.Repeat(10, timeline.Movement(5, 1.5)),
+					// Kicking shells.
+					timeline.Repeat(46, timeline.Movement(40, 3)),
+					timeline.Repeat(45, timeline.EnableTargets(40, 1)),
+					timeline.Repeat(45, timeline.DisableTargets(55, 1)),
+				),
+			},
+		}, tortos, bats)
+	}
+}
@@ -0,0 +1,19 @@
+package tot
+
+// Melee damage of the bosses for 10 N, 25 N, 10 H and 25 H.
+var bossMinBaseDamage = [4]float64{250_000, 300_000, 350_000, 450_000}
+
+func Register() {
+	addJinrokh("Throne of Thunder")
+	addHorridon("Throne of Thunder")
+	addCouncilOfElders("Throne of Thunder")
+	addTortos("Throne of Thunder")
+	addMegaera("Throne of Thunder")
+	addJikun("Throne of Thunder")
+	addDurumu("Throne of Thunder")
+	addPrimordius("Throne of Thunder")
+	addDarkAnimus("Throne of Thunder")
+	addIronQon("Throne of Thunder")
+	addTwinConsorts("Throne of Thunder")
+	addLeiShen("Throne of Thunder")
+}
@@ -0,0 +1,45 @@
+package tot
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/timeline"
+)
+
+// Lu'lin and Suen share the night, when Lu'lin is burned while Suen stays out of reach. At day Suen
+// comes down and Lu'lin leaves, and at dusk both are up with Lu'lin taking all the damage. The
+// Celestial Aid from Xuen's Tiger at dusk speeds the raid up.
+func addTwinConsorts(raidPrefix string) {
+	for _, d := range timeline.Difficulties {
+		scale := d.DamageScale()
+		health := d.Health(68_000_000)
+		minBaseDamage := d.Pick(bossMinBaseDamage)
+
+		lulin := timeline.Boss(68905, "Lu'lin", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+		suen := timeline.Boss(68904, "Suen", d, proto.MobType_MobTypeHumanoid, health, minBaseDamage)
+		suen.DisabledAtStart = true
+
+		timeline.AddBossEncounter(raidPrefix, "Twin Consorts", d, &proto.EncounterTimeline{
+			FightLength: 480,
+			Phases: []*proto.TimelinePhase{
+				timeline.Phase("Night", 0,
+					timeline.Repeat(15, timeline.Movement(10, 2)),
+					timeline.Repeat(20, timeline.RaidDamage(12, 137404, proto.SpellSchool_SpellSchoolArcane, 30_000*scale, 0)),
+					timeline.EnableTargets(180, 1),
+					timeline.DisableTargets(180.5, 0),
+				),
+				timeline.Phase("Day", 181,
+					timeline.Repeat(15, timeline.RaidDamage(5, 137491, proto.SpellSchool_SpellSchoolFire, 50_000*scale, 0)),
+					timeline.Repeat(20, timeline.Movement(10, 2.5)),
+					timeline.EnableTargets(180, 0),
+				),
+				timeline.Phase("Dusk", 362,
+					timeline.TargetSwitch(0, 0, 0),
+					timeline.ExecutePhase(0, 35),
+					timeline.RaidBuff(0, 138855, 20, 1.2, 1),
+					timeline.Repeat(15, timeline.RaidDamage(5, 137491, proto.SpellSchool_SpellSchoolFire, 50_000*scale, 0)),
+					timeline.Repeat(15, timeline.Movement(10, 2)),
+				),
+			},
+		}, lulin, suen)
+	}
+}
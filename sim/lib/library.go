@@ -8,6 +8,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"log"
+	"runtime/debug"
+	"sync"
 	"unsafe"
 
 	"github.com/wowsims/mop/sim"
@@ -125,11 +127,14 @@ func getDatabase(itemIds *int32, numItems int32, enchantIds *int32, numEnchants
 	for i, enchantId := range eids {
 		enchant := core.EnchantsByEffectID[enchantId]
 		simDB.Enchants[i] = &proto.SimEnchant{
-			EffectId:      enchant.EffectID,
-			Stats:         enchant.Stats[:],
-			EnchantEffect: enchant.EnchantEffect,
-			Name:          enchant.Name,
-			Type:          enchant.Type,
+			EffectId:           enchant.EffectID,
+			Stats:              enchant.Stats[:],
+			EnchantEffect:      enchant.EnchantEffect,
+			Name:               enchant.Name,
+			Type:               enchant.Type,
+			ExtraTypes:         enchant.ExtraTypes,
+			EnchantType:        enchant.EnchantType,
+			RequiredProfession: enchant.RequiredProfession,
 		}
 	}
 	for i, gemId := range gids {
@@ -140,6 +145,7 @@ func getDatabase(itemIds *int32, numItems int32, enchantIds *int32, numEnchants
 			Color:                   gem.Color,
 			Stats:                   gem.Stats[:],
 			DisabledInChallengeMode: gem.DisabledInChallengeMode,
+			RequiredProfession:      gem.RequiredProfession,
 		}
 	}
 	out, err := protojson.Marshal(simDB)
@@ -149,6 +155,9 @@ func getDatabase(itemIds *int32, numItems int32, enchantIds *int32, numEnchants
 	return C.CString(string(out))
 }
 
+// The functions below drive a single global sim, controlling the first player of the first
+// party. The env* functions further down support any number of sims and any player.
+
 //export new
 func new(json *C.char) {
 	input := &proto.RaidSimRequest{}
@@ -176,17 +185,6 @@ func trySpell(act int) bool {
 	target := player.GetCharacter().CurrentTarget
 	casted := false
 
-	// FIXME : This is a hack to allow Heroic strike to work
-	if spell.ActionID.SpellID == 47450 {
-		aura := player.GetCharacter().GetAura("HS Queue Aura")
-		if aura.IsActive() {
-			return false
-		}
-		aura.Activate(_active_sim)
-		return true
-	}
-	// End of Heroic strike hack
-
 	if spell.CanCast(_active_sim, target) {
 		casted = spell.Cast(_active_sim, target)
 		if casted && spell.CurCast.GCD > 0 {
@@ -357,6 +355,141 @@ func cleanup() {
 	_active_sim.Cleanup()
 }
 
+var (
+	_envs      = map[int32]*core.InteractiveEnv{}
+	_envsMutex sync.Mutex
+	_next_env  int32 = 1
+)
+
+func getEnv(handle int32) (*core.InteractiveEnv, bool) {
+	_envsMutex.Lock()
+	defer _envsMutex.Unlock()
+	env, ok := _envs[handle]
+	if !ok {
+		log.Printf("no environment with handle %d", handle)
+	}
+	return env, ok
+}
+
+// Creates an interactive environment controlling the player at playerIndex, counting across all
+// parties, and returns its handle, or -1 if the request is invalid. Different environments can be
+// used from different threads at once, but each one only from one thread at a time.
+//
+//export envNew
+func envNew(json *C.char, playerIndex int32) int32 {
+	input := &proto.RaidSimRequest{}
+	err := protojson.Unmarshal([]byte(C.GoString(json)), input)
+	if err != nil {
+		log.Printf("failed to load input json: %s", err)
+		return -1
+	}
+	sim.RegisterAll()
+	env, err := core.NewInteractiveEnv(input, int(playerIndex))
+	if err != nil {
+		log.Printf("failed to create environment: %s", err)
+		return -1
+	}
+
+	_envsMutex.Lock()
+	defer _envsMutex.Unlock()
+	handle := _next_env
+	_next_env++
+	_envs[handle] = env
+	return handle
+}
+
+// Returns the environment's InteractiveEnvSchema as json, or NULL if the handle is unknown.
+//
+//export envSchema
+func envSchema(handle int32) *C.char {
+	env, ok := getEnv(handle)
+	if !ok {
+		return nil
+	}
+	out, err := protojson.Marshal(env.Schema())
+	if err != nil {
+		panic(err)
+	}
+	return C.CString(string(out))
+}
+
+// Returns the number of values in an observation, or -1 if the handle is unknown.
+//
+//export envObservationSize
+func envObservationSize(handle int32) int32 {
+	env, ok := getEnv(handle)
+	if !ok {
+		return -1
+	}
+	return env.Schema().ObservationSize
+}
+
+// Starts a new episode, writing its first observation to observation, which needs room for
+// envObservationSize values. Returns false if the handle is unknown or the sim failed.
+//
+//export envReset
+func envReset(handle int32, seed int64, observation *float64) (ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Printf("failed to reset environment %d: %v\nStack Trace:\n%s", handle, err, debug.Stack())
+			ok = false
+		}
+	}()
+	env, ok := getEnv(handle)
+	if !ok {
+		return false
+	}
+	copy(unsafe.Slice(observation, env.Schema().ObservationSize), env.Reset(seed))
+	return true
+}
+
+// Takes an action, writing the next observation and the reward for the action. Returns 1 if the
+// episode is over, 0 if it isn't, or -1 if the handle is unknown or the sim failed.
+//
+//export envStep
+func envStep(handle int32, action int32, observation *float64, reward *float64) (status int32) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Printf("failed to step environment %d: %v\nStack Trace:\n%s", handle, err, debug.Stack())
+			status = -1
+		}
+	}()
+	env, ok := getEnv(handle)
+	if !ok {
+		return -1
+	}
+	obs, r, done := env.Step(int(action))
+	copy(unsafe.Slice(observation, env.Schema().ObservationSize), obs)
+	*reward = r
+	if done {
+		return 1
+	}
+	return 0
+}
+
+// Returns the UnitMetrics of the controlled player over all finished episodes as json, or NULL if
+// the handle is unknown.
+//
+//export envMetrics
+func envMetrics(handle int32) *C.char {
+	env, ok := getEnv(handle)
+	if !ok {
+		return nil
+	}
+	out, err := protojson.Marshal(env.Metrics())
+	if err != nil {
+		panic(err)
+	}
+	return C.CString(string(out))
+}
+
+//export envClose
+func envClose(handle int32) {
+	_envsMutex.Lock()
+	defer _envsMutex.Unlock()
+	delete(_envs, handle)
+}
+
 //export FreeCString
 func FreeCString(s *C.char) {
 	C.free(unsafe.Pointer(s))
@@ -13,10 +13,11 @@ func (prot *ProtectionPaladin) registerConsecrationSpell() {
 		ActionID:       core.ActionID{SpellID: 26573},
 		SpellSchool:    core.SpellSchoolHoly,
 		ProcMask:       core.ProcMaskSpellDamage,
-		Flags:          core.SpellFlagAPL | core.SpellFlagAoE,
+		Flags:          core.SpellFlagAPL | core.SpellFlagAoE | core.SpellFlagAreaAroundCaster,
 		ClassSpellMask: paladin.SpellMaskConsecration,
 
 		MaxRange: 8,
+		Radius:   8,
 
 		ManaCost: core.ManaCostOptions{
 			BaseCostPercent: 7,
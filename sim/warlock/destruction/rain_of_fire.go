@@ -24,6 +24,7 @@ func (destruction DestructionWarlock) registerRainOfFire() {
 		ProcMask:       core.ProcMaskSpellDamage,
 		Flags:          core.SpellFlagAoE | core.SpellFlagAPL,
 		ClassSpellMask: warlock.WarlockSpellRainOfFire,
+		Radius:         8,
 		Cast: core.CastConfig{
 			DefaultCast: core.Cast{
 				GCD: core.GCDDefault,
@@ -51,7 +52,7 @@ func (destruction DestructionWarlock) registerRainOfFire() {
 			IsAOE:                true,
 			BonusCoefficient:     rofCoeff,
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				for _, aoeTarget := range sim.Encounter.ActiveTargetUnits {
+				for _, aoeTarget := range dot.Spell.AreaTargets(sim) {
 					result := dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, baseDamage, dot.OutcomeTickMagicCrit)
 					if result.Landed() && sim.Proc(0.125, "RoF - Ember Proc") {
 						destruction.BurningEmbers.Gain(sim, 2, dot.ActionID)
@@ -39,6 +39,11 @@ func main() {
 	js.Global().Set("bulkSimAsync", js.FuncOf(bulkSimAsync))
 	js.Global().Set("abortById", js.FuncOf(abortById))
 	js.Global().Set("bulkSimCombos", js.FuncOf(bulkSimCombos))
+	js.Global().Set("statScalingAsync", js.FuncOf(statScalingAsync))
+	js.Global().Set("itemUpgradesAsync", js.FuncOf(itemUpgradesAsync))
+	js.Global().Set("professionComparisonAsync", js.FuncOf(professionComparisonAsync))
+	js.Global().Set("raceComparisonAsync", js.FuncOf(raceComparisonAsync))
+	js.Global().Set("talentSweepAsync", js.FuncOf(talentSweepAsync))
 	js.Global().Call("wasmready")
 	<-c
 }
@@ -280,6 +285,96 @@ func bulkSimAsync(this js.Value, args []js.Value) interface{} {
 	return js.Undefined()
 }
 
+func statScalingAsync(this js.Value, args []js.Value) interface{} {
+	ssr := &proto.StatScalingRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), ssr); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+
+	requestId := args[2].String()
+	if strings.HasPrefix(requestId, "<T") {
+		requestId = "" // Make it return the error for an empty id
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	go core.RunStatScalingAsync(ssr, reporter, requestId)
+	go processAsyncProgress(args[1], reporter)
+	return js.Undefined()
+}
+
+func itemUpgradesAsync(this js.Value, args []js.Value) interface{} {
+	iur := &proto.ItemUpgradeRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), iur); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+
+	requestId := args[2].String()
+	if strings.HasPrefix(requestId, "<T") {
+		requestId = "" // Make it return the error for an empty id
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	go core.RunItemUpgradesAsync(iur, reporter, requestId)
+	go processAsyncProgress(args[1], reporter)
+	return js.Undefined()
+}
+
+func professionComparisonAsync(this js.Value, args []js.Value) interface{} {
+	pcr := &proto.ProfessionComparisonRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), pcr); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+
+	requestId := args[2].String()
+	if strings.HasPrefix(requestId, "<T") {
+		requestId = "" // Make it return the error for an empty id
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	go core.RunProfessionComparisonAsync(pcr, reporter, requestId)
+	go processAsyncProgress(args[1], reporter)
+	return js.Undefined()
+}
+
+func raceComparisonAsync(this js.Value, args []js.Value) interface{} {
+	rcr := &proto.RaceComparisonRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), rcr); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+
+	requestId := args[2].String()
+	if strings.HasPrefix(requestId, "<T") {
+		requestId = "" // Make it return the error for an empty id
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	go core.RunRaceComparisonAsync(rcr, reporter, requestId)
+	go processAsyncProgress(args[1], reporter)
+	return js.Undefined()
+}
+
+func talentSweepAsync(this js.Value, args []js.Value) interface{} {
+	tsr := &proto.TalentSweepRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), tsr); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+
+	requestId := args[2].String()
+	if strings.HasPrefix(requestId, "<T") {
+		requestId = "" // Make it return the error for an empty id
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	go core.RunTalentSweepAsync(tsr, reporter, requestId)
+	go processAsyncProgress(args[1], reporter)
+	return js.Undefined()
+}
+
 func raidSimRequestSplit(this js.Value, args []js.Value) interface{} {
 	splitRequest := &proto.RaidSimRequestSplitRequest{}
 	if err := googleProto.Unmarshal(getArgsBinary(args[0]), splitRequest); err != nil {
@@ -408,7 +503,7 @@ func processAsyncProgress(progFunc js.Value, reporter chan *proto.ProgressMetric
 			js.CopyBytesToJS(outArray, outbytes)
 			progFunc.Invoke(outArray)
 
-			if progMetric.FinalWeightResult != nil || progMetric.FinalRaidResult != nil || progMetric.FinalBulkResult != nil {
+			if progMetric.FinalWeightResult != nil || progMetric.FinalRaidResult != nil || progMetric.FinalBulkResult != nil || progMetric.FinalScalingResult != nil || progMetric.FinalUpgradeResult != nil || progMetric.FinalProfessionResult != nil || progMetric.FinalRaceResult != nil || progMetric.FinalSweepResult != nil {
 				return
 			}
 		}
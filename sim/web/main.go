@@ -114,6 +114,21 @@ var handlers = map[string]apiHandler{
 	"/bulkSimCombos": {msg: func() googleProto.Message { return &proto.BulkSimCombosRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.RunBulkCombos(msg.(*proto.BulkSimCombosRequest))
 	}},
+	"/statScaling": {msg: func() googleProto.Message { return &proto.StatScalingRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.RunStatScaling(msg.(*proto.StatScalingRequest))
+	}},
+	"/itemUpgrades": {msg: func() googleProto.Message { return &proto.ItemUpgradeRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.RunItemUpgrades(msg.(*proto.ItemUpgradeRequest))
+	}},
+	"/professionComparison": {msg: func() googleProto.Message { return &proto.ProfessionComparisonRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.RunProfessionComparison(msg.(*proto.ProfessionComparisonRequest))
+	}},
+	"/raceComparison": {msg: func() googleProto.Message { return &proto.RaceComparisonRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.RunRaceComparison(msg.(*proto.RaceComparisonRequest))
+	}},
+	"/talentSweep": {msg: func() googleProto.Message { return &proto.TalentSweepRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.RunTalentSweep(msg.(*proto.TalentSweepRequest))
+	}},
 }
 
 var asyncAPIHandlers = map[string]asyncAPIHandler{
@@ -126,6 +141,21 @@ var asyncAPIHandlers = map[string]asyncAPIHandler{
 	"/bulkSimAsync": {msg: func() googleProto.Message { return &proto.BulkSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.RunBulkSimAsync(msg.(*proto.BulkSimRequest), reporter, requestId)
 	}},
+	"/statScalingAsync": {msg: func() googleProto.Message { return &proto.StatScalingRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunStatScalingAsync(msg.(*proto.StatScalingRequest), reporter, requestId)
+	}},
+	"/itemUpgradesAsync": {msg: func() googleProto.Message { return &proto.ItemUpgradeRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunItemUpgradesAsync(msg.(*proto.ItemUpgradeRequest), reporter, requestId)
+	}},
+	"/professionComparisonAsync": {msg: func() googleProto.Message { return &proto.ProfessionComparisonRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunProfessionComparisonAsync(msg.(*proto.ProfessionComparisonRequest), reporter, requestId)
+	}},
+	"/raceComparisonAsync": {msg: func() googleProto.Message { return &proto.RaceComparisonRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunRaceComparisonAsync(msg.(*proto.RaceComparisonRequest), reporter, requestId)
+	}},
+	"/talentSweepAsync": {msg: func() googleProto.Message { return &proto.TalentSweepRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunTalentSweepAsync(msg.(*proto.TalentSweepRequest), reporter, requestId)
+	}},
 }
 
 type server struct {
@@ -144,13 +174,29 @@ type asyncAPIHandler struct {
 
 type asyncProgress struct {
 	id             string
+	requestId      string
 	latestProgress atomic.Value
+
+	// Only the latest report is kept for /asyncProgressStream, since each one supersedes the ones
+	// before it. Subscribers which connect late or reconnect start from it.
+	streamMut   sync.Mutex
+	latest      *proto.ProgressMetrics
+	published   int           // Number of reports so far, which numbers the events of the stream.
+	updated     chan struct{} // Closed and replaced whenever a report is published or the sim finishes.
+	finished    bool
+	subscribers int
 }
 
-func (s *server) addNewSim() *asyncProgress {
+func isFinalProgress(progMetric *proto.ProgressMetrics) bool {
+	return progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil || progMetric.FinalScalingResult != nil || progMetric.FinalUpgradeResult != nil || progMetric.FinalProfessionResult != nil || progMetric.FinalRaceResult != nil || progMetric.FinalSweepResult != nil
+}
+
+func (s *server) addNewSim(requestId string) *asyncProgress {
 	newID := uuid.NewString()
 	simProgress := &asyncProgress{
-		id: newID,
+		id:        newID,
+		requestId: requestId,
+		updated:   make(chan struct{}),
 	}
 	simProgress.latestProgress.Store(&proto.ProgressMetrics{})
 
@@ -185,18 +231,23 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 	//  as the simulation advances it will push changes to the channel
 	//  these changes will be consumed by the goroutine below so the asyncProgress endpoint can fetch the results.
 	reporter := make(chan *proto.ProgressMetrics, 100)
-	handler.handle(msg, reporter, r.URL.Query().Get("requestId"))
+	requestId := r.URL.Query().Get("requestId")
+	handler.handle(msg, reporter, requestId)
 
 	// Generate a new async simulation
-	simProgress := s.addNewSim()
+	simProgress := s.addNewSim(requestId)
 
 	// Now launch a background process that pulls progress reports off the reporter channel
 	// and pushes it into the async progress cache.
 	go func() {
+		defer simProgress.finish()
 		for {
 			select {
 			case <-time.After(time.Minute * 10):
-				// if we get no progress after 10 minutes, delete the pending sim and exit.
+				// if we get no progress after 10 minutes and nobody is streaming it, delete the pending sim and exit.
+				if simProgress.hasSubscribers() {
+					continue
+				}
 				s.progMut.Lock()
 				delete(s.asyncProgresses, simProgress.id)
 				s.progMut.Unlock()
@@ -206,7 +257,15 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 					return
 				}
 				simProgress.latestProgress.Store(progMetric)
-				if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil {
+				simProgress.publish(progMetric)
+				if isFinalProgress(progMetric) {
+					// Streams don't remove the sim when they finish, so keep it around long enough for
+					// late subscribers to replay it.
+					time.AfterFunc(time.Minute, func() {
+						s.progMut.Lock()
+						delete(s.asyncProgresses, simProgress.id)
+						s.progMut.Unlock()
+					})
 					return
 				}
 			}
@@ -264,7 +323,7 @@ func (s *server) setupAsyncServer() {
 		}
 
 		// If this was the last result, delete the cache for this simulation.
-		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil || latest.FinalBulkResult != nil {
+		if isFinalProgress(latest) {
 			s.progMut.Lock()
 			delete(s.asyncProgresses, msg.ProgressId)
 			s.progMut.Unlock()
@@ -272,6 +331,9 @@ func (s *server) setupAsyncServer() {
 		w.Header().Add("Content-Type", "application/x-protobuf")
 		w.Write(outbytes)
 	})))
+
+	// asyncProgressStream pushes the latest progress reports of a simulation as server-sent events.
+	http.Handle("/asyncProgressStream", corsMiddleware(http.HandlerFunc(s.handleProgressStream)))
 }
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
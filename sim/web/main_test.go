@@ -3,9 +3,11 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"encoding/base64"
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -118,3 +120,169 @@ func TestIndividualSim(t *testing.T) {
 
 	log.Printf("RESULT: %#v", rsr)
 }
+
+func startAsyncSim(t *testing.T, req *proto.RaidSimRequest, requestId string) string {
+	t.Helper()
+	msgBytes, err := googleProto.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %s", err.Error())
+	}
+	r, err := http.Post("http://localhost:3339/raidSimAsync?requestId="+requestId, "application/x-protobuf", bytes.NewReader(msgBytes))
+	if err != nil {
+		t.Fatalf("Failed to POST request: %s", err.Error())
+	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("Failed to read result body: %s", err.Error())
+	}
+	result := &proto.AsyncAPIResult{}
+	if err := googleProto.Unmarshal(body, result); err != nil {
+		t.Fatalf("Failed to parse result: %s", err.Error())
+	}
+	return result.ProgressId
+}
+
+// Reads progress events off the stream until the final result, or until stop returns true.
+func readProgressStream(t *testing.T, body io.Reader, stop func(*proto.ProgressMetrics) bool) *proto.ProgressMetrics {
+	t.Helper()
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, 16*1024*1024)
+	var last *proto.ProgressMetrics
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		msgBytes, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			t.Fatalf("Failed to decode event: %s", err.Error())
+		}
+		last = &proto.ProgressMetrics{}
+		if err := googleProto.Unmarshal(msgBytes, last); err != nil {
+			t.Fatalf("Failed to parse event: %s", err.Error())
+		}
+		if last.FinalRaidResult != nil || stop(last) {
+			return last
+		}
+	}
+	return last
+}
+
+func TestProgressStream(t *testing.T) {
+	newRequest := func(iterations int32) *proto.RaidSimRequest {
+		return &proto.RaidSimRequest{
+			Raid: core.SinglePlayerRaidProto(
+				&proto.Player{
+					Race:      proto.Race_RaceTroll,
+					Class:     proto.Class_ClassShaman,
+					Equipment: &proto.EquipmentSpec{},
+					Spec:      basicSpec,
+				},
+				&proto.PartyBuffs{},
+				&proto.RaidBuffs{},
+				&proto.Debuffs{}),
+			Encounter: &proto.Encounter{
+				Duration: 120,
+				Targets:  []*proto.Target{{}},
+			},
+			SimOptions: &proto.SimOptions{
+				Iterations: iterations,
+				RandomSeed: 1,
+			},
+		}
+	}
+
+	t.Run("MultipleSubscribers", func(t *testing.T) {
+		progressId := startAsyncSim(t, newRequest(1000), "streamtest")
+
+		var wg sync.WaitGroup
+		finals := make([]*proto.ProgressMetrics, 2)
+		for i := range finals {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r, err := http.Get("http://localhost:3339/asyncProgressStream?progressId=" + progressId)
+				if err != nil {
+					t.Errorf("Failed to GET stream: %s", err.Error())
+					return
+				}
+				defer r.Body.Close()
+				finals[i] = readProgressStream(t, r.Body, func(*proto.ProgressMetrics) bool { return false })
+			}()
+		}
+		wg.Wait()
+
+		for i, final := range finals {
+			if final == nil || final.FinalRaidResult == nil {
+				t.Errorf("Subscriber %d did not receive the final result", i)
+			} else if final.FinalRaidResult.Error != nil {
+				t.Errorf("Subscriber %d got error result: %s", i, final.FinalRaidResult.Error.Message)
+			}
+		}
+	})
+
+	t.Run("LateSubscriber", func(t *testing.T) {
+		progressId := startAsyncSim(t, newRequest(1000), "streamlate")
+
+		for i := range 2 {
+			r, err := http.Get("http://localhost:3339/asyncProgressStream?progressId=" + progressId)
+			if err != nil {
+				t.Fatalf("Failed to GET stream: %s", err.Error())
+			}
+			reports := 0
+			final := readProgressStream(t, r.Body, func(*proto.ProgressMetrics) bool {
+				reports++
+				return false
+			})
+			r.Body.Close()
+
+			if final == nil || final.FinalRaidResult == nil {
+				t.Fatalf("Subscriber %d did not receive the final result", i)
+			}
+			// The sim is done when the second subscriber connects, so only the final result is left.
+			if i == 1 && reports != 0 {
+				t.Errorf("Late subscriber received %d progress reports before the final result", reports)
+			}
+		}
+	})
+
+	t.Run("AbortOnDisconnect", func(t *testing.T) {
+		progressId := startAsyncSim(t, newRequest(1000000), "streamabort")
+
+		r, err := http.Get("http://localhost:3339/asyncProgressStream?progressId=" + progressId)
+		if err != nil {
+			t.Fatalf("Failed to GET stream: %s", err.Error())
+		}
+		readProgressStream(t, r.Body, func(*proto.ProgressMetrics) bool { return true })
+		r.Body.Close()
+
+		// Poll rather than stream, so the closed stream was the last subscriber.
+		var final *proto.ProgressMetrics
+		for deadline := time.Now().Add(time.Minute); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+			msgBytes, err := googleProto.Marshal(&proto.AsyncAPIResult{ProgressId: progressId})
+			if err != nil {
+				t.Fatalf("Failed to encode request: %s", err.Error())
+			}
+			r, err := http.Post("http://localhost:3339/asyncProgress", "application/x-protobuf", bytes.NewReader(msgBytes))
+			if err != nil {
+				t.Fatalf("Failed to POST request: %s", err.Error())
+			}
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				t.Fatalf("Failed to read result body: %s", err.Error())
+			}
+			final = &proto.ProgressMetrics{}
+			if err := googleProto.Unmarshal(body, final); err != nil {
+				t.Fatalf("Failed to parse result: %s", err.Error())
+			}
+			if final.FinalRaidResult != nil {
+				break
+			}
+		}
+		if final == nil || final.FinalRaidResult == nil || final.FinalRaidResult.Error == nil || final.FinalRaidResult.Error.Type != proto.ErrorOutcomeType_ErrorOutcomeAborted {
+			t.Errorf("Sim was not aborted after the stream disconnected, final progress: %v", final)
+		}
+	})
+}
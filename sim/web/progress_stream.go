@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	proto "github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func (ap *asyncProgress) publish(progMetric *proto.ProgressMetrics) {
+	ap.streamMut.Lock()
+	defer ap.streamMut.Unlock()
+	ap.latest = progMetric
+	ap.published++
+	close(ap.updated)
+	ap.updated = make(chan struct{})
+}
+
+// Marks the sim as done, so streams end once they have sent the latest report.
+func (ap *asyncProgress) finish() {
+	ap.streamMut.Lock()
+	defer ap.streamMut.Unlock()
+	ap.finished = true
+	close(ap.updated)
+	ap.updated = make(chan struct{})
+}
+
+// Returns the latest report and its index if it is at index start or later, a channel which is
+// closed on the next change, and whether the sim is done so there won't be one.
+func (ap *asyncProgress) since(start int) (*proto.ProgressMetrics, int, chan struct{}, bool) {
+	ap.streamMut.Lock()
+	defer ap.streamMut.Unlock()
+	if start < ap.published {
+		return ap.latest, ap.published - 1, ap.updated, ap.finished
+	}
+	return nil, 0, ap.updated, ap.finished
+}
+
+func (ap *asyncProgress) subscribe() {
+	ap.streamMut.Lock()
+	ap.subscribers++
+	ap.streamMut.Unlock()
+}
+
+// Returns true if this was the last subscriber and the sim is still running.
+func (ap *asyncProgress) unsubscribe() bool {
+	ap.streamMut.Lock()
+	defer ap.streamMut.Unlock()
+	ap.subscribers--
+	return ap.subscribers == 0 && !ap.finished
+}
+
+func (ap *asyncProgress) hasSubscribers() bool {
+	ap.streamMut.Lock()
+	defer ap.streamMut.Unlock()
+	return ap.subscribers > 0
+}
+
+// Streams the progress reports of an async sim as server-sent events, for
+// GET /asyncProgressStream?progressId=<id>. Each event holds one base64 encoded ProgressMetrics,
+// with the index of the report as its ID so a reconnecting EventSource picks up where it left off.
+// Reports published while a subscriber is still writing are skipped in favour of the latest one.
+// The stream ends after the final result. If the last subscriber disconnects before then, the sim
+// is aborted through its request ID.
+func (s *server) handleProgressStream(w http.ResponseWriter, r *http.Request) {
+	progressId := r.URL.Query().Get("progressId")
+	s.progMut.RLock()
+	progress, ok := s.asyncProgresses[progressId]
+	s.progMut.RUnlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Printf("[ERROR] Response writer does not support streaming")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	sent := 0
+	if lastEventId, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil {
+		sent = lastEventId + 1
+	}
+
+	progress.subscribe()
+	defer func() {
+		if progress.unsubscribe() && progress.requestId != "" {
+			simsignals.AbortById(progress.requestId)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		progMetric, index, updated, finished := progress.since(sent)
+		if progMetric != nil {
+			outbytes, err := googleProto.Marshal(progMetric)
+			if err != nil {
+				log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: progress\ndata: %s\n\n", index, base64.StdEncoding.EncodeToString(outbytes)); err != nil {
+				return
+			}
+			sent = index + 1
+			flusher.Flush()
+		}
+
+		if finished {
+			return
+		}
+
+		select {
+		case <-updated:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -98,6 +98,8 @@ import {
 	APLValueSpellTimeToCharge,
 	APLValueSpellTimeToReady,
 	APLValueSpellTravelTime,
+	APLValueTargetDistance,
+	APLValueTargetsInRadius,
 	APLValueTotemRemainingTime,
 	APLValueTrinketProcsMaxRemainingICD,
 	APLValueTrinketProcsMinRemainingTime,
@@ -680,6 +682,23 @@ const valueKindFactories: { [f in ValidAPLValueKind]: ValueKindConfig<APLValueIm
 		newValue: APLValueUnitDistance.create,
 		fields: [AplHelpers.unitFieldConfig('sourceUnit', 'aura_sources')],
 	}),
+	targetDistance: inputBuilder({
+		label: 'Distance to Target',
+		submenu: ['Unit'],
+		shortDescription: 'Returns the distance in yards from the player to the specified target.',
+		newValue: APLValueTargetDistance.create,
+		fields: [AplHelpers.unitFieldConfig('targetUnit', 'targets')],
+	}),
+	targetsInRadius: inputBuilder({
+		label: 'Targets in Radius',
+		submenu: ['Unit'],
+		shortDescription: 'Returns the number of active targets within the radius (in yards) of the specified unit.',
+		newValue: APLValueTargetsInRadius.create,
+		fields: [
+			AplHelpers.unitFieldConfig('centerUnit', 'aura_sources_targets_first'),
+			AplHelpers.numberFieldConfig('radius', true, { label: 'Radius' }),
+		],
+	}),
 
 	// Resources
 	currentHealth: inputBuilder({
@@ -2,6 +2,7 @@ import i18n from '../../../i18n/config';
 import * as Tooltips from '../../constants/tooltips.js';
 import { Encounter } from '../../encounter.js';
 import { IndividualSimUI, InputSection } from '../../individual_sim_ui.jsx';
+import { defaultHumanErrorModel } from '../../player.js';
 import { ConsumesSpec, Debuffs, HealingModel, IndividualBuffs, ItemSwap, PartyBuffs, Profession, RaidBuffs } from '../../proto/common.js';
 import { SavedEncounter, SavedSettings } from '../../proto/ui.js';
 import { professionNames, raceNames } from '../../proto_utils/names.js';
@@ -13,6 +14,7 @@ import { EncounterPicker } from '../encounter_picker.js';
 import * as IconInputs from '../icon_inputs.js';
 import { Input } from '../input.jsx';
 import * as BuffDebuffInputs from '../inputs/buffs_debuffs.js';
+import * as OtherInputs from '../inputs/other_inputs.js';
 import { relevantStatOptions } from '../inputs/stat_options.js';
 import { ItemSwapPicker } from '../item_swap_picker.jsx';
 import { BooleanPicker } from '../pickers/boolean_picker.js';
@@ -71,6 +73,7 @@ export class SettingsTab extends SimTab {
 		this.buildCustomSettingsSections();
 		this.buildConsumesSection();
 		this.buildOtherSettings();
+		this.buildHumanErrorSettings();
 
 		if (!this.simUI.isWithinRaidSim) {
 			this.buildBuffsSettings();
@@ -200,6 +203,28 @@ export class SettingsTab extends SimTab {
 		}
 	}
 
+	private buildHumanErrorSettings() {
+		const contentBlock = new ContentBlock(this.column2, 'human-error-settings', {
+			header: { title: 'Human Error' },
+		});
+
+		this.configureInputSection(contentBlock.bodyElement, {
+			inputs: [
+				OtherInputs.HumanErrorSkill,
+				OtherInputs.GcdLatency,
+				OtherInputs.GcdLatencyVariation,
+				OtherInputs.MissedGcdChance,
+				OtherInputs.CooldownDelay,
+				OtherInputs.CooldownDelayVariation,
+				OtherInputs.ProcReaction,
+				OtherInputs.ProcReactionVariation,
+			],
+		});
+		contentBlock.bodyElement.querySelectorAll('.input-root').forEach(elem => {
+			elem.classList.add('input-inline');
+		});
+	}
+
 	private buildBuffsSettings() {
 		const contentBlock = new ContentBlock(this.column3, 'buffs-settings', {
 			header: { title: 'Raid Buffs', tooltip: Tooltips.BUFFS_SECTION },
@@ -335,6 +360,7 @@ export class SettingsTab extends SimTab {
 					simUI.player.setInFrontOfTarget(eventID, newSettings.inFrontOfTarget);
 					simUI.player.setDistanceFromTarget(eventID, newSettings.distanceFromTarget);
 					simUI.player.setHealingModel(eventID, newSettings.healingModel || HealingModel.create());
+					simUI.player.setHumanErrorModel(eventID, newSettings.humanError || defaultHumanErrorModel());
 					simUI.player.setChallengeModeEnabled(eventID, newSettings.challengeMode);
 				});
 			},
@@ -407,6 +433,7 @@ export class SettingsTab extends SimTab {
 			inFrontOfTarget: this.simUI.player.getInFrontOfTarget(),
 			distanceFromTarget: this.simUI.player.getDistanceFromTarget(),
 			healingModel: this.simUI.player.getHealingModel(),
+			humanError: this.simUI.player.getHumanErrorModel(),
 			challengeMode: this.simUI.player.getChallengeModeEnabled(),
 		});
 	}
@@ -1,5 +1,5 @@
 import { Player } from '../../player.js';
-import { UnitReference } from '../../proto/common.js';
+import { HumanErrorModel, HumanLatency, UnitReference } from '../../proto/common.js';
 import { emptyUnitReference } from '../../proto_utils/utils.js';
 import { Sim } from '../../sim.js';
 import { EventID } from '../../typed_event.js';
@@ -127,6 +127,91 @@ export const ChannelClipDelay = {
 	},
 };
 
+export const HumanErrorSkill = {
+	id: 'human-error-skill',
+	type: 'number' as const,
+	label: 'Skill',
+	labelTooltip:
+		'Player skill from 0 to 100. At 100 the rotation is played perfectly, and the human errors below shrink linearly as skill goes up, from their full size at 0.',
+	changedEvent: (player: Player<any>) => player.humanErrorModelChangeEmitter,
+	getValue: (player: Player<any>) => player.getHumanErrorModel().skill,
+	setValue: (eventID: EventID, player: Player<any>, newValue: number) => {
+		const humanErrorModel = player.getHumanErrorModel();
+		humanErrorModel.skill = Math.min(Math.max(newValue, 0), 100);
+		player.setHumanErrorModel(eventID, humanErrorModel);
+	},
+};
+
+export const MissedGcdChance = {
+	id: 'human-error-missed-gcd-chance',
+	type: 'number' as const,
+	float: true,
+	positive: true,
+	label: 'Missed GCD %',
+	labelTooltip: 'Chance in percent to waste a whole GCD after each GCD, at 0 skill.',
+	changedEvent: (player: Player<any>) => player.humanErrorModelChangeEmitter,
+	getValue: (player: Player<any>) => player.getHumanErrorModel().missedGcdChance,
+	setValue: (eventID: EventID, player: Player<any>, newValue: number) => {
+		const humanErrorModel = player.getHumanErrorModel();
+		humanErrorModel.missedGcdChance = newValue;
+		player.setHumanErrorModel(eventID, humanErrorModel);
+	},
+	enableWhen: (player: Player<any>) => player.getHumanErrorModel().skill < 100,
+};
+
+type HumanLatencyField = 'gcdLatency' | 'cooldownDelay' | 'procReaction';
+
+function makeHumanLatencyInput(field: HumanLatencyField, part: keyof HumanLatency, label: string, labelTooltip: string) {
+	const getLatency = (humanErrorModel: HumanErrorModel) => humanErrorModel[field] || HumanLatency.create();
+	return {
+		id: `human-error-${field}-${part}`,
+		type: 'number' as const,
+		positive: true,
+		label,
+		labelTooltip,
+		changedEvent: (player: Player<any>) => player.humanErrorModelChangeEmitter,
+		getValue: (player: Player<any>) => getLatency(player.getHumanErrorModel())[part],
+		setValue: (eventID: EventID, player: Player<any>, newValue: number) => {
+			const humanErrorModel = player.getHumanErrorModel();
+			humanErrorModel[field] = { ...getLatency(humanErrorModel), [part]: newValue };
+			player.setHumanErrorModel(eventID, humanErrorModel);
+		},
+		enableWhen: (player: Player<any>) => player.getHumanErrorModel().skill < 100,
+	};
+}
+
+export const GcdLatency = makeHumanLatencyInput(
+	'gcdLatency',
+	'meanMs',
+	'GCD Latency',
+	'Average extra delay before pressing the next button once the GCD comes up, in milliseconds, at 0 skill.',
+);
+export const GcdLatencyVariation = makeHumanLatencyInput('gcdLatency', 'stddevMs', 'GCD Latency +/-', 'Standard deviation of the GCD latency, in milliseconds.');
+export const CooldownDelay = makeHumanLatencyInput(
+	'cooldownDelay',
+	'meanMs',
+	'Cooldown Delay',
+	'Average delay before noticing a major cooldown has come off cooldown, in milliseconds, at 0 skill.',
+);
+export const CooldownDelayVariation = makeHumanLatencyInput(
+	'cooldownDelay',
+	'stddevMs',
+	'Cooldown Delay +/-',
+	'Standard deviation of the cooldown delay, in milliseconds.',
+);
+export const ProcReaction = makeHumanLatencyInput(
+	'procReaction',
+	'meanMs',
+	'Proc Reaction',
+	"Average extra delay on top of the Input Delay before reacting to procs in APL values such as 'Aura Is Active With Reaction Time', in milliseconds, at 0 skill.",
+);
+export const ProcReactionVariation = makeHumanLatencyInput(
+	'procReaction',
+	'stddevMs',
+	'Proc Reaction +/-',
+	'Standard deviation of the proc reaction delay, in milliseconds.',
+);
+
 export const InFrontOfTarget = {
 	id: 'in-front-of-target',
 	type: 'boolean' as const,
@@ -125,6 +125,11 @@ export class Encounter {
 	applyPreset(eventID: EventID, preset: PresetEncounter) {
 		this.targets = preset.targets.map(presetTarget => presetTarget.target || TargetProto.create());
 		this.targetsChangeEmitter.emit(eventID);
+
+		const fightLength = this.targets[0]?.timeline?.fightLength;
+		if (fightLength) {
+			this.setDuration(eventID, fightLength);
+		}
 	}
 
 	applyPresetTarget(eventID: EventID, preset: PresetTarget, index: number) {
@@ -27,6 +27,7 @@ import {
 	Glyphs,
 	HandType,
 	HealingModel,
+	HumanErrorModel,
 	IndividualBuffs,
 	ItemLevelState,
 	ItemRandomSuffix,
@@ -229,6 +230,18 @@ export function getSpecConfig<SpecType extends Spec>(spec: SpecType): PlayerConf
 	return config;
 }
 
+// Perfect play by default. The errors describe a 0 skill player, so lowering the skill slider
+// alone gives a sensible model.
+export function defaultHumanErrorModel(): HumanErrorModel {
+	return HumanErrorModel.create({
+		skill: 100,
+		gcdLatency: { meanMs: 250, stddevMs: 100 },
+		missedGcdChance: 5,
+		cooldownDelay: { meanMs: 3000, stddevMs: 2000 },
+		procReaction: { meanMs: 400, stddevMs: 200 },
+	});
+}
+
 // Manages all the gear / consumes / other settings for a single Player.
 export class Player<SpecType extends Spec> {
 	readonly sim: Sim;
@@ -259,6 +272,7 @@ export class Player<SpecType extends Spec> {
 	private distanceFromTarget = 0;
 	private healingModel: HealingModel = HealingModel.create();
 	private healingEnabled = false;
+	private humanErrorModel: HumanErrorModel = defaultHumanErrorModel();
 	private challengeModeEnabled = false;
 
 	private readonly autoRotationGenerator: AutoRotationGenerator<SpecType> | null = null;
@@ -299,6 +313,7 @@ export class Player<SpecType extends Spec> {
 	readonly inFrontOfTargetChangeEmitter = new TypedEvent<void>('PlayerInFrontOfTarget');
 	readonly distanceFromTargetChangeEmitter = new TypedEvent<void>('PlayerDistanceFromTarget');
 	readonly healingModelChangeEmitter = new TypedEvent<void>('PlayerHealingModel');
+	readonly humanErrorModelChangeEmitter = new TypedEvent<void>('PlayerHumanErrorModel');
 	readonly epWeightsChangeEmitter = new TypedEvent<void>('PlayerEpWeights');
 	readonly statCapsChangeEmitter = new TypedEvent<void>('StatCaps');
 	readonly softCapBreakpointsChangeEmitter = new TypedEvent<void>('SoftCapBreakpoints');
@@ -361,6 +376,7 @@ export class Player<SpecType extends Spec> {
 				this.inFrontOfTargetChangeEmitter,
 				this.distanceFromTargetChangeEmitter,
 				this.healingModelChangeEmitter,
+				this.humanErrorModelChangeEmitter,
 				this.epWeightsChangeEmitter,
 				this.epRatiosChangeEmitter,
 				this.epRefStatChangeEmitter,
@@ -1095,6 +1111,19 @@ export class Player<SpecType extends Spec> {
 		this.healingModelChangeEmitter.emit(eventID);
 	}
 
+	getHumanErrorModel(): HumanErrorModel {
+		// Make a defensive copy
+		return HumanErrorModel.clone(this.humanErrorModel);
+	}
+
+	setHumanErrorModel(eventID: EventID, newHumanErrorModel: HumanErrorModel) {
+		if (HumanErrorModel.equals(this.humanErrorModel, newHumanErrorModel)) return;
+
+		// Make a defensive copy
+		this.humanErrorModel = HumanErrorModel.clone(newHumanErrorModel);
+		this.humanErrorModelChangeEmitter.emit(eventID);
+	}
+
 	computeStatsEP(stats?: Stats): number {
 		if (stats == undefined) {
 			return 0;
@@ -1526,6 +1555,7 @@ export class Player<SpecType extends Spec> {
 				inFrontOfTarget: this.getInFrontOfTarget(),
 				distanceFromTarget: this.getDistanceFromTarget(),
 				healingModel: this.getHealingModel(),
+				humanError: this.getHumanErrorModel(),
 				challengeMode: this.getChallengeModeEnabled(),
 			});
 			player = withSpec(this.getSpec(), player, this.getSpecOptions());
@@ -1582,6 +1612,7 @@ export class Player<SpecType extends Spec> {
 				this.setInFrontOfTarget(eventID, proto.inFrontOfTarget);
 				this.setDistanceFromTarget(eventID, proto.distanceFromTarget);
 				this.setHealingModel(eventID, proto.healingModel || HealingModel.create());
+				this.setHumanErrorModel(eventID, proto.humanError || defaultHumanErrorModel());
 				this.setChallengeModeEnabled(eventID, proto.challengeMode);
 			}
 			if (loadCategory(SimSettingCategories.External)) {
@@ -1606,6 +1637,7 @@ export class Player<SpecType extends Spec> {
 					burstWindow: this.playerSpec.isTankSpec ? 6 : 0,
 				}),
 			);
+			this.setHumanErrorModel(eventID, defaultHumanErrorModel());
 			this.setSimpleCooldowns(
 				eventID,
 				Cooldowns.create({
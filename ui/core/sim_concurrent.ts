@@ -227,6 +227,7 @@ export const runConcurrentStatWeights = async (
 	const id = generateRequestId(SimRequest.statWeightsAsync);
 
 	const manualResponse = await workerPool.statWeightRequests(request);
+	if (manualResponse.error) return makeAndSendWeightsError(manualResponse.error, onProgress);
 	manualResponse.baseRequest!.requestId = id;
 
 	if (signals.abort.isTriggered()) {